@@ -0,0 +1,74 @@
+package eventbridge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+	"github.com/pennsieve/publishing-service/api/config"
+	"github.com/pennsieve/publishing-service/api/events"
+	log "github.com/sirupsen/logrus"
+	"time"
+)
+
+// PutEventsAPI is the subset of the EventBridge client used by the Publisher
+type PutEventsAPI interface {
+	PutEvents(ctx context.Context, params *eventbridge.PutEventsInput, optFns ...func(*eventbridge.Options)) (*eventbridge.PutEventsOutput, error)
+}
+
+// MakePublisher creates an events.Publisher that puts events on the named EventBridge event bus
+func MakePublisher(client PutEventsAPI, eventBusName string, timeout time.Duration) *Publisher {
+	return &Publisher{
+		Client:       client,
+		EventBusName: eventBusName,
+		Timeout:      timeout,
+	}
+}
+
+type Publisher struct {
+	Client       PutEventsAPI
+	EventBusName string
+	Timeout      time.Duration
+}
+
+// Publish puts the event on the event bus, with the event's type as its detail-type
+func (p *Publisher) Publish(ctx context.Context, event events.Event) error {
+	log.WithFields(log.Fields{"id": event.Id, "type": event.Type, "eventBusName": p.EventBusName}).Info("Publisher.Publish()")
+
+	detail, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshalling event: %w", err)
+	}
+
+	ctx, cancel := config.WithTimeout(ctx, p.Timeout)
+	defer cancel()
+
+	result, err := p.Client.PutEvents(ctx, &eventbridge.PutEventsInput{
+		Entries: []types.PutEventsRequestEntry{{
+			EventBusName: aws.String(p.EventBusName),
+			Source:       aws.String(events.Source),
+			DetailType:   aws.String(event.Type),
+			Detail:       aws.String(string(detail)),
+			Time:         aws.Time(event.OccurredAt),
+		}},
+	})
+	if err != nil {
+		log.WithFields(log.Fields{"PutEvents": "failure", "error": fmt.Sprintf("%+v", err)}).Error("Publisher.Publish()")
+		return err
+	}
+
+	// PutEvents succeeds when entries fail; each failed entry has an error code
+	if result.FailedEntryCount > 0 {
+		for _, entry := range result.Entries {
+			if entry.ErrorCode != nil {
+				return fmt.Errorf("event was not put on %s: %s: %s", p.EventBusName, aws.ToString(entry.ErrorCode), aws.ToString(entry.ErrorMessage))
+			}
+		}
+		return fmt.Errorf("event was not put on %s", p.EventBusName)
+	}
+
+	log.WithFields(log.Fields{"PutEvents": "success", "id": event.Id}).Info("Publisher.Publish()")
+	return nil
+}
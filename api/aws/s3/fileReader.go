@@ -4,23 +4,21 @@ import (
 	"context"
 	"fmt"
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"io/ioutil"
 )
 
-func MakeFileReader() *FileReader {
-	cfg, err := config.LoadDefaultConfig(context.Background())
-	if err != nil {
-		// TODO: handle error
-	}
-	s3Client := s3.NewFromConfig(cfg)
+// GetObjectAPI is the subset of the S3 client used by the FileReader
+type GetObjectAPI interface {
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+}
 
+func MakeFileReader(s3Client GetObjectAPI) *FileReader {
 	return &FileReader{s3Client: s3Client}
 }
 
 type FileReader struct {
-	s3Client *s3.Client
+	s3Client GetObjectAPI
 }
 
 func (reader *FileReader) ReadFile(ctx context.Context, s3Bucket string, s3Key string) (string, error) {
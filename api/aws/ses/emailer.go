@@ -2,84 +2,159 @@ package ses
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ses"
 	"github.com/aws/aws-sdk-go-v2/service/ses/types"
 	sesTypes "github.com/pennsieve/publishing-service/api/aws/ses/types"
 	log "github.com/sirupsen/logrus"
+	"regexp"
+	"sort"
 )
 
-func MakeEmailer() *Emailer {
-	cfg, err := config.LoadDefaultConfig(context.Background())
-	if err != nil {
-		// TODO: handle error
+// maxDestinations is the most recipients SES accepts on one email
+const maxDestinations = 50
+
+// SendEmailAPI is the subset of the SES client used by the Emailer
+type SendEmailAPI interface {
+	SendEmail(ctx context.Context, params *ses.SendEmailInput, optFns ...func(*ses.Options)) (*ses.SendEmailOutput, error)
+}
+
+// Option configures the Emailer
+type Option func(emailer *Emailer)
+
+// WithDelivery sets how emails with more than one recipient are sent; the default is PerRecipient
+func WithDelivery(delivery sesTypes.Delivery) Option {
+	return func(emailer *Emailer) {
+		emailer.Delivery = delivery
 	}
+}
+
+// WithConfigurationSet sends emails with the SES configuration set, e.g. to publish their delivery events
+func WithConfigurationSet(name string) Option {
+	return func(emailer *Emailer) {
+		emailer.ConfigurationSet = name
+	}
+}
 
-	client := ses.NewFromConfig(cfg)
-	return &Emailer{
+func MakeEmailer(client SendEmailAPI, options ...Option) *Emailer {
+	emailer := &Emailer{
 		Client:  client,
 		CharSet: "UTF-8",
 	}
+	for _, option := range options {
+		option(emailer)
+	}
+	return emailer
 }
 
 type Emailer struct {
-	Client  *ses.Client
-	CharSet string
+	Client           SendEmailAPI
+	CharSet          string
+	Delivery         sesTypes.Delivery
+	ConfigurationSet string
 }
 
-func (emailer *Emailer) messageBody(body string, format sesTypes.MessageFormat) *types.Body {
-	switch format {
-	case sesTypes.HTML:
-		return &types.Body{
-			Html: &types.Content{
-				Data:    &body,
-				Charset: &emailer.CharSet,
-			},
-			Text: nil,
+func (emailer *Emailer) messageBody(body sesTypes.Body) *types.Body {
+	var message types.Body
+	if body.HTML != "" {
+		message.Html = &types.Content{
+			Data:    &body.HTML,
+			Charset: &emailer.CharSet,
 		}
-	case sesTypes.Text:
-		return &types.Body{
-			Html: nil,
-			Text: &types.Content{
-				Data:    &body,
-				Charset: &emailer.CharSet,
-			},
+	}
+	if body.Text != "" {
+		message.Text = &types.Content{
+			Data:    &body.Text,
+			Charset: &emailer.CharSet,
+		}
+	}
+	return &message
+}
+
+// invalidTagCharacters are those SES does not allow in the name or value of a message tag
+var invalidTagCharacters = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+func (emailer *Emailer) messageTags(tags map[string]string) []types.MessageTag {
+	var names []string
+	for name := range tags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var messageTags []types.MessageTag
+	for _, name := range names {
+		messageTags = append(messageTags, types.MessageTag{
+			Name:  aws.String(invalidTagCharacters.ReplaceAllString(name, "_")),
+			Value: aws.String(invalidTagCharacters.ReplaceAllString(tags[name], "_")),
+		})
+	}
+	return messageTags
+}
+
+// Send sends the email to its recipients, as the Emailer's Delivery says, so that no recipient sees the others'
+// addresses, and reports the result for each recipient. The error joins the failures, if there are any.
+func (emailer *Emailer) Send(ctx context.Context, email sesTypes.Email) ([]sesTypes.Result, error) {
+	var recipients []string
+	for _, recipient := range email.Recipients {
+		if recipient != "" {
+			recipients = append(recipients, recipient)
 		}
 	}
-	return nil
+
+	var results []sesTypes.Result
+	switch emailer.Delivery {
+	case sesTypes.BCC:
+		for start := 0; start < len(recipients); start += maxDestinations {
+			batch := recipients[start:min(start+maxDestinations, len(recipients))]
+			messageId, err := emailer.sendEmail(ctx, email, &types.Destination{BccAddresses: batch})
+			for _, recipient := range batch {
+				results = append(results, sesTypes.Result{Recipient: recipient, MessageId: messageId, Err: err})
+			}
+		}
+	default:
+		for _, recipient := range recipients {
+			messageId, err := emailer.sendEmail(ctx, email, &types.Destination{ToAddresses: []string{recipient}})
+			results = append(results, sesTypes.Result{Recipient: recipient, MessageId: messageId, Err: err})
+		}
+	}
+
+	var errs []error
+	for _, result := range results {
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", result.Recipient, result.Err))
+		}
+	}
+	return results, errors.Join(errs...)
 }
 
-func (emailer *Emailer) SendMessage(ctx context.Context, sender string, recipients []string, subject string, body string, format sesTypes.MessageFormat) error {
+// sendEmail sends one copy of the email to the destination, returning its SES message id
+func (emailer *Emailer) sendEmail(ctx context.Context, email sesTypes.Email, destination *types.Destination) (string, error) {
 	// compose email message
 	message := &ses.SendEmailInput{
-		Destination: &types.Destination{
-			BccAddresses: nil,
-			CcAddresses:  nil,
-			ToAddresses:  recipients,
-		},
+		Destination: destination,
 		Message: &types.Message{
-			Body: emailer.messageBody(body, format),
+			Body: emailer.messageBody(email.Body),
 			Subject: &types.Content{
-				Data:    &subject,
+				Data:    &email.Subject,
 				Charset: &emailer.CharSet,
 			},
 		},
-		Source:               &sender,
-		ConfigurationSetName: nil,
-		ReplyToAddresses:     nil,
-		ReturnPath:           nil,
-		ReturnPathArn:        nil,
-		SourceArn:            nil,
-		Tags:                 nil,
+		Source:           &email.Sender,
+		ReplyToAddresses: email.ReplyTo,
+		Tags:             emailer.messageTags(email.Tags),
+	}
+	if emailer.ConfigurationSet != "" {
+		message.ConfigurationSetName = &emailer.ConfigurationSet
 	}
 
 	result, err := emailer.Client.SendEmail(ctx, message)
 	if err != nil {
-		log.WithFields(log.Fields{"SendMessage": "failure", "error": fmt.Sprintf("%+v", err)}).Info("Emailer.SendMessage()")
-		return err
+		log.WithFields(log.Fields{"SendMessage": "failure", "destination": fmt.Sprintf("%+v", *destination), "error": fmt.Sprintf("%+v", err)}).Info("Emailer.sendEmail()")
+		return "", err
 	}
 
-	log.WithFields(log.Fields{"SendMessage": "success", "result": fmt.Sprintf("%+v", result)}).Info("Emailer.SendMessage()")
-	return nil
+	log.WithFields(log.Fields{"SendMessage": "success", "messageId": aws.ToString(result.MessageId)}).Info("Emailer.sendEmail()")
+	return aws.ToString(result.MessageId), nil
 }
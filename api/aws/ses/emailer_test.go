@@ -0,0 +1,121 @@
+package ses
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ses"
+	sesTypes "github.com/pennsieve/publishing-service/api/aws/ses/types"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// fakeSES records the emails sent, failing those to the addresses in fail
+type fakeSES struct {
+	sent []*ses.SendEmailInput
+	fail map[string]bool
+}
+
+func (f *fakeSES) SendEmail(ctx context.Context, params *ses.SendEmailInput, optFns ...func(*ses.Options)) (*ses.SendEmailOutput, error) {
+	f.sent = append(f.sent, params)
+	for _, address := range append(params.Destination.ToAddresses, params.Destination.BccAddresses...) {
+		if f.fail[address] {
+			return nil, errors.New("address is on the suppression list")
+		}
+	}
+	return &ses.SendEmailOutput{MessageId: aws.String(fmt.Sprintf("message-%d", len(f.sent)))}, nil
+}
+
+func testEmail(recipients ...string) sesTypes.Email {
+	return sesTypes.Email{
+		Sender:     "support@pennsieve.net",
+		Recipients: recipients,
+		ReplyTo:    []string{"curators@repository.org"},
+		Subject:    "A Dataset Proposal has been submitted",
+		Body:       sesTypes.Body{HTML: "<p>Submitted</p>", Text: "Submitted"},
+		Tags:       map[string]string{"notification": "ProposalSubmitted", "repository": "N:organization:1"},
+	}
+}
+
+func TestSendPerRecipient(t *testing.T) {
+	client := &fakeSES{fail: map[string]bool{"bounced@example.com": true}}
+	emailer := MakeEmailer(client, WithConfigurationSet("publishing"))
+
+	results, err := emailer.Send(context.Background(), testEmail("one@example.com", "", "bounced@example.com", "two@example.com"))
+	if err == nil || !strings.Contains(err.Error(), "bounced@example.com") {
+		t.Errorf("Send() error = %v, want the failure for bounced@example.com", err)
+	}
+
+	if len(client.sent) != 3 {
+		t.Fatalf("sent %d emails, want one to each recipient", len(client.sent))
+	}
+	for i, recipient := range []string{"one@example.com", "bounced@example.com", "two@example.com"} {
+		destination := client.sent[i].Destination
+		if !slices.Equal(destination.ToAddresses, []string{recipient}) || len(destination.CcAddresses) != 0 || len(destination.BccAddresses) != 0 {
+			t.Errorf("email %d destination = %+v, want only %s", i, destination, recipient)
+		}
+	}
+
+	want := []sesTypes.Result{
+		{Recipient: "one@example.com", MessageId: "message-1"},
+		{Recipient: "bounced@example.com"},
+		{Recipient: "two@example.com", MessageId: "message-3"},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("results = %+v, want %+v", results, want)
+	}
+	for i, result := range results {
+		if result.Recipient != want[i].Recipient || result.MessageId != want[i].MessageId || (result.Err != nil) != (want[i].MessageId == "") {
+			t.Errorf("result %d = %+v, want %+v", i, result, want[i])
+		}
+	}
+
+	input := client.sent[0]
+	if input.Message.Body.Html == nil || input.Message.Body.Text == nil || aws.ToString(input.Message.Body.Text.Data) != "Submitted" {
+		t.Errorf("body = %+v, want HTML and plain-text parts", input.Message.Body)
+	}
+	if !slices.Equal(input.ReplyToAddresses, []string{"curators@repository.org"}) || aws.ToString(input.ConfigurationSetName) != "publishing" {
+		t.Errorf("reply-to = %v, configuration set = %q, want those of the email and the Emailer", input.ReplyToAddresses, aws.ToString(input.ConfigurationSetName))
+	}
+	var tags []string
+	for _, tag := range input.Tags {
+		tags = append(tags, aws.ToString(tag.Name)+"="+aws.ToString(tag.Value))
+	}
+	if !slices.Equal(tags, []string{"notification=ProposalSubmitted", "repository=N_organization_1"}) {
+		t.Errorf("tags = %v, want the email's tags with the characters SES does not allow replaced", tags)
+	}
+}
+
+func TestSendBCC(t *testing.T) {
+	client := &fakeSES{}
+	emailer := MakeEmailer(client, WithDelivery(sesTypes.BCC))
+
+	var recipients []string
+	for i := 0; i < 120; i++ {
+		recipients = append(recipients, fmt.Sprintf("publisher%d@example.com", i))
+	}
+	results, err := emailer.Send(context.Background(), testEmail(recipients...))
+	if err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+
+	if len(client.sent) != 3 {
+		t.Fatalf("sent %d emails, want batches of %d", len(client.sent), maxDestinations)
+	}
+	var blindCopied []string
+	for _, input := range client.sent {
+		if len(input.Destination.ToAddresses) != 0 || len(input.Destination.CcAddresses) != 0 || len(input.Destination.BccAddresses) > maxDestinations {
+			t.Errorf("destination = %+v, want only up to %d blind copies", input.Destination, maxDestinations)
+		}
+		blindCopied = append(blindCopied, input.Destination.BccAddresses...)
+	}
+	if !slices.Equal(blindCopied, recipients) {
+		t.Errorf("blind-copied %v, want %v", blindCopied, recipients)
+	}
+
+	if len(results) != len(recipients) || results[0].MessageId != "message-1" || results[119].MessageId != "message-3" {
+		t.Errorf("results = %+v, want one for each recipient, with the message id of its batch", results)
+	}
+}
@@ -0,0 +1,8 @@
+package types
+
+// Body is the content of an email. When both parts are set, they are sent as alternatives, so that mail clients
+// that do not show HTML show the plain text.
+type Body struct {
+	HTML string
+	Text string
+}
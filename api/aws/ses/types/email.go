@@ -0,0 +1,31 @@
+package types
+
+// Email is a message to send with the Emailer. Tags are attached to the email's SES events, e.g. to tell the
+// notifications apart in the delivery metrics of a configuration set.
+type Email struct {
+	Sender     string
+	Recipients []string
+	ReplyTo    []string
+	Subject    string
+	Body       Body
+	Tags       map[string]string
+}
+
+// Delivery is how an email with more than one recipient is sent. Either way, the recipients do not see each
+// other's addresses.
+type Delivery int64
+
+const (
+	// PerRecipient sends each recipient their own copy of the email
+	PerRecipient Delivery = iota
+	// BCC sends one copy of the email, blind-copied to its recipients, in batches of up to the SES limit
+	BCC
+)
+
+// Result is the outcome of sending an email to one of its recipients. Recipients blind-copied on the same copy
+// share its MessageId and Err.
+type Result struct {
+	Recipient string
+	MessageId string
+	Err       error
+}
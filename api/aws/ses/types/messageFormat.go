@@ -1,8 +0,0 @@
-package types
-
-type MessageFormat int64
-
-const (
-	HTML MessageFormat = iota
-	Text
-)
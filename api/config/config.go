@@ -0,0 +1,266 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/ses"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultTemplateCacheTTL is how long an email template is cached before it is read again
+const DefaultTemplateCacheTTL = 15 * time.Minute
+
+// Tables are the names of the DynamoDB tables used by the Publishing Service
+type Tables struct {
+	Info                    string
+	Repositories            string
+	Questions               string
+	DatasetProposals        string
+	ProposalSearch          string
+	NotificationPreferences string
+	NotificationOutbox      string
+	Webhooks                string
+	WebhookDeliveries       string
+}
+
+// EmailTemplates locate the email templates in S3 used by the EmailNotifier
+type EmailTemplates struct {
+	// CacheTTL is how long the EmailNotifier keeps a template before reading it again
+	CacheTTL         time.Duration
+	Bucket           string
+	Submitted        string
+	Withdrawn        string
+	Accepted         string
+	Rejected         string
+	DraftReminder    string
+	ReviewReminder   string
+	ReviewEscalation string
+	Digest           string
+	// receipts to the author, and notices to contributors and workspace admins (see NotificationMatrix)
+	SubmittedReceipt    string
+	WithdrawnReceipt    string
+	ContributorAccepted string
+	AdminCopy           string
+}
+
+// Endpoints override the default AWS service endpoints, e.g. to point at DynamoDB Local or LocalStack.
+// An empty value uses the default endpoint for the region.
+type Endpoints struct {
+	DynamoDB    string
+	EventBridge string
+	S3          string
+	SES         string
+	SQS         string
+}
+
+// Config is the configuration of the Publishing Service. It is loaded once, at cold start.
+type Config struct {
+	Environment          string
+	Region               string
+	PennsieveDomain      string
+	Tables               Tables
+	EmailTemplates       EmailTemplates
+	Email                Email
+	EmailServiceQueueURL string
+	EventBusName         string
+	Endpoints            Endpoints
+	Timeouts             Timeouts
+	DraftExpiry          DraftExpiry
+	ReviewSLA            ReviewSLA
+	Outbox               Outbox
+	Webhooks             Webhooks
+	Notifications        NotificationMatrix
+}
+
+// Load reads the configuration from the environment and validates it
+func Load() (*Config, error) {
+	env := &environment{}
+	timeouts, timeoutsErr := LoadTimeouts()
+	draftExpiry, draftExpiryErr := LoadDraftExpiry()
+	reviewSLA, reviewSLAErr := LoadReviewSLA()
+	outbox, outboxErr := LoadOutbox()
+	webhooks, webhooksErr := LoadWebhooks()
+
+	cfg := &Config{
+		Environment:     os.Getenv("ENV"),
+		Region:          os.Getenv("REGION"),
+		PennsieveDomain: os.Getenv("PENNSIEVE_DOMAIN"),
+		Tables: Tables{
+			Info:                    os.Getenv("PUBLISHING_INFO_TABLE"),
+			Repositories:            os.Getenv("REPOSITORIES_TABLE"),
+			Questions:               os.Getenv("REPOSITORY_QUESTIONS_TABLE"),
+			DatasetProposals:        os.Getenv("DATASET_PROPOSAL_TABLE"),
+			ProposalSearch:          os.Getenv("PROPOSAL_SEARCH_TABLE"),
+			NotificationPreferences: os.Getenv("NOTIFICATION_PREFERENCES_TABLE"),
+			NotificationOutbox:      os.Getenv("NOTIFICATION_OUTBOX_TABLE"),
+			Webhooks:                os.Getenv("WEBHOOKS_TABLE"),
+			WebhookDeliveries:       os.Getenv("WEBHOOK_DELIVERIES_TABLE"),
+		},
+		EmailTemplates: EmailTemplates{
+			CacheTTL:         env.duration("EMAIL_TEMPLATE_CACHE_TTL", DefaultTemplateCacheTTL),
+			Bucket:           os.Getenv("EMAIL_TEMPLATE_BUCKET"),
+			Submitted:        os.Getenv("EMAIL_TEMPLATE_SUBMITTED"),
+			Withdrawn:        os.Getenv("EMAIL_TEMPLATE_WITHDRAWN"),
+			Accepted:         os.Getenv("EMAIL_TEMPLATE_ACCEPTED"),
+			Rejected:         os.Getenv("EMAIL_TEMPLATE_REJECTED"),
+			DraftReminder:    os.Getenv("EMAIL_TEMPLATE_DRAFT_REMINDER"),
+			ReviewReminder:   os.Getenv("EMAIL_TEMPLATE_REVIEW_REMINDER"),
+			ReviewEscalation: os.Getenv("EMAIL_TEMPLATE_REVIEW_ESCALATION"),
+			Digest:           os.Getenv("EMAIL_TEMPLATE_DIGEST"),
+
+			SubmittedReceipt:    os.Getenv("EMAIL_TEMPLATE_SUBMITTED_RECEIPT"),
+			WithdrawnReceipt:    os.Getenv("EMAIL_TEMPLATE_WITHDRAWN_RECEIPT"),
+			ContributorAccepted: os.Getenv("EMAIL_TEMPLATE_CONTRIBUTOR_ACCEPTED"),
+			AdminCopy:           os.Getenv("EMAIL_TEMPLATE_ADMIN_COPY"),
+		},
+		Email:                LoadEmail(),
+		EmailServiceQueueURL: os.Getenv("EMAIL_SERVICE_QUEUE_URL"),
+		EventBusName:         os.Getenv("EVENT_BUS_NAME"),
+		Endpoints: Endpoints{
+			DynamoDB:    os.Getenv("DYNAMODB_URL"),
+			EventBridge: os.Getenv("EVENTBRIDGE_URL"),
+			S3:          os.Getenv("S3_URL"),
+			SES:         os.Getenv("SES_URL"),
+			SQS:         os.Getenv("SQS_URL"),
+		},
+		Timeouts:    timeouts,
+		DraftExpiry: draftExpiry,
+		ReviewSLA:   reviewSLA,
+		Outbox:      outbox,
+		Webhooks:    webhooks,
+
+		Notifications: LoadNotificationMatrix(),
+	}
+
+	// a malformed setting fails the configuration, as a missing one does, rather than quietly using the default
+	if err := errors.Join(env.err(), timeoutsErr, draftExpiryErr, reviewSLAErr, outboxErr, webhooksErr); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// Validate reports every required setting that is missing
+func (c *Config) Validate() error {
+	required := []struct {
+		key   string
+		value string
+	}{
+		{"PENNSIEVE_DOMAIN", c.PennsieveDomain},
+		{"PUBLISHING_INFO_TABLE", c.Tables.Info},
+		{"REPOSITORIES_TABLE", c.Tables.Repositories},
+		{"REPOSITORY_QUESTIONS_TABLE", c.Tables.Questions},
+		{"DATASET_PROPOSAL_TABLE", c.Tables.DatasetProposals},
+		{"PROPOSAL_SEARCH_TABLE", c.Tables.ProposalSearch},
+		{"NOTIFICATION_PREFERENCES_TABLE", c.Tables.NotificationPreferences},
+		{"NOTIFICATION_OUTBOX_TABLE", c.Tables.NotificationOutbox},
+		{"WEBHOOKS_TABLE", c.Tables.Webhooks},
+		{"WEBHOOK_DELIVERIES_TABLE", c.Tables.WebhookDeliveries},
+		{"EMAIL_SERVICE_QUEUE_URL", c.EmailServiceQueueURL},
+		{"EVENT_BUS_NAME", c.EventBusName},
+		// the EmailNotifier renders every notification the email-service does not send, and their previews
+		{"EMAIL_TEMPLATE_BUCKET", c.EmailTemplates.Bucket},
+		{"EMAIL_TEMPLATE_SUBMITTED", c.EmailTemplates.Submitted},
+		{"EMAIL_TEMPLATE_WITHDRAWN", c.EmailTemplates.Withdrawn},
+		{"EMAIL_TEMPLATE_ACCEPTED", c.EmailTemplates.Accepted},
+		{"EMAIL_TEMPLATE_REJECTED", c.EmailTemplates.Rejected},
+		{"EMAIL_TEMPLATE_DRAFT_REMINDER", c.EmailTemplates.DraftReminder},
+		{"EMAIL_TEMPLATE_REVIEW_REMINDER", c.EmailTemplates.ReviewReminder},
+		{"EMAIL_TEMPLATE_REVIEW_ESCALATION", c.EmailTemplates.ReviewEscalation},
+		{"EMAIL_TEMPLATE_DIGEST", c.EmailTemplates.Digest},
+		{"EMAIL_TEMPLATE_SUBMITTED_RECEIPT", c.EmailTemplates.SubmittedReceipt},
+		{"EMAIL_TEMPLATE_WITHDRAWN_RECEIPT", c.EmailTemplates.WithdrawnReceipt},
+		{"EMAIL_TEMPLATE_CONTRIBUTOR_ACCEPTED", c.EmailTemplates.ContributorAccepted},
+		{"EMAIL_TEMPLATE_ADMIN_COPY", c.EmailTemplates.AdminCopy},
+	}
+
+	var missing []string
+	for _, setting := range required {
+		if setting.value == "" {
+			missing = append(missing, setting.key)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required configuration: %s", strings.Join(missing, ", "))
+	}
+
+	if err := c.Email.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.DraftExpiry.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.ReviewSLA.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Notifications.Validate(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Clients are the AWS service clients shared by all requests
+type Clients struct {
+	DynamoDB    *dynamodb.Client
+	EventBridge *eventbridge.Client
+	S3          *s3.Client
+	SES         *ses.Client
+	SQS         *sqs.Client
+}
+
+// NewClients creates the AWS service clients, honoring any endpoint overrides in the configuration
+func NewClients(ctx context.Context, cfg *Config) (*Clients, error) {
+	var options []func(*awsconfig.LoadOptions) error
+	if cfg.Region != "" {
+		options = append(options, awsconfig.WithRegion(cfg.Region))
+	}
+
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("loading AWS config: %w", err)
+	}
+
+	return &Clients{
+		DynamoDB: dynamodb.NewFromConfig(awsCfg, func(o *dynamodb.Options) {
+			o.BaseEndpoint = endpoint(cfg.Endpoints.DynamoDB)
+		}),
+		EventBridge: eventbridge.NewFromConfig(awsCfg, func(o *eventbridge.Options) {
+			o.BaseEndpoint = endpoint(cfg.Endpoints.EventBridge)
+		}),
+		S3: s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+			o.BaseEndpoint = endpoint(cfg.Endpoints.S3)
+			// LocalStack serves buckets from the path rather than a subdomain
+			o.UsePathStyle = cfg.Endpoints.S3 != ""
+		}),
+		SES: ses.NewFromConfig(awsCfg, func(o *ses.Options) {
+			o.BaseEndpoint = endpoint(cfg.Endpoints.SES)
+		}),
+		SQS: sqs.NewFromConfig(awsCfg, func(o *sqs.Options) {
+			o.BaseEndpoint = endpoint(cfg.Endpoints.SQS)
+		}),
+	}, nil
+}
+
+func endpoint(url string) *string {
+	if url == "" {
+		return nil
+	}
+	return aws.String(url)
+}
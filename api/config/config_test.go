@@ -0,0 +1,65 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+// setRequiredEnv sets every required setting
+func setRequiredEnv(t *testing.T) {
+	t.Helper()
+	for _, key := range []string{
+		"PENNSIEVE_DOMAIN", "PUBLISHING_INFO_TABLE", "REPOSITORIES_TABLE", "REPOSITORY_QUESTIONS_TABLE",
+		"DATASET_PROPOSAL_TABLE", "PROPOSAL_SEARCH_TABLE", "NOTIFICATION_PREFERENCES_TABLE", "NOTIFICATION_OUTBOX_TABLE",
+		"WEBHOOKS_TABLE", "WEBHOOK_DELIVERIES_TABLE", "EMAIL_SERVICE_QUEUE_URL", "EVENT_BUS_NAME",
+		"EMAIL_TEMPLATE_BUCKET", "EMAIL_TEMPLATE_SUBMITTED", "EMAIL_TEMPLATE_WITHDRAWN", "EMAIL_TEMPLATE_ACCEPTED",
+		"EMAIL_TEMPLATE_REJECTED", "EMAIL_TEMPLATE_DRAFT_REMINDER", "EMAIL_TEMPLATE_REVIEW_REMINDER",
+		"EMAIL_TEMPLATE_REVIEW_ESCALATION", "EMAIL_TEMPLATE_DIGEST", "EMAIL_TEMPLATE_SUBMITTED_RECEIPT",
+		"EMAIL_TEMPLATE_WITHDRAWN_RECEIPT", "EMAIL_TEMPLATE_CONTRIBUTOR_ACCEPTED", "EMAIL_TEMPLATE_ADMIN_COPY",
+	} {
+		t.Setenv(key, strings.ToLower(key))
+	}
+}
+
+func TestLoad(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("DRAFT_REMINDER_AFTER", "240h")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.DraftExpiry.ReminderAfter.Hours() != 240 || cfg.Outbox != DefaultOutbox() {
+		t.Errorf("Load() = %+v, want the configured draft reminder and the default outbox", cfg)
+	}
+}
+
+func TestLoadFailsOnMalformedSettings(t *testing.T) {
+	tests := map[string]string{
+		"DRAFT_REMINDER_AFTER":     "14",
+		"REVIEW_ESCALATE_AFTER":    "a month",
+		"OUTBOX_MAX_ATTEMPTS":      "0",
+		"WEBHOOK_TIMEOUT":          "5",
+		"EMAIL_TEMPLATE_CACHE_TTL": "15",
+		"DYNAMODB_TIMEOUT":         "-",
+	}
+	for key, value := range tests {
+		t.Run(key, func(t *testing.T) {
+			setRequiredEnv(t)
+			t.Setenv(key, value)
+
+			if _, err := Load(); err == nil || !strings.Contains(err.Error(), key) {
+				t.Errorf("Load() error = %v, want %s reported", err, key)
+			}
+		})
+	}
+}
+
+func TestLoadRequiresEmailTemplates(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("EMAIL_TEMPLATE_ADMIN_COPY", "")
+
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "EMAIL_TEMPLATE_ADMIN_COPY") {
+		t.Errorf("Load() error = %v, want EMAIL_TEMPLATE_ADMIN_COPY reported missing", err)
+	}
+}
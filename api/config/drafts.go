@@ -0,0 +1,57 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	// DraftExpiryArchive marks expired drafts ARCHIVED; DynamoDB removes them once their TTL passes
+	DraftExpiryArchive = "archive"
+	// DraftExpiryDelete deletes expired drafts
+	DraftExpiryDelete = "delete"
+)
+
+const (
+	DefaultDraftReminderAfter    = 60 * 24 * time.Hour
+	DefaultDraftExpiryGrace      = 14 * 24 * time.Hour
+	DefaultDraftArchiveRetention = 90 * 24 * time.Hour
+)
+
+// DraftExpiry is the policy of the scheduled job that cleans up abandoned draft proposals.
+// A draft untouched for ReminderAfter is sent a reminder; if it is still untouched Grace after
+// the reminder, it is expired by Action. Archived drafts are kept for ArchiveRetention.
+type DraftExpiry struct {
+	ReminderAfter    time.Duration
+	Grace            time.Duration
+	ArchiveRetention time.Duration
+	Action           string
+}
+
+// LoadDraftExpiry reads the draft expiry policy from the environment, e.g. DRAFT_REMINDER_AFTER=720h
+func LoadDraftExpiry() (DraftExpiry, error) {
+	action := os.Getenv("DRAFT_EXPIRY_ACTION")
+	if action == "" {
+		action = DraftExpiryArchive
+	}
+
+	env := &environment{}
+	draftExpiry := DraftExpiry{
+		ReminderAfter:    env.duration("DRAFT_REMINDER_AFTER", DefaultDraftReminderAfter),
+		Grace:            env.duration("DRAFT_EXPIRY_GRACE", DefaultDraftExpiryGrace),
+		ArchiveRetention: env.duration("DRAFT_ARCHIVE_RETENTION", DefaultDraftArchiveRetention),
+		Action:           action,
+	}
+	return draftExpiry, env.err()
+}
+
+// Validate reports an unknown expiry action
+func (d DraftExpiry) Validate() error {
+	switch d.Action {
+	case DraftExpiryArchive, DraftExpiryDelete:
+		return nil
+	default:
+		return fmt.Errorf("invalid DRAFT_EXPIRY_ACTION %q: must be %q or %q", d.Action, DraftExpiryArchive, DraftExpiryDelete)
+	}
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"fmt"
+	"os"
+)
+
+// Deliveries of an email with more than one recipient
+const (
+	// EmailDeliveryPerRecipient sends each recipient their own copy
+	EmailDeliveryPerRecipient = "per-recipient"
+	// EmailDeliveryBCC sends one copy, blind-copied to the recipients
+	EmailDeliveryBCC = "bcc"
+)
+
+// Email is how the EmailNotifier sends email with SES. Either Delivery keeps the recipients' addresses from each
+// other. ConfigurationSet, if set, is the SES configuration set the emails are sent with, e.g. to publish their
+// delivery, bounce and complaint events.
+type Email struct {
+	Delivery         string
+	ConfigurationSet string
+	LogoBaseURL      string
+}
+
+// LoadEmail reads the email settings from the environment, e.g. EMAIL_DELIVERY=bcc
+func LoadEmail() Email {
+	delivery := os.Getenv("EMAIL_DELIVERY")
+	if delivery == "" {
+		delivery = EmailDeliveryPerRecipient
+	}
+
+	return Email{
+		Delivery:         delivery,
+		ConfigurationSet: os.Getenv("SES_CONFIGURATION_SET"),
+		LogoBaseURL:      os.Getenv("EMAIL_LOGO_BASE_URL"),
+	}
+}
+
+// Validate reports an unknown delivery
+func (e Email) Validate() error {
+	switch e.Delivery {
+	case EmailDeliveryPerRecipient, EmailDeliveryBCC:
+		return nil
+	default:
+		return fmt.Errorf("invalid EMAIL_DELIVERY %q: must be %s or %s", e.Delivery, EmailDeliveryPerRecipient, EmailDeliveryBCC)
+	}
+}
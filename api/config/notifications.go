@@ -0,0 +1,95 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Lifecycle transitions of a Dataset Proposal that send notifications
+const (
+	TransitionSubmitted = "submitted"
+	TransitionWithdrawn = "withdrawn"
+	TransitionAccepted  = "accepted"
+	TransitionRejected  = "rejected"
+)
+
+// Audiences that may be notified of a transition
+const (
+	// AudiencePublishers is the publishing team of the Repository, other than those who get a digest
+	AudiencePublishers = "publishers"
+	// AudienceAuthor is the proposal's owner: a receipt on submit and withdraw, the decision on accept and reject
+	AudienceAuthor = "author"
+	// AudienceContributors are the Contributors listed on the proposal, other than its owner
+	AudienceContributors = "contributors"
+	// AudienceWorkspaceAdmins are the admins of the Repository's workspace, sent a copy of the transition
+	AudienceWorkspaceAdmins = "admins"
+)
+
+// supportedAudiences are the audiences there is a notification for, for each transition
+var supportedAudiences = map[string][]string{
+	TransitionSubmitted: {AudiencePublishers, AudienceAuthor, AudienceWorkspaceAdmins},
+	TransitionWithdrawn: {AudiencePublishers, AudienceAuthor, AudienceWorkspaceAdmins},
+	TransitionAccepted:  {AudienceAuthor, AudienceContributors, AudienceWorkspaceAdmins},
+	TransitionRejected:  {AudienceAuthor, AudienceWorkspaceAdmins},
+}
+
+// NotificationMatrix is who is notified of each lifecycle transition of a Dataset Proposal
+type NotificationMatrix map[string][]string
+
+// DefaultNotificationMatrix notifies everyone but the workspace admins
+func DefaultNotificationMatrix() NotificationMatrix {
+	return NotificationMatrix{
+		TransitionSubmitted: {AudiencePublishers, AudienceAuthor},
+		TransitionWithdrawn: {AudiencePublishers, AudienceAuthor},
+		TransitionAccepted:  {AudienceAuthor, AudienceContributors},
+		TransitionRejected:  {AudienceAuthor},
+	}
+}
+
+// LoadNotificationMatrix reads the notification matrix from the environment, as semicolon-separated transitions,
+// each with a comma-separated list of audiences, e.g. NOTIFICATION_MATRIX="submitted=publishers,author,admins;rejected=".
+// A transition that is not listed keeps its default audiences; one listed without audiences notifies no one.
+func LoadNotificationMatrix() NotificationMatrix {
+	matrix := DefaultNotificationMatrix()
+
+	for _, entry := range strings.Split(os.Getenv("NOTIFICATION_MATRIX"), ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		transition, value, _ := strings.Cut(entry, "=")
+		var audiences []string
+		for _, audience := range strings.Split(value, ",") {
+			if audience = strings.TrimSpace(audience); audience != "" {
+				audiences = append(audiences, strings.ToLower(audience))
+			}
+		}
+		matrix[strings.ToLower(strings.TrimSpace(transition))] = audiences
+	}
+
+	return matrix
+}
+
+// Notifies reports whether the audience is notified of the transition
+func (m NotificationMatrix) Notifies(transition string, audience string) bool {
+	return slices.Contains(m[transition], audience)
+}
+
+// Validate reports unknown transitions, and audiences that there is no notification of the transition for
+func (m NotificationMatrix) Validate() error {
+	for transition, audiences := range m {
+		supported, found := supportedAudiences[transition]
+		if !found {
+			return fmt.Errorf("invalid NOTIFICATION_MATRIX: unknown transition %q", transition)
+		}
+		for _, audience := range audiences {
+			if !slices.Contains(supported, audience) {
+				return fmt.Errorf("invalid NOTIFICATION_MATRIX: %q cannot be notified of %q (must be one of %s)", audience, transition, strings.Join(supported, ", "))
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"time"
+)
+
+// Outbox is the delivery policy of the notification outbox. A message that is not delivered to every
+// recipient is retried after RetryBase, doubling after each attempt up to RetryMax, and a recipient
+// is given up on (dead) after MaxAttempts. A message being delivered is leased for Lease, so that it
+// is not delivered twice; delivered messages are kept for Retention.
+type Outbox struct {
+	MaxAttempts int
+	RetryBase   time.Duration
+	RetryMax    time.Duration
+	Lease       time.Duration
+	Retention   time.Duration
+}
+
+// DefaultOutbox is the outbox delivery policy used when none is configured
+func DefaultOutbox() Outbox {
+	return Outbox{
+		MaxAttempts: 8,
+		RetryBase:   time.Minute,
+		RetryMax:    6 * time.Hour,
+		Lease:       2 * time.Minute,
+		Retention:   30 * 24 * time.Hour,
+	}
+}
+
+// LoadOutbox reads the outbox delivery policy from the environment, e.g. OUTBOX_MAX_ATTEMPTS=10
+func LoadOutbox() (Outbox, error) {
+	env := &environment{}
+	defaults := DefaultOutbox()
+	outbox := Outbox{
+		MaxAttempts: env.integer("OUTBOX_MAX_ATTEMPTS", defaults.MaxAttempts),
+		RetryBase:   env.duration("OUTBOX_RETRY_BASE", defaults.RetryBase),
+		RetryMax:    env.duration("OUTBOX_RETRY_MAX", defaults.RetryMax),
+		Lease:       env.duration("OUTBOX_LEASE", defaults.Lease),
+		Retention:   env.duration("OUTBOX_RETENTION", defaults.Retention),
+	}
+	return outbox, env.err()
+}
+
+// Backoff is the delay before the next delivery of a message that has been attempted the given number of times
+func (o Outbox) Backoff(attempts int) time.Duration {
+	backoff := o.RetryBase
+	for i := 1; i < attempts && backoff < o.RetryMax; i++ {
+		backoff *= 2
+	}
+	return min(backoff, o.RetryMax)
+}
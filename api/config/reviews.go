@@ -0,0 +1,37 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	DefaultReviewReminderAfter = 14 * 24 * time.Hour
+	DefaultReviewEscalateAfter = 30 * 24 * time.Hour
+)
+
+// ReviewSLA is the default policy of the scheduled job that chases SUBMITTED proposals awaiting review.
+// A Repository's publishers are sent a digest of the proposals submitted at least ReminderAfter ago,
+// and its workspace admins of those submitted at least EscalateAfter ago. A Repository may override both.
+type ReviewSLA struct {
+	ReminderAfter time.Duration
+	EscalateAfter time.Duration
+}
+
+// LoadReviewSLA reads the review SLA from the environment, e.g. REVIEW_REMINDER_AFTER=336h
+func LoadReviewSLA() (ReviewSLA, error) {
+	env := &environment{}
+	sla := ReviewSLA{
+		ReminderAfter: env.duration("REVIEW_REMINDER_AFTER", DefaultReviewReminderAfter),
+		EscalateAfter: env.duration("REVIEW_ESCALATE_AFTER", DefaultReviewEscalateAfter),
+	}
+	return sla, env.err()
+}
+
+// Validate reports an escalation threshold that comes before the reminder
+func (r ReviewSLA) Validate() error {
+	if r.EscalateAfter < r.ReminderAfter {
+		return fmt.Errorf("invalid REVIEW_ESCALATE_AFTER %s: must not be less than REVIEW_REMINDER_AFTER %s", r.EscalateAfter, r.ReminderAfter)
+	}
+	return nil
+}
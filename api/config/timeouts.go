@@ -0,0 +1,84 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+const DefaultTimeout = 10 * time.Second
+
+// Timeouts are the per-call deadlines applied to requests made to backing services.
+// A zero Timeout means that only the deadline of the parent context applies.
+type Timeouts struct {
+	DynamoDB    time.Duration
+	EventBridge time.Duration
+	RDS         time.Duration
+	SQS         time.Duration
+}
+
+// LoadTimeouts reads the per-call timeouts from the environment, e.g. DYNAMODB_TIMEOUT=5s
+func LoadTimeouts() (Timeouts, error) {
+	env := &environment{}
+	timeouts := Timeouts{
+		DynamoDB:    env.duration("DYNAMODB_TIMEOUT", DefaultTimeout),
+		EventBridge: env.duration("EVENTBRIDGE_TIMEOUT", DefaultTimeout),
+		RDS:         env.duration("RDS_TIMEOUT", DefaultTimeout),
+		SQS:         env.duration("SQS_TIMEOUT", DefaultTimeout),
+	}
+	return timeouts, env.err()
+}
+
+// environment reads typed settings from the environment, collecting the errors of those that are malformed, so
+// that a setting such as DRAFT_REMINDER_AFTER=14 fails the configuration instead of quietly using the default
+type environment struct {
+	errs []error
+}
+
+// duration reads a setting such as 720h; an unset setting is the default
+func (e *environment) duration(key string, defaultValue time.Duration) time.Duration {
+	value, found := os.LookupEnv(key)
+	if !found || value == "" {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("invalid %s %q: must be a duration, e.g. 720h", key, value))
+		return defaultValue
+	}
+
+	return duration
+}
+
+// integer reads a setting that must be a positive integer; an unset setting is the default
+func (e *environment) integer(key string, defaultValue int) int {
+	value, found := os.LookupEnv(key)
+	if !found || value == "" {
+		return defaultValue
+	}
+
+	i, err := strconv.Atoi(value)
+	if err != nil || i <= 0 {
+		e.errs = append(e.errs, fmt.Errorf("invalid %s %q: must be a positive integer", key, value))
+		return defaultValue
+	}
+
+	return i
+}
+
+// err reports every malformed setting read
+func (e *environment) err() error {
+	return errors.Join(e.errs...)
+}
+
+// WithTimeout derives a context for a single call to a backing service
+func WithTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultWebhookTimeout           = 10 * time.Second
+	DefaultWebhookDeliveryRetention = 30 * 24 * time.Hour
+)
+
+// Webhooks is the policy for delivering events to the Webhooks registered by Repositories. Each request
+// to an endpoint is given Timeout, and the log of delivery attempts is kept for DeliveryRetention.
+// Endpoints on private and loopback addresses are refused unless AllowPrivateAddresses is set, so that
+// a Webhook cannot be used to reach services inside the VPC; it is set when running locally.
+type Webhooks struct {
+	Timeout               time.Duration
+	DeliveryRetention     time.Duration
+	AllowPrivateAddresses bool
+}
+
+// DefaultWebhooks is the webhook delivery policy used when none is configured
+func DefaultWebhooks() Webhooks {
+	return Webhooks{
+		Timeout:           DefaultWebhookTimeout,
+		DeliveryRetention: DefaultWebhookDeliveryRetention,
+	}
+}
+
+// LoadWebhooks reads the webhook delivery policy from the environment, e.g. WEBHOOK_TIMEOUT=5s
+func LoadWebhooks() (Webhooks, error) {
+	allowPrivate, _ := strconv.ParseBool(os.Getenv("WEBHOOK_ALLOW_PRIVATE_ADDRESSES"))
+
+	env := &environment{}
+	webhooks := Webhooks{
+		Timeout:               env.duration("WEBHOOK_TIMEOUT", DefaultWebhookTimeout),
+		DeliveryRetention:     env.duration("WEBHOOK_DELIVERY_RETENTION", DefaultWebhookDeliveryRetention),
+		AllowPrivateAddresses: allowPrivate,
+	}
+	return webhooks, env.err()
+}
@@ -1,9 +1,9 @@
 package dtos
 
 import (
+	"context"
 	"github.com/pennsieve/publishing-service/api/aws/s3"
 	"github.com/pennsieve/publishing-service/api/models"
-	"time"
 )
 
 func BuildQuestionDTO(question models.Question) QuestionDTO {
@@ -44,24 +44,35 @@ func BuildContributor(contributor ContributorDTO) models.Contributor {
 	}
 }
 
-func BuildInfoDTO(info models.Info) InfoDTO {
-	presigner := s3.MakePresigner()
+// presignedURL returns a URL to get the object that is valid for 12 hours, or the empty string
+// if the URL could not be presigned
+func presignedURL(ctx context.Context, presigner s3.ObjectPresigner, location models.S3Location) string {
+	if presigner == nil {
+		return ""
+	}
 
-	file, _ := presigner.GetObject(
-		info.File.S3Bucket,
-		info.File.S3Key,
+	request, err := presigner.GetObject(ctx,
+		location.S3Bucket,
+		location.S3Key,
 		12*3600, // 12 hours
 	)
+	if err != nil {
+		return ""
+	}
+
+	return request.URL
+}
 
+func BuildInfoDTO(ctx context.Context, presigner s3.ObjectPresigner, info models.Info) InfoDTO {
 	return InfoDTO{
 		Tag:  info.Tag,
 		Type: info.Type,
-		URL:  file.URL,
+		URL:  presignedURL(ctx, presigner, info.File),
 	}
 }
 
 // TODO: can we better abstract the type for questionMap?
-func BuildRepositoryDTO(repository models.Repository, questionMap map[int]QuestionDTO) RepositoryDTO {
+func BuildRepositoryDTO(ctx context.Context, presigner s3.ObjectPresigner, repository models.Repository, questionMap map[int]QuestionDTO) RepositoryDTO {
 	// build list of selected Questions for the Repository
 	var questionDTOs []QuestionDTO
 	for i := 0; i < len(repository.Questions); i++ {
@@ -69,20 +80,6 @@ func BuildRepositoryDTO(repository models.Repository, questionMap map[int]Questi
 		questionDTOs = append(questionDTOs, questionMap[questionNumber])
 	}
 
-	presigner := s3.MakePresigner()
-
-	overviewDocument, _ := presigner.GetObject(
-		repository.OverviewDocument.S3Bucket,
-		repository.OverviewDocument.S3Key,
-		12*3600, // 12 hours
-	)
-
-	logoFile, _ := presigner.GetObject(
-		repository.LogoFile.S3Bucket,
-		repository.LogoFile.S3Key,
-		12*3600, // 12 hours
-	)
-
 	return RepositoryDTO{
 		OrganizationNodeId:  repository.OrganizationNodeId,
 		Name:                repository.Name,
@@ -90,8 +87,8 @@ func BuildRepositoryDTO(repository models.Repository, questionMap map[int]Questi
 		Type:                repository.Type,
 		Description:         repository.Description,
 		URL:                 repository.URL,
-		OverviewDocumentUrl: overviewDocument.URL,
-		LogoFileUrl:         logoFile.URL,
+		OverviewDocumentUrl: presignedURL(ctx, presigner, repository.OverviewDocument),
+		LogoFileUrl:         presignedURL(ctx, presigner, repository.LogoFile),
 		Questions:           questionDTOs,
 		CreatedAt:           repository.CreatedAt,
 		UpdatedAt:           repository.UpdatedAt,
@@ -127,6 +124,7 @@ func BuildDatasetProposalDTO(proposal *models.DatasetProposal) DatasetProposalDT
 		WithdrawnAt:        proposal.WithdrawnAt,
 		AcceptedAt:         proposal.AcceptedAt,
 		RejectedAt:         proposal.RejectedAt,
+		Version:            proposal.Version,
 	}
 }
 
@@ -141,8 +139,6 @@ func BuildDatasetProposal(dto DatasetProposalDTO) *models.DatasetProposal {
 		contributors = append(contributors, BuildContributor(dto.Contributors[i]))
 	}
 
-	currentTime := time.Now().Unix()
-
 	proposal := &models.DatasetProposal{
 		UserId:             dto.UserId,
 		NodeId:             dto.NodeId,
@@ -151,11 +147,17 @@ func BuildDatasetProposal(dto DatasetProposalDTO) *models.DatasetProposal {
 		Name:               dto.Name,
 		Description:        dto.Description,
 		OrganizationNodeId: dto.OrganizationNodeId,
+		DatasetNodeId:      dto.DatasetNodeId,
 		ProposalStatus:     dto.ProposalStatus,
 		Survey:             survey,
 		Contributors:       contributors,
-		CreatedAt:          currentTime,
-		UpdatedAt:          currentTime,
+		CreatedAt:          dto.CreatedAt,
+		UpdatedAt:          dto.UpdatedAt,
+		SubmittedAt:        dto.SubmittedAt,
+		WithdrawnAt:        dto.WithdrawnAt,
+		AcceptedAt:         dto.AcceptedAt,
+		RejectedAt:         dto.RejectedAt,
+		Version:            dto.Version,
 	}
 
 	return proposal
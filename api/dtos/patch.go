@@ -0,0 +1,123 @@
+package dtos
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// DatasetProposalPatchRequest is a JSON Merge Patch (RFC 7386) of the client-writable fields of a
+// Dataset Proposal. Absent fields are left unchanged and a null description is cleared. The survey
+// and contributors may be replaced with an array, or patched one entry at a time with an object:
+//
+//	{"survey": {"3": "new response", "4": null}}               // answer question 3, remove the answer to 4
+//	{"contributors": {"0": {"lastName": "Smith"}, "2": null}}  // update contributor 0, remove contributor 2
+//
+// A contributor index equal to the number of contributors appends a new contributor.
+type DatasetProposalPatchRequest struct {
+	Name         Optional[string]  `json:"name"`
+	Description  Optional[string]  `json:"description"`
+	Survey       SurveyPatch       `json:"survey"`
+	Contributors ContributorsPatch `json:"contributors"`
+}
+
+// Optional is a field of a patch that distinguishes an absent value from an explicit null
+type Optional[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	if isNull(data) {
+		o.Null = true
+		return nil
+	}
+	return decodeStrict(data, &o.Value)
+}
+
+// SurveyPatch replaces the survey (Replace), or sets and removes answers by question id (Answers, nil to remove)
+type SurveyPatch struct {
+	Set     bool
+	Replace []SurveyDTO
+	Answers map[int]*string
+}
+
+func (p *SurveyPatch) UnmarshalJSON(data []byte) error {
+	p.Set = true
+	if isNull(data) {
+		return nil
+	}
+	if isArray(data) {
+		return decodeStrict(data, &p.Replace)
+	}
+
+	var answers map[string]*string
+	if err := decodeStrict(data, &answers); err != nil {
+		return err
+	}
+	p.Answers = make(map[int]*string)
+	for key, response := range answers {
+		questionId, err := strconv.Atoi(key)
+		if err != nil {
+			return fmt.Errorf("survey: invalid question id %q", key)
+		}
+		p.Answers[questionId] = response
+	}
+	return nil
+}
+
+// ContributorPatch holds the contributor fields to change
+type ContributorPatch struct {
+	FirstName    *string `json:"firstName"`
+	LastName     *string `json:"lastName"`
+	EmailAddress *string `json:"emailAddress"`
+}
+
+// ContributorsPatch replaces the contributors (Replace), or patches and removes contributors by index (Changes, nil to remove)
+type ContributorsPatch struct {
+	Set     bool
+	Replace []ContributorDTO
+	Changes map[int]*ContributorPatch
+}
+
+func (p *ContributorsPatch) UnmarshalJSON(data []byte) error {
+	p.Set = true
+	if isNull(data) {
+		return nil
+	}
+	if isArray(data) {
+		return decodeStrict(data, &p.Replace)
+	}
+
+	var changes map[string]*ContributorPatch
+	if err := decodeStrict(data, &changes); err != nil {
+		return err
+	}
+	p.Changes = make(map[int]*ContributorPatch)
+	for key, change := range changes {
+		index, err := strconv.Atoi(key)
+		if err != nil || index < 0 {
+			return fmt.Errorf("contributors: invalid index %q", key)
+		}
+		p.Changes[index] = change
+	}
+	return nil
+}
+
+func isNull(data []byte) bool {
+	return bytes.Equal(bytes.TrimSpace(data), []byte("null"))
+}
+
+func isArray(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte("["))
+}
+
+// decodeStrict decodes nested patch values, rejecting unknown fields like the request body itself
+func decodeStrict(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
@@ -0,0 +1,11 @@
+package dtos
+
+type NotificationPreferenceDTO struct {
+	Frequency    string `json:"frequency"`
+	LastDigestAt int64  `json:"lastDigestAt"`
+	UpdatedAt    int64  `json:"updatedAt"`
+}
+
+type NotificationPreferenceRequest struct {
+	Frequency string `json:"frequency"`
+}
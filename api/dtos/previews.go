@@ -0,0 +1,10 @@
+package dtos
+
+// NotificationPreviewDTO is a notification's email for a Dataset Proposal, rendered as it would be sent
+type NotificationPreviewDTO struct {
+	Notification string   `json:"notification"`
+	Subject      string   `json:"subject"`
+	HTML         string   `json:"html"`
+	Text         string   `json:"text"`
+	ReplyTo      []string `json:"replyTo"`
+}
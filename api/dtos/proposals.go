@@ -2,6 +2,7 @@ package dtos
 
 type SurveyDTO struct {
 	QuestionId int    `json:"questionId"`
+	Question   string `json:"question,omitempty"`
 	Response   string `json:"response"`
 }
 
@@ -23,9 +24,11 @@ type DatasetProposalDTO struct {
 	WithdrawnAt        int64            `json:"withdrawnAt"`
 	AcceptedAt         int64            `json:"acceptedAt"`
 	RejectedAt         int64            `json:"rejectedAt"`
+	Version            int64            `json:"version"`
 }
 
 type DatasetSubmissionsDTO struct {
 	TotalCount int                  `json:"totalCount"`
+	Counts     map[string]int       `json:"counts"`
 	Proposals  []DatasetProposalDTO `json:"proposals"`
 }
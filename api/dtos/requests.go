@@ -0,0 +1,34 @@
+package dtos
+
+// DatasetProposalCreateRequest holds the client-writable fields of a new Dataset Proposal
+type DatasetProposalCreateRequest struct {
+	Name               string           `json:"name"`
+	Description        string           `json:"description"`
+	OrganizationNodeId string           `json:"organizationNodeId"`
+	Survey             []SurveyDTO      `json:"survey"`
+	Contributors       []ContributorDTO `json:"contributors"`
+}
+
+// DatasetProposalUpdateRequest holds the client-writable fields of an existing Dataset Proposal.
+// NodeId identifies the Dataset Proposal on the deprecated PUT /proposal route, which has no path parameter.
+// Version is the version of the Dataset Proposal the update was made from; the update fails if it has since changed.
+type DatasetProposalUpdateRequest struct {
+	NodeId       string           `json:"nodeId,omitempty"`
+	Version      *int64           `json:"version,omitempty"`
+	Name         string           `json:"name"`
+	Description  string           `json:"description"`
+	Survey       []SurveyDTO      `json:"survey"`
+	Contributors []ContributorDTO `json:"contributors"`
+}
+
+// FieldErrorDTO describes why the value of one field of a request is invalid
+type FieldErrorDTO struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationErrorDTO is the response body for a request that failed validation
+type ValidationErrorDTO struct {
+	Message string          `json:"message"`
+	Errors  []FieldErrorDTO `json:"errors"`
+}
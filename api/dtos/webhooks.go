@@ -0,0 +1,32 @@
+package dtos
+
+// WebhookDTO is a Repository's Webhook. Its Secret is only returned when the Webhook is created.
+type WebhookDTO struct {
+	Id        string   `json:"id"`
+	URL       string   `json:"url"`
+	Events    []string `json:"events"`
+	Active    bool     `json:"active"`
+	Secret    string   `json:"secret,omitempty"`
+	CreatedAt int64    `json:"createdAt"`
+	UpdatedAt int64    `json:"updatedAt"`
+}
+
+// WebhookRequest registers, or replaces, a Webhook. No Events subscribes to every event; Active defaults to true.
+type WebhookRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	Active *bool    `json:"active"`
+}
+
+type WebhookDeliveryDTO struct {
+	Id             string `json:"id"`
+	EventId        string `json:"eventId"`
+	EventType      string `json:"eventType"`
+	ProposalNodeId string `json:"proposalNodeId"`
+	Attempt        int    `json:"attempt"`
+	StatusCode     int    `json:"statusCode"`
+	Error          string `json:"error,omitempty"`
+	Succeeded      bool   `json:"succeeded"`
+	DurationMillis int64  `json:"durationMillis"`
+	AttemptedAt    int64  `json:"attemptedAt"`
+}
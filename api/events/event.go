@@ -0,0 +1,77 @@
+// Package events publishes Dataset Proposal lifecycle events to the Pennsieve event bus, so that other services
+// (discover, analytics) learn of proposals, and of the datasets created for them, without polling DynamoDB.
+// Events are written to the outbox with the proposal change they report, and delivered by a Publisher.
+package events
+
+import (
+	_ "embed"
+	"github.com/google/uuid"
+	"github.com/pennsieve/publishing-service/api/models"
+	"time"
+)
+
+// Source is the source of every event published by the Publishing Service
+const Source = "pennsieve.publishing-service"
+
+// SchemaVersion is the version of the event schema. Adding an optional field keeps the version; removing,
+// renaming or changing the meaning of a field is a new version, which consumers match on before reading an event.
+const SchemaVersion = "1"
+
+// Schema is the JSON Schema of the events of SchemaVersion
+//
+//go:embed schema/proposal-event-v1.json
+var Schema []byte
+
+// Event types, one for each lifecycle transition of a Dataset Proposal; an event's type is its detail-type
+const (
+	ProposalCreated   = "ProposalCreated"
+	ProposalSubmitted = "ProposalSubmitted"
+	ProposalWithdrawn = "ProposalWithdrawn"
+	ProposalAccepted  = "ProposalAccepted"
+	ProposalRejected  = "ProposalRejected"
+)
+
+// Types are the event types that are published
+var Types = []string{ProposalCreated, ProposalSubmitted, ProposalWithdrawn, ProposalAccepted, ProposalRejected}
+
+// Event is the detail of a published event. Its Id is the same on every attempt to publish it,
+// so that a consumer can ignore a repeated event.
+type Event struct {
+	SchemaVersion string        `json:"schemaVersion"`
+	Id            string        `json:"id"`
+	Type          string        `json:"type"`
+	OccurredAt    time.Time     `json:"occurredAt"`
+	Proposal      EventProposal `json:"proposal"`
+}
+
+// EventProposal is the Dataset Proposal, as it is after the transition. DatasetNodeId is set once it is accepted.
+type EventProposal struct {
+	NodeId             string `json:"nodeId"`
+	UserId             int    `json:"userId"`
+	OrganizationNodeId string `json:"organizationNodeId"`
+	Name               string `json:"name"`
+	Status             string `json:"status"`
+	DatasetNodeId      string `json:"datasetNodeId,omitempty"`
+	CreatedAt          int64  `json:"createdAt"`
+	UpdatedAt          int64  `json:"updatedAt"`
+}
+
+// NewEvent creates the event of the given type for the proposal, as it is after the transition
+func NewEvent(eventType string, proposal *models.DatasetProposal, now time.Time) Event {
+	return Event{
+		SchemaVersion: SchemaVersion,
+		Id:            uuid.NewString(),
+		Type:          eventType,
+		OccurredAt:    now.UTC(),
+		Proposal: EventProposal{
+			NodeId:             proposal.NodeId,
+			UserId:             proposal.UserId,
+			OrganizationNodeId: proposal.OrganizationNodeId,
+			Name:               proposal.Name,
+			Status:             proposal.ProposalStatus,
+			DatasetNodeId:      proposal.DatasetNodeId,
+			CreatedAt:          proposal.CreatedAt,
+			UpdatedAt:          proposal.UpdatedAt,
+		},
+	}
+}
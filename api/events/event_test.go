@@ -0,0 +1,92 @@
+package events
+
+import (
+	"encoding/json"
+	"github.com/pennsieve/publishing-service/api/models"
+	"slices"
+	"testing"
+	"time"
+)
+
+// schemaObject is the part of a JSON Schema object definition checked by the tests
+type schemaObject struct {
+	Required   []string                   `json:"required"`
+	Properties map[string]json.RawMessage `json:"properties"`
+}
+
+type schemaProperty struct {
+	Enum []string `json:"enum"`
+}
+
+func parseSchema(t *testing.T) (schemaObject, schemaObject, map[string]schemaProperty) {
+	t.Helper()
+
+	var event schemaObject
+	if err := json.Unmarshal(Schema, &event); err != nil {
+		t.Fatalf("parsing schema: %v", err)
+	}
+	var proposal schemaObject
+	if err := json.Unmarshal(event.Properties["proposal"], &proposal); err != nil {
+		t.Fatalf("parsing proposal schema: %v", err)
+	}
+	properties := make(map[string]schemaProperty)
+	for name, raw := range event.Properties {
+		var property schemaProperty
+		if err := json.Unmarshal(raw, &property); err != nil {
+			t.Fatalf("parsing property %s: %v", name, err)
+		}
+		properties[name] = property
+	}
+	return event, proposal, properties
+}
+
+// checkObject reports fields that are required by the schema but missing, and fields that the schema does not declare
+func checkObject(t *testing.T, name string, schema schemaObject, object map[string]json.RawMessage) {
+	t.Helper()
+	for _, field := range schema.Required {
+		if _, found := object[field]; !found {
+			t.Errorf("%s is missing required field %s", name, field)
+		}
+	}
+	for field := range object {
+		if _, found := schema.Properties[field]; !found {
+			t.Errorf("%s has field %s, which is not in the schema", name, field)
+		}
+	}
+}
+
+func TestEventMatchesSchema(t *testing.T) {
+	eventSchema, proposalSchema, properties := parseSchema(t)
+
+	if !slices.Equal(properties["schemaVersion"].Enum, []string{SchemaVersion}) {
+		t.Errorf("schema version enum = %v, want [%s]", properties["schemaVersion"].Enum, SchemaVersion)
+	}
+	if !slices.Equal(properties["type"].Enum, Types) {
+		t.Errorf("schema type enum = %v, want %v", properties["type"].Enum, Types)
+	}
+
+	proposals := map[string]*models.DatasetProposal{
+		"draft":    {UserId: 1, NodeId: "N:proposal:1", Name: "Proposal", OrganizationNodeId: "N:organization:1", ProposalStatus: "DRAFT", CreatedAt: 1000, UpdatedAt: 1000},
+		"accepted": {UserId: 1, NodeId: "N:proposal:1", Name: "Proposal", OrganizationNodeId: "N:organization:1", ProposalStatus: "ACCEPTED", DatasetNodeId: "N:dataset:1", CreatedAt: 1000, UpdatedAt: 2000},
+	}
+	for name, proposal := range proposals {
+		t.Run(name, func(t *testing.T) {
+			payload, err := json.Marshal(NewEvent(ProposalAccepted, proposal, time.Now()))
+			if err != nil {
+				t.Fatalf("marshalling event: %v", err)
+			}
+
+			var event map[string]json.RawMessage
+			if err := json.Unmarshal(payload, &event); err != nil {
+				t.Fatalf("unmarshalling event: %v", err)
+			}
+			checkObject(t, "event", eventSchema, event)
+
+			var eventProposal map[string]json.RawMessage
+			if err := json.Unmarshal(event["proposal"], &eventProposal); err != nil {
+				t.Fatalf("unmarshalling event proposal: %v", err)
+			}
+			checkObject(t, "event proposal", proposalSchema, eventProposal)
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package events
+
+import (
+	"context"
+)
+
+// Publisher publishes events to an event bus, such as EventBridge or an SNS topic. Delivery is at least once:
+// an event that may have been published is published again when the attempt fails.
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
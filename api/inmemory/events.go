@@ -0,0 +1,46 @@
+package inmemory
+
+import (
+	"context"
+	"github.com/pennsieve/publishing-service/api/events"
+	"sync"
+)
+
+// NewEventPublisher creates an events.Publisher that records every event instead of publishing it
+func NewEventPublisher() *EventPublisher {
+	return &EventPublisher{}
+}
+
+// EventPublisher is a recording events.Publisher. If Err is set, it is returned from
+// every call, after the event has been recorded.
+type EventPublisher struct {
+	mu        sync.Mutex
+	published []events.Event
+	Err       error
+}
+
+// Published returns the events recorded so far, in the order they were published
+func (p *EventPublisher) Published() []events.Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]events.Event(nil), p.published...)
+}
+
+// Reset forgets the events recorded so far
+func (p *EventPublisher) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.published = nil
+}
+
+func (p *EventPublisher) Publish(ctx context.Context, event events.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.published = append(p.published, event)
+	return p.Err
+}
+
+var _ events.Publisher = (*EventPublisher)(nil)
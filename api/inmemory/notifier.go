@@ -0,0 +1,109 @@
+package inmemory
+
+import (
+	"context"
+	"github.com/pennsieve/publishing-service/api/notification"
+	"sync"
+)
+
+// SentNotification is a notification recorded by the Notifier
+type SentNotification struct {
+	Notification      notification.Notification
+	MessageAttributes notification.MessageAttributes
+	Recipients        []string
+}
+
+// NewNotifier creates a notification.Notifier that records every notification instead of sending it
+func NewNotifier() *Notifier {
+	return &Notifier{}
+}
+
+// Notifier is a recording notification.Notifier. If Err is set, it is returned from
+// every call, after the notification has been recorded.
+type Notifier struct {
+	mu   sync.Mutex
+	sent []SentNotification
+	Err  error
+}
+
+// Sent returns the notifications recorded so far, in the order they were sent
+func (n *Notifier) Sent() []SentNotification {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return append([]SentNotification(nil), n.sent...)
+}
+
+// Reset forgets the notifications recorded so far
+func (n *Notifier) Reset() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.sent = nil
+}
+
+func (n *Notifier) record(action notification.Notification, messageAttributes notification.MessageAttributes, recipients []string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	attributes := make(notification.MessageAttributes, len(messageAttributes))
+	for key, value := range messageAttributes {
+		attributes[key] = value
+	}
+	n.sent = append(n.sent, SentNotification{
+		Notification:      action,
+		MessageAttributes: attributes,
+		Recipients:        append([]string(nil), recipients...),
+	})
+	return n.Err
+}
+
+func (n *Notifier) ProposalSubmitted(ctx context.Context, messageAttributes notification.MessageAttributes, recipients []string) error {
+	return n.record(notification.Submitted, messageAttributes, recipients)
+}
+
+func (n *Notifier) ProposalWithdrawn(ctx context.Context, messageAttributes notification.MessageAttributes, recipients []string) error {
+	return n.record(notification.Withdrawn, messageAttributes, recipients)
+}
+
+func (n *Notifier) ProposalAccepted(ctx context.Context, messageAttributes notification.MessageAttributes, recipients []string) error {
+	return n.record(notification.Accepted, messageAttributes, recipients)
+}
+
+func (n *Notifier) ProposalRejected(ctx context.Context, messageAttributes notification.MessageAttributes, recipients []string) error {
+	return n.record(notification.Rejected, messageAttributes, recipients)
+}
+
+func (n *Notifier) ProposalDraftReminder(ctx context.Context, messageAttributes notification.MessageAttributes, recipients []string) error {
+	return n.record(notification.DraftReminder, messageAttributes, recipients)
+}
+
+func (n *Notifier) ProposalReviewReminder(ctx context.Context, messageAttributes notification.MessageAttributes, recipients []string) error {
+	return n.record(notification.ReviewReminder, messageAttributes, recipients)
+}
+
+func (n *Notifier) ProposalReviewEscalation(ctx context.Context, messageAttributes notification.MessageAttributes, recipients []string) error {
+	return n.record(notification.ReviewEscalation, messageAttributes, recipients)
+}
+
+func (n *Notifier) ProposalDigest(ctx context.Context, messageAttributes notification.MessageAttributes, recipients []string) error {
+	return n.record(notification.Digest, messageAttributes, recipients)
+}
+
+func (n *Notifier) ProposalSubmittedReceipt(ctx context.Context, messageAttributes notification.MessageAttributes, recipients []string) error {
+	return n.record(notification.SubmittedReceipt, messageAttributes, recipients)
+}
+
+func (n *Notifier) ProposalWithdrawnReceipt(ctx context.Context, messageAttributes notification.MessageAttributes, recipients []string) error {
+	return n.record(notification.WithdrawnReceipt, messageAttributes, recipients)
+}
+
+func (n *Notifier) ProposalContributorAccepted(ctx context.Context, messageAttributes notification.MessageAttributes, recipients []string) error {
+	return n.record(notification.ContributorAccepted, messageAttributes, recipients)
+}
+
+func (n *Notifier) ProposalAdminCopy(ctx context.Context, messageAttributes notification.MessageAttributes, recipients []string) error {
+	return n.record(notification.AdminCopy, messageAttributes, recipients)
+}
+
+var _ notification.Notifier = (*Notifier)(nil)
@@ -0,0 +1,218 @@
+package inmemory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/google/uuid"
+	pgdbModels "github.com/pennsieve/pennsieve-go-core/pkg/models/pgdb"
+	"github.com/pennsieve/publishing-service/api/models"
+	"github.com/pennsieve/publishing-service/api/store"
+	"sync"
+	"time"
+)
+
+const welcomeWorkspaceSlug = "welcome_to_pennsieve"
+
+// NewPennsieveStore creates an in-memory store.PennsievePublishingStore for the workspace orgId.
+// Like the Postgres store, datasets created for accepted proposals are created in that workspace.
+func NewPennsieveStore(orgId int64) *PennsieveStore {
+	return &PennsieveStore{
+		orgId:           orgId,
+		users:           make(map[int64]pgdbModels.User),
+		organizations:   make(map[int64]pgdbModels.Organization),
+		publishingTeams: make(map[int64]models.PublishingTeam),
+		publishers:      make(map[string][]models.Publisher),
+		admins:          make(map[string][]models.WorkspaceAdmin),
+		datasetTeams:    make(map[int64][]int64),
+	}
+}
+
+// PennsieveStore is an in-memory store.PennsievePublishingStore
+type PennsieveStore struct {
+	mu              sync.RWMutex
+	orgId           int64
+	users           map[int64]pgdbModels.User
+	organizations   map[int64]pgdbModels.Organization
+	publishingTeams map[int64]models.PublishingTeam
+	publishers      map[string][]models.Publisher
+	admins          map[string][]models.WorkspaceAdmin
+	datasets        []pgdbModels.Dataset
+	datasetTeams    map[int64][]int64
+}
+
+// AddUser seeds (or replaces) a Pennsieve user
+func (p *PennsieveStore) AddUser(user pgdbModels.User) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.users[user.Id] = user
+}
+
+// AddOrganization seeds (or replaces) a Pennsieve workspace. A workspace with the slug
+// "welcome_to_pennsieve" is returned by GetWelcomeWorkspace.
+func (p *PennsieveStore) AddOrganization(organization pgdbModels.Organization) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.organizations[organization.Id] = organization
+}
+
+// AddPublishingTeam seeds the publishers team of a workspace
+func (p *PennsieveStore) AddPublishingTeam(team models.PublishingTeam) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	team.SystemTeamType = store.SystemTeamTypePublishers
+	p.publishingTeams[team.WorkspaceId] = team
+}
+
+// AddPublisher seeds a member of the publishers team of the workspace with the given NodeId
+func (p *PennsieveStore) AddPublisher(orgNodeId string, publisher models.Publisher) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.publishers[orgNodeId] = append(p.publishers[orgNodeId], publisher)
+}
+
+// AddWorkspaceAdmin seeds an administrator of the workspace with the given NodeId
+func (p *PennsieveStore) AddWorkspaceAdmin(orgNodeId string, admin models.WorkspaceAdmin) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.admins[orgNodeId] = append(p.admins[orgNodeId], admin)
+}
+
+// Datasets returns the datasets created for accepted proposals
+func (p *PennsieveStore) Datasets() []pgdbModels.Dataset {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return append([]pgdbModels.Dataset(nil), p.datasets...)
+}
+
+// DatasetTeams returns the ids of the teams that have been added to the dataset
+func (p *PennsieveStore) DatasetTeams(datasetId int64) []int64 {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return append([]int64(nil), p.datasetTeams[datasetId]...)
+}
+
+func (p *PennsieveStore) GetProposalUser(ctx context.Context, userId int64) (*pgdbModels.User, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	user, found := p.users[userId]
+	if !found {
+		return nil, sql.ErrNoRows
+	}
+	return &user, nil
+}
+
+func (p *PennsieveStore) organizationByNodeId(nodeId string) (*pgdbModels.Organization, error) {
+	for _, organization := range p.organizations {
+		if organization.NodeId == nodeId {
+			return &organization, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (p *PennsieveStore) GetRepositoryWorkspace(ctx context.Context, repository *models.Repository) (*pgdbModels.Organization, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.organizationByNodeId(repository.OrganizationNodeId)
+}
+
+func (p *PennsieveStore) GetWelcomeWorkspace(ctx context.Context) (*pgdbModels.Organization, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	for _, organization := range p.organizations {
+		if organization.Slug == welcomeWorkspaceSlug {
+			return &organization, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (p *PennsieveStore) GetPublishingTeam(ctx context.Context, workspaceId int64) (*models.PublishingTeam, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	team, found := p.publishingTeams[workspaceId]
+	if !found {
+		return nil, sql.ErrNoRows
+	}
+	return &team, nil
+}
+
+func (p *PennsieveStore) AddPublishingTeamToDataset(ctx context.Context, publishingTeam *models.PublishingTeam, dataset *pgdbModels.Dataset) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.datasetTeams[dataset.Id] = append(p.datasetTeams[dataset.Id], publishingTeam.TeamId)
+	return nil
+}
+
+func (p *PennsieveStore) GetPublishingTeamMembers(ctx context.Context, repository *models.Repository) ([]models.Publisher, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return append([]models.Publisher(nil), p.publishers[repository.OrganizationNodeId]...), nil
+}
+
+func (p *PennsieveStore) GetWorkspaceAdmins(ctx context.Context, repository *models.Repository) ([]models.WorkspaceAdmin, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return append([]models.WorkspaceAdmin(nil), p.admins[repository.OrganizationNodeId]...), nil
+}
+
+func (p *PennsieveStore) CreateDatasetForAcceptedProposal(ctx context.Context, proposal *models.DatasetProposal) (*store.CreatedDataset, error) {
+	user, err := p.GetProposalUser(ctx, int64(proposal.UserId))
+	if err != nil {
+		return nil, fmt.Errorf("failed to GetUserById id: %d (error: %+v)", proposal.UserId, err)
+	}
+
+	p.mu.RLock()
+	organization, found := p.organizations[p.orgId]
+	p.mu.RUnlock()
+	if !found {
+		return nil, fmt.Errorf("failed to GetOrganization id: %d (error: %+v)", p.orgId, sql.ErrNoRows)
+	}
+
+	publishingTeam, err := p.GetPublishingTeam(ctx, organization.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to GetPublishingTeam (error: %+v)", err)
+	}
+
+	p.mu.Lock()
+	now := time.Now()
+	dataset := pgdbModels.Dataset{
+		Id:        int64(len(p.datasets) + 1),
+		Name:      proposal.Name,
+		State:     "READY",
+		NodeId:    sql.NullString{String: fmt.Sprintf("N:dataset:%s", uuid.NewString()), Valid: true},
+		Type:      "research",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	p.datasets = append(p.datasets, dataset)
+	p.mu.Unlock()
+
+	err = p.AddPublishingTeamToDataset(ctx, publishingTeam, &dataset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &store.CreatedDataset{
+		User:         user,
+		Organization: &organization,
+		Dataset:      &dataset,
+	}, nil
+}
+
+var _ store.PennsievePublishingStore = (*PennsieveStore)(nil)
@@ -0,0 +1,556 @@
+// Package inmemory provides thread-safe, in-memory implementations of the Publishing Service stores
+// and notifier, so that the service can be exercised in plain `go test` without DynamoDB or Postgres.
+package inmemory
+
+import (
+	"context"
+	"fmt"
+	"github.com/pennsieve/publishing-service/api/models"
+	"github.com/pennsieve/publishing-service/api/store"
+	"maps"
+	"sort"
+	"sync"
+)
+
+// NewPublishingStore creates an empty in-memory store.PublishingStore
+func NewPublishingStore() *PublishingStore {
+	return &PublishingStore{
+		repositories: make(map[string]models.Repository),
+		questions:    make(map[int]models.Question),
+		proposals:    make(map[models.DatasetProposalKey]models.DatasetProposal),
+		preferences:  make(map[int64]models.NotificationPreference),
+		outbox:       make(map[string]models.OutboxMessage),
+		webhooks:     make(map[webhookKey]models.Webhook),
+		deliveries:   make(map[string][]models.WebhookDelivery),
+		search:       store.NewLocalSearchIndex(),
+	}
+}
+
+// PublishingStore is an in-memory store.PublishingStore. Dataset Proposals are keyed by (UserId, NodeId),
+// like the proposals table, and queries by Repository and status follow the semantics of the
+// RepositoryProposalStatusIndex GSI: both key attributes are required on every write.
+type PublishingStore struct {
+	mu           sync.RWMutex
+	info         []models.Info
+	repositories map[string]models.Repository
+	questions    map[int]models.Question
+	proposals    map[models.DatasetProposalKey]models.DatasetProposal
+	preferences  map[int64]models.NotificationPreference
+	outbox       map[string]models.OutboxMessage
+	webhooks     map[webhookKey]models.Webhook
+	deliveries   map[string][]models.WebhookDelivery
+	search       store.ProposalSearchIndex
+}
+
+// webhookKey is the key of the webhooks table
+type webhookKey struct {
+	organizationNodeId string
+	id                 string
+}
+
+// AddInfo seeds a Publishing Info item
+func (s *PublishingStore) AddInfo(info models.Info) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.info = append(s.info, info)
+}
+
+// AddRepository seeds (or replaces) a Repository
+func (s *PublishingStore) AddRepository(repository models.Repository) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.repositories[repository.OrganizationNodeId] = copyRepository(repository)
+}
+
+// copyRepository returns a deep copy, so that callers cannot modify stored Repositories
+func copyRepository(repository models.Repository) models.Repository {
+	repository.Questions = append([]int(nil), repository.Questions...)
+	if repository.ReviewSLA != nil {
+		sla := *repository.ReviewSLA
+		repository.ReviewSLA = &sla
+	}
+	if repository.EmailBranding != nil {
+		branding := *repository.EmailBranding
+		branding.Subjects = maps.Clone(branding.Subjects)
+		branding.Templates = maps.Clone(branding.Templates)
+		repository.EmailBranding = &branding
+	}
+	return repository
+}
+
+// AddQuestion seeds (or replaces) a Repository Question
+func (s *PublishingStore) AddQuestion(question models.Question) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.questions[question.Id] = question
+}
+
+// copyProposal returns a deep copy, so that callers cannot modify stored Dataset Proposals
+func copyProposal(proposal models.DatasetProposal) models.DatasetProposal {
+	proposal.Survey = append([]models.Survey(nil), proposal.Survey...)
+	proposal.Contributors = append([]models.Contributor(nil), proposal.Contributors...)
+	return proposal
+}
+
+func keyOf(proposal *models.DatasetProposal) models.DatasetProposalKey {
+	return models.DatasetProposalKey{UserId: proposal.UserId, NodeId: proposal.NodeId}
+}
+
+// sortedProposals returns copies of the matching Dataset Proposals, ordered by NodeId (the table's range key)
+func (s *PublishingStore) sortedProposals(match func(proposal *models.DatasetProposal) bool) []models.DatasetProposal {
+	var results []models.DatasetProposal
+	for _, proposal := range s.proposals {
+		if match(&proposal) {
+			results = append(results, copyProposal(proposal))
+		}
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].NodeId < results[j].NodeId })
+	return results
+}
+
+func (s *PublishingStore) GetInfo(ctx context.Context) ([]models.Info, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	info := append([]models.Info(nil), s.info...)
+	sort.SliceStable(info, func(i, j int) bool {
+		if info[i].Tag != info[j].Tag {
+			return info[i].Tag < info[j].Tag
+		}
+		return info[i].Type < info[j].Type
+	})
+	return info, nil
+}
+
+func (s *PublishingStore) GetRepositories(ctx context.Context) ([]models.Repository, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var repositories []models.Repository
+	for _, repository := range s.repositories {
+		repositories = append(repositories, copyRepository(repository))
+	}
+	sort.SliceStable(repositories, func(i, j int) bool {
+		if repositories[i].DisplayName != repositories[j].DisplayName {
+			return repositories[i].DisplayName < repositories[j].DisplayName
+		}
+		return repositories[i].OrganizationNodeId < repositories[j].OrganizationNodeId
+	})
+	return repositories, nil
+}
+
+func (s *PublishingStore) GetRepository(ctx context.Context, organizationNodeId string) (*models.Repository, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	repository, found := s.repositories[organizationNodeId]
+	if !found {
+		return nil, store.ErrNotFound
+	}
+	repository = copyRepository(repository)
+	return &repository, nil
+}
+
+func (s *PublishingStore) GetQuestions(ctx context.Context) ([]models.Question, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var questions []models.Question
+	for _, question := range s.questions {
+		questions = append(questions, question)
+	}
+	sort.Slice(questions, func(i, j int) bool { return questions[i].Id < questions[j].Id })
+	return questions, nil
+}
+
+func (s *PublishingStore) GetDatasetProposal(ctx context.Context, userId int, nodeId string) (*models.DatasetProposal, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	proposal, found := s.proposals[models.DatasetProposalKey{UserId: userId, NodeId: nodeId}]
+	if !found {
+		return nil, store.ErrNotFound
+	}
+	proposal = copyProposal(proposal)
+	return &proposal, nil
+}
+
+func (s *PublishingStore) GetDatasetProposalsForUser(ctx context.Context, userId int64) ([]models.DatasetProposal, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.sortedProposals(func(proposal *models.DatasetProposal) bool {
+		return int64(proposal.UserId) == userId
+	}), nil
+}
+
+func (s *PublishingStore) GetDatasetProposalsForWorkspace(ctx context.Context, orgNodeId string, status string) ([]models.DatasetProposal, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.sortedProposals(func(proposal *models.DatasetProposal) bool {
+		return proposal.OrganizationNodeId == orgNodeId && proposal.ProposalStatus == status
+	}), nil
+}
+
+func (s *PublishingStore) GetDatasetProposalByNodeId(ctx context.Context, nodeId string) (*models.DatasetProposal, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	results := s.sortedProposals(func(proposal *models.DatasetProposal) bool {
+		return proposal.NodeId == nodeId
+	})
+	if len(results) == 0 {
+		return nil, store.ErrNotFound
+	}
+	if len(results) > 1 {
+		return nil, fmt.Errorf("singleton get returned more than one item")
+	}
+	return &results[0], nil
+}
+
+// validate enforces the key attributes of the table and of the RepositoryProposalStatusIndex GSI;
+// DynamoDB rejects empty strings for index key attributes
+func validate(proposal *models.DatasetProposal) error {
+	if proposal.NodeId == "" {
+		return fmt.Errorf("ValidationException: missing key attribute NodeId")
+	}
+	if proposal.OrganizationNodeId == "" || proposal.ProposalStatus == "" {
+		return fmt.Errorf("ValidationException: empty string for key attribute of index RepositoryProposalStatusIndex")
+	}
+	return nil
+}
+
+// put stores the Dataset Proposal
+func (s *PublishingStore) put(ctx context.Context, proposal *models.DatasetProposal) error {
+	if err := validate(proposal); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.proposals[keyOf(proposal)] = copyProposal(*proposal)
+	s.mu.Unlock()
+
+	return s.search.Index(ctx, proposal)
+}
+
+func (s *PublishingStore) CreateDatasetProposal(ctx context.Context, proposal *models.DatasetProposal) (*models.DatasetProposal, error) {
+	if err := s.put(ctx, proposal); err != nil {
+		return nil, err
+	}
+	return proposal, nil
+}
+
+func (s *PublishingStore) UpdateDatasetProposal(ctx context.Context, proposal *models.DatasetProposal) (*models.DatasetProposal, error) {
+	if err := validate(proposal); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if stored, found := s.proposals[keyOf(proposal)]; !found || stored.Version != proposal.Version {
+		s.mu.Unlock()
+		return nil, store.ErrConflict
+	}
+	proposal.Version++
+	s.proposals[keyOf(proposal)] = copyProposal(*proposal)
+	s.mu.Unlock()
+
+	if err := s.search.Index(ctx, proposal); err != nil {
+		return nil, err
+	}
+	return proposal, nil
+}
+
+func (s *PublishingStore) DeleteDatasetProposal(ctx context.Context, proposal *models.DatasetProposal) error {
+	s.mu.Lock()
+	delete(s.proposals, keyOf(proposal))
+	s.mu.Unlock()
+
+	return s.search.Remove(ctx, proposal)
+}
+
+// updateProposal changes the stored Dataset Proposal, and the given copy of it, provided that the stored proposal
+// matches; otherwise store.ErrConflict is returned. The Version is incremented, as by the DynamoDB store.
+func (s *PublishingStore) updateProposal(proposal *models.DatasetProposal, match func(stored *models.DatasetProposal) bool, change func(proposal *models.DatasetProposal)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, found := s.proposals[keyOf(proposal)]
+	if !found || !match(&stored) {
+		return store.ErrConflict
+	}
+	change(&stored)
+	stored.Version++
+	s.proposals[keyOf(proposal)] = stored
+
+	change(proposal)
+	proposal.Version++
+	return nil
+}
+
+// draftUnchanged matches a stored Dataset Proposal that is still a DRAFT, and has not been changed since the draft was read
+func draftUnchanged(draft *models.DatasetProposal) func(stored *models.DatasetProposal) bool {
+	return func(stored *models.DatasetProposal) bool {
+		return stored.ProposalStatus == "DRAFT" && stored.UpdatedAt == draft.UpdatedAt
+	}
+}
+
+func (s *PublishingStore) RecordDraftReminder(ctx context.Context, draft *models.DatasetProposal, remindedAt int64) error {
+	return s.updateProposal(draft, draftUnchanged(draft), func(proposal *models.DatasetProposal) {
+		proposal.ReminderSentAt = remindedAt
+	})
+}
+
+func (s *PublishingStore) ArchiveDraft(ctx context.Context, draft *models.DatasetProposal, expiresAt int64) error {
+	err := s.updateProposal(draft, draftUnchanged(draft), func(proposal *models.DatasetProposal) {
+		proposal.ProposalStatus = "ARCHIVED"
+		proposal.ExpiresAt = expiresAt
+	})
+	if err != nil {
+		return err
+	}
+	return s.search.Index(ctx, draft)
+}
+
+func (s *PublishingStore) DeleteDraft(ctx context.Context, draft *models.DatasetProposal) error {
+	s.mu.Lock()
+	stored, found := s.proposals[keyOf(draft)]
+	if !found || !draftUnchanged(draft)(&stored) {
+		s.mu.Unlock()
+		return store.ErrConflict
+	}
+	delete(s.proposals, keyOf(draft))
+	s.mu.Unlock()
+
+	return s.search.Remove(ctx, draft)
+}
+
+// stillSubmitted matches a stored Dataset Proposal that is still SUBMITTED, and has not been resubmitted since the proposal was read
+func stillSubmitted(proposal *models.DatasetProposal) func(stored *models.DatasetProposal) bool {
+	return func(stored *models.DatasetProposal) bool {
+		return stored.ProposalStatus == "SUBMITTED" && stored.SubmittedAt == proposal.SubmittedAt
+	}
+}
+
+func (s *PublishingStore) RecordReviewReminder(ctx context.Context, proposal *models.DatasetProposal, remindedAt int64) error {
+	return s.updateProposal(proposal, stillSubmitted(proposal), func(proposal *models.DatasetProposal) {
+		proposal.ReviewRemindedAt = remindedAt
+	})
+}
+
+func (s *PublishingStore) RecordReviewEscalation(ctx context.Context, proposal *models.DatasetProposal, escalatedAt int64) error {
+	return s.updateProposal(proposal, stillSubmitted(proposal), func(proposal *models.DatasetProposal) {
+		proposal.ReviewEscalatedAt = escalatedAt
+	})
+}
+
+func (s *PublishingStore) SearchDatasetProposals(ctx context.Context, orgNodeId string, query string) ([]models.DatasetProposal, error) {
+	keys, err := s.search.Search(ctx, orgNodeId, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var proposals []models.DatasetProposal
+	for _, key := range keys {
+		proposal, err := s.GetDatasetProposal(ctx, key.UserId, key.NodeId)
+		if err != nil {
+			continue
+		}
+		proposals = append(proposals, *proposal)
+	}
+	return proposals, nil
+}
+
+func (s *PublishingStore) GetNotificationPreference(ctx context.Context, userId int64) (*models.NotificationPreference, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	preference, found := s.preferences[userId]
+	if !found {
+		return &models.NotificationPreference{UserId: userId, Frequency: models.NotifyImmediately}, nil
+	}
+	return &preference, nil
+}
+
+func (s *PublishingStore) PutNotificationPreference(ctx context.Context, preference *models.NotificationPreference) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.preferences[preference.UserId] = *preference
+	return nil
+}
+
+func (s *PublishingStore) RecordDigest(ctx context.Context, userId int64, digestAt int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	preference, found := s.preferences[userId]
+	if !found {
+		return store.ErrConflict
+	}
+	preference.LastDigestAt = digestAt
+	s.preferences[userId] = preference
+	return nil
+}
+
+// copyOutboxMessage returns a deep copy, so that callers cannot modify stored outbox messages
+func copyOutboxMessage(message models.OutboxMessage) models.OutboxMessage {
+	attributes := make(map[string]string, len(message.MessageAttributes))
+	for key, value := range message.MessageAttributes {
+		attributes[key] = value
+	}
+	message.MessageAttributes = attributes
+	message.Recipients = append([]models.OutboxRecipient(nil), message.Recipients...)
+	return message
+}
+
+// OutboxMessages returns every outbox message, ordered by CreatedAt, then Id
+func (s *PublishingStore) OutboxMessages() []models.OutboxMessage {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.sortedOutboxMessages(func(message *models.OutboxMessage) bool { return true })
+}
+
+func (s *PublishingStore) sortedOutboxMessages(match func(message *models.OutboxMessage) bool) []models.OutboxMessage {
+	var results []models.OutboxMessage
+	for _, message := range s.outbox {
+		if match(&message) {
+			results = append(results, copyOutboxMessage(message))
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].CreatedAt != results[j].CreatedAt {
+			return results[i].CreatedAt < results[j].CreatedAt
+		}
+		return results[i].Id < results[j].Id
+	})
+	return results
+}
+
+func (s *PublishingStore) UpdateDatasetProposalWithOutbox(ctx context.Context, proposal *models.DatasetProposal, messages []models.OutboxMessage) (*models.DatasetProposal, error) {
+	if err := validate(proposal); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if stored, found := s.proposals[keyOf(proposal)]; found && stored.Version != proposal.Version {
+		s.mu.Unlock()
+		return nil, store.ErrConflict
+	}
+	for _, message := range messages {
+		if _, found := s.outbox[message.Id]; found {
+			s.mu.Unlock()
+			return nil, fmt.Errorf("TransactionCanceledException: ConditionalCheckFailed")
+		}
+	}
+	proposal.Version++
+	s.proposals[keyOf(proposal)] = copyProposal(*proposal)
+	for _, message := range messages {
+		s.outbox[message.Id] = copyOutboxMessage(message)
+	}
+	s.mu.Unlock()
+
+	if err := s.search.Index(ctx, proposal); err != nil {
+		return nil, err
+	}
+	return proposal, nil
+}
+
+func (s *PublishingStore) GetDueOutboxMessages(ctx context.Context, now int64) ([]models.OutboxMessage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.sortedOutboxMessages(func(message *models.OutboxMessage) bool {
+		return message.Status == models.OutboxPending && message.NextAttemptAt <= now
+	}), nil
+}
+
+func (s *PublishingStore) PutOutboxMessage(ctx context.Context, message *models.OutboxMessage, expectedVersion int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if stored, found := s.outbox[message.Id]; !found || stored.Version != expectedVersion {
+		return store.ErrConflict
+	}
+	s.outbox[message.Id] = copyOutboxMessage(*message)
+	return nil
+}
+
+// copyWebhook returns a deep copy, so that callers cannot modify stored Webhooks
+func copyWebhook(webhook models.Webhook) models.Webhook {
+	webhook.Events = append([]string(nil), webhook.Events...)
+	return webhook
+}
+
+func (s *PublishingStore) GetWebhooks(ctx context.Context, orgNodeId string) ([]models.Webhook, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var webhooks []models.Webhook
+	for key, webhook := range s.webhooks {
+		if key.organizationNodeId == orgNodeId {
+			webhooks = append(webhooks, copyWebhook(webhook))
+		}
+	}
+	sort.Slice(webhooks, func(i, j int) bool {
+		return webhooks[i].Id < webhooks[j].Id
+	})
+	return webhooks, nil
+}
+
+func (s *PublishingStore) GetWebhook(ctx context.Context, orgNodeId string, id string) (*models.Webhook, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	webhook, found := s.webhooks[webhookKey{orgNodeId, id}]
+	if !found {
+		return nil, store.ErrNotFound
+	}
+	webhook = copyWebhook(webhook)
+	return &webhook, nil
+}
+
+func (s *PublishingStore) PutWebhook(ctx context.Context, webhook *models.Webhook) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.webhooks[webhookKey{webhook.OrganizationNodeId, webhook.Id}] = copyWebhook(*webhook)
+	return nil
+}
+
+func (s *PublishingStore) DeleteWebhook(ctx context.Context, webhook *models.Webhook) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.webhooks, webhookKey{webhook.OrganizationNodeId, webhook.Id})
+	return nil
+}
+
+// GetWebhookDeliveries returns the most recent attempts to deliver to the Webhook, newest first
+func (s *PublishingStore) GetWebhookDeliveries(ctx context.Context, webhookId string, limit int) ([]models.WebhookDelivery, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	deliveries := append([]models.WebhookDelivery(nil), s.deliveries[webhookId]...)
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].Id > deliveries[j].Id
+	})
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+func (s *PublishingStore) PutWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deliveries[delivery.WebhookId] = append(s.deliveries[delivery.WebhookId], *delivery)
+	return nil
+}
+
+var _ store.PublishingStore = (*PublishingStore)(nil)
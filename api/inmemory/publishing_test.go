@@ -0,0 +1,168 @@
+package inmemory
+
+import (
+	"context"
+	"errors"
+	"github.com/pennsieve/publishing-service/api/models"
+	"github.com/pennsieve/publishing-service/api/store"
+	"testing"
+)
+
+func testProposal() models.DatasetProposal {
+	return models.DatasetProposal{
+		UserId:             1,
+		NodeId:             "N:proposal:1",
+		OrganizationNodeId: "N:organization:1",
+		ProposalStatus:     "DRAFT",
+		Name:               "Proposal",
+		Contributors:       []models.Contributor{{FirstName: "Ada", LastName: "Lovelace"}},
+	}
+}
+
+func TestStoredItemsAreCopied(t *testing.T) {
+	ctx := context.Background()
+	pubStore := NewPublishingStore()
+
+	proposal := testProposal()
+	if _, err := pubStore.CreateDatasetProposal(ctx, &proposal); err != nil {
+		t.Fatalf("CreateDatasetProposal() error: %v", err)
+	}
+	// changes to the written proposal, and to one that has been read, are not stored
+	proposal.Contributors[0].FirstName = "Written"
+	read, err := pubStore.GetDatasetProposal(ctx, proposal.UserId, proposal.NodeId)
+	if err != nil {
+		t.Fatalf("GetDatasetProposal() error: %v", err)
+	}
+	read.Contributors[0].FirstName = "Read"
+	read.Name = "Read"
+
+	stored, _ := pubStore.GetDatasetProposal(ctx, proposal.UserId, proposal.NodeId)
+	if stored.Name != "Proposal" || stored.Contributors[0].FirstName != "Ada" {
+		t.Errorf("stored proposal = %+v, want it unchanged by its callers", stored)
+	}
+
+	pubStore.AddRepository(models.Repository{
+		OrganizationNodeId: "N:organization:1",
+		Questions:          []int{1, 2},
+		EmailBranding:      &models.EmailBranding{Subjects: map[string]string{"ProposalSubmitted": "Submitted"}},
+	})
+	repository, _ := pubStore.GetRepository(ctx, "N:organization:1")
+	repository.Questions[0] = 3
+	repository.EmailBranding.Subjects["ProposalSubmitted"] = "Changed"
+
+	repository, _ = pubStore.GetRepository(ctx, "N:organization:1")
+	if repository.Questions[0] != 1 || repository.EmailBranding.Subjects["ProposalSubmitted"] != "Submitted" {
+		t.Errorf("stored repository = %+v, want it unchanged by its callers", repository)
+	}
+}
+
+func TestGetsReturnErrNotFound(t *testing.T) {
+	ctx := context.Background()
+	pubStore := NewPublishingStore()
+
+	if _, err := pubStore.GetDatasetProposal(ctx, 1, "N:proposal:missing"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("GetDatasetProposal() error = %v, want ErrNotFound", err)
+	}
+	if _, err := pubStore.GetDatasetProposalByNodeId(ctx, "N:proposal:missing"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("GetDatasetProposalByNodeId() error = %v, want ErrNotFound", err)
+	}
+	if _, err := pubStore.GetRepository(ctx, "N:organization:missing"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("GetRepository() error = %v, want ErrNotFound", err)
+	}
+	if _, err := pubStore.GetWebhook(ctx, "N:organization:1", "missing"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("GetWebhook() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestEmptyIndexKeyAttributesAreRejected(t *testing.T) {
+	tests := map[string]func(proposal *models.DatasetProposal){
+		"no node id":         func(proposal *models.DatasetProposal) { proposal.NodeId = "" },
+		"no repository":      func(proposal *models.DatasetProposal) { proposal.OrganizationNodeId = "" },
+		"no proposal status": func(proposal *models.DatasetProposal) { proposal.ProposalStatus = "" },
+	}
+	for name, change := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			pubStore := NewPublishingStore()
+
+			proposal := testProposal()
+			change(&proposal)
+			if _, err := pubStore.CreateDatasetProposal(ctx, &proposal); err == nil {
+				t.Errorf("CreateDatasetProposal() succeeded, want the empty key attribute rejected")
+			}
+			if _, err := pubStore.UpdateDatasetProposalWithOutbox(ctx, &proposal, nil); err == nil {
+				t.Errorf("UpdateDatasetProposalWithOutbox() succeeded, want the empty key attribute rejected")
+			}
+			if proposals, _ := pubStore.GetDatasetProposalsForUser(ctx, int64(proposal.UserId)); len(proposals) != 0 {
+				t.Errorf("stored %+v, want nothing stored", proposals)
+			}
+		})
+	}
+}
+
+func TestDraftUpdatesRequireAnUnchangedDraft(t *testing.T) {
+	ctx := context.Background()
+	pubStore := NewPublishingStore()
+
+	proposal := testProposal()
+	proposal.UpdatedAt = 1000
+	if _, err := pubStore.CreateDatasetProposal(ctx, &proposal); err != nil {
+		t.Fatalf("CreateDatasetProposal() error: %v", err)
+	}
+	read := proposal
+
+	// the draft is edited after it was read
+	proposal.UpdatedAt = 2000
+	if _, err := pubStore.UpdateDatasetProposal(ctx, &proposal); err != nil {
+		t.Fatalf("UpdateDatasetProposal() error: %v", err)
+	}
+
+	if err := pubStore.RecordDraftReminder(ctx, &read, 3000); !errors.Is(err, store.ErrConflict) {
+		t.Errorf("RecordDraftReminder() error = %v, want ErrConflict", err)
+	}
+	if err := pubStore.ArchiveDraft(ctx, &read, 4000); !errors.Is(err, store.ErrConflict) {
+		t.Errorf("ArchiveDraft() error = %v, want ErrConflict", err)
+	}
+	if err := pubStore.DeleteDraft(ctx, &read); !errors.Is(err, store.ErrConflict) {
+		t.Errorf("DeleteDraft() error = %v, want ErrConflict", err)
+	}
+
+	if err := pubStore.ArchiveDraft(ctx, &proposal, 4000); err != nil {
+		t.Fatalf("ArchiveDraft() error: %v", err)
+	}
+	stored, _ := pubStore.GetDatasetProposal(ctx, proposal.UserId, proposal.NodeId)
+	if stored.ProposalStatus != "ARCHIVED" || stored.ExpiresAt != 4000 || stored.Version != proposal.Version {
+		t.Errorf("stored proposal = %+v, want it archived at version %d", stored, proposal.Version)
+	}
+}
+
+func TestReviewRecordsRequireTheSameSubmission(t *testing.T) {
+	ctx := context.Background()
+	pubStore := NewPublishingStore()
+
+	proposal := testProposal()
+	proposal.ProposalStatus = "SUBMITTED"
+	proposal.SubmittedAt = 1000
+	if _, err := pubStore.CreateDatasetProposal(ctx, &proposal); err != nil {
+		t.Fatalf("CreateDatasetProposal() error: %v", err)
+	}
+	read := proposal
+
+	if err := pubStore.RecordReviewReminder(ctx, &proposal, 2000); err != nil {
+		t.Fatalf("RecordReviewReminder() error: %v", err)
+	}
+
+	// the proposal is withdrawn and submitted again after it was read
+	proposal.SubmittedAt = 3000
+	if _, err := pubStore.UpdateDatasetProposal(ctx, &proposal); err != nil {
+		t.Fatalf("UpdateDatasetProposal() error: %v", err)
+	}
+
+	if err := pubStore.RecordReviewEscalation(ctx, &read, 4000); !errors.Is(err, store.ErrConflict) {
+		t.Errorf("RecordReviewEscalation() error = %v, want ErrConflict", err)
+	}
+	stored, _ := pubStore.GetDatasetProposal(ctx, proposal.UserId, proposal.NodeId)
+	if stored.ReviewRemindedAt != 2000 || stored.ReviewEscalatedAt != 0 {
+		t.Errorf("stored proposal = %+v, want the reminder recorded and no escalation", stored)
+	}
+}
@@ -0,0 +1,244 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/pennsieve/publishing-service/api/models"
+	"github.com/pennsieve/publishing-service/api/notification"
+	"github.com/pennsieve/publishing-service/api/store"
+	log "github.com/sirupsen/logrus"
+	"html"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// digestSlack allows for the schedule firing a little earlier than it did for the previous digest
+const digestSlack = time.Hour
+
+// digestStatuses are the statuses of proposals that may have been submitted or withdrawn since the last digest
+var digestStatuses = []string{"SUBMITTED", "ACCEPTED", "REJECTED", "WITHDRAWN"}
+
+// DigestsResult counts what a run of the Digests job did
+type DigestsResult struct {
+	Sent   int `json:"sent"`
+	Empty  int `json:"empty"`
+	Failed int `json:"failed"`
+}
+
+// Digests sends publishers who prefer a daily or weekly digest one email of the proposals submitted to,
+// or withdrawn from, their Repositories since their last digest. It is expected to run daily.
+type Digests struct {
+	store           store.PublishingStore
+	pennsieve       store.PennsievePublishingStore
+	notifier        notification.Notifier
+	pennsieveDomain string
+}
+
+func NewDigests(store store.PublishingStore, pennsieve store.PennsievePublishingStore, notifier notification.Notifier, pennsieveDomain string) *Digests {
+	return &Digests{
+		store:           store,
+		pennsieve:       pennsieve,
+		notifier:        notifier,
+		pennsieveDomain: pennsieveDomain,
+	}
+}
+
+// digestSubscriber is a publisher who prefers a digest, and the Repositories they publish for
+type digestSubscriber struct {
+	publisher    models.Publisher
+	preference   *models.NotificationPreference
+	repositories []*models.Repository
+}
+
+// digestEvent is a proposal being submitted or withdrawn
+type digestEvent struct {
+	at         int64
+	action     string
+	proposal   *models.DatasetProposal
+	repository *models.Repository
+}
+
+// Run sends the digests that are due as of now. A failure for one publisher is logged and counted,
+// and does not stop the run; their digest is sent on the next run, covering the events since the last one.
+func (j *Digests) Run(ctx context.Context, now time.Time) (*DigestsResult, error) {
+	log.WithFields(log.Fields{"now": now}).Info("Digests.Run()")
+
+	repositories, err := j.store.GetRepositories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting repositories: %w", err)
+	}
+
+	result := &DigestsResult{}
+	subscribers, failed := j.subscribers(ctx, repositories)
+	result.Failed += failed
+
+	events := make(map[string][]digestEvent)
+	for _, subscriber := range subscribers {
+		period := digestPeriod(subscriber.preference.Frequency)
+		if now.Sub(time.Unix(subscriber.preference.LastDigestAt, 0)) < period-digestSlack {
+			continue
+		}
+
+		sent, err := j.send(ctx, now, period, subscriber, events)
+		if err != nil {
+			log.WithFields(log.Fields{"userId": subscriber.publisher.UserId, "error": fmt.Sprintf("%+v", err)}).Error("Digests.Run()")
+			result.Failed++
+			continue
+		}
+		if sent {
+			result.Sent++
+		} else {
+			result.Empty++
+		}
+	}
+
+	log.WithFields(log.Fields{"result": fmt.Sprintf("%+v", result)}).Info("Digests.Run()")
+	return result, nil
+}
+
+func digestPeriod(frequency string) time.Duration {
+	if frequency == models.NotifyWeekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// subscribers finds the publishers of every Repository who prefer a digest, ordered by user id
+func (j *Digests) subscribers(ctx context.Context, repositories []models.Repository) ([]*digestSubscriber, int) {
+	failed := 0
+	byUser := make(map[int64]*digestSubscriber)
+	for i := range repositories {
+		repository := &repositories[i]
+		publishers, err := j.pennsieve.GetPublishingTeamMembers(ctx, repository)
+		if err != nil {
+			log.WithFields(log.Fields{"repository": repository.OrganizationNodeId, "error": fmt.Sprintf("%+v", err)}).Error("Digests.subscribers()")
+			failed++
+			continue
+		}
+
+		for _, publisher := range publishers {
+			if publisher.UserEmailAddress == "" {
+				continue
+			}
+			if subscriber, found := byUser[publisher.UserId]; found {
+				if subscriber != nil {
+					subscriber.repositories = append(subscriber.repositories, repository)
+				}
+				continue
+			}
+
+			preference, err := j.store.GetNotificationPreference(ctx, publisher.UserId)
+			if err != nil {
+				log.WithFields(log.Fields{"userId": publisher.UserId, "error": fmt.Sprintf("%+v", err)}).Error("Digests.subscribers()")
+				failed++
+				continue
+			}
+			if preference.Frequency != models.NotifyDaily && preference.Frequency != models.NotifyWeekly {
+				// remember publishers without a digest, so that their preference is read once
+				byUser[publisher.UserId] = nil
+				continue
+			}
+			byUser[publisher.UserId] = &digestSubscriber{
+				publisher:    publisher,
+				preference:   preference,
+				repositories: []*models.Repository{repository},
+			}
+		}
+	}
+
+	var subscribers []*digestSubscriber
+	for _, subscriber := range byUser {
+		if subscriber != nil {
+			subscribers = append(subscribers, subscriber)
+		}
+	}
+	sort.Slice(subscribers, func(i, k int) bool {
+		return subscribers[i].publisher.UserId < subscribers[k].publisher.UserId
+	})
+	return subscribers, failed
+}
+
+// repositoryEvents returns every submitted and withdrawn event of the Repository, reading them once per run
+func (j *Digests) repositoryEvents(ctx context.Context, repository *models.Repository, cache map[string][]digestEvent) ([]digestEvent, error) {
+	if events, found := cache[repository.OrganizationNodeId]; found {
+		return events, nil
+	}
+
+	var events []digestEvent
+	for _, status := range digestStatuses {
+		proposals, err := j.store.GetDatasetProposalsForWorkspace(ctx, repository.OrganizationNodeId, status)
+		if err != nil {
+			return nil, fmt.Errorf("getting %s proposals for repository %s: %w", status, repository.OrganizationNodeId, err)
+		}
+		for i := range proposals {
+			proposal := &proposals[i]
+			if proposal.SubmittedAt > 0 {
+				events = append(events, digestEvent{at: proposal.SubmittedAt, action: "submitted to", proposal: proposal, repository: repository})
+			}
+			if proposal.WithdrawnAt > 0 {
+				events = append(events, digestEvent{at: proposal.WithdrawnAt, action: "withdrawn from", proposal: proposal, repository: repository})
+			}
+		}
+	}
+
+	cache[repository.OrganizationNodeId] = events
+	return events, nil
+}
+
+// send emails the subscriber the events since their last digest, if there are any, and records the digest
+func (j *Digests) send(ctx context.Context, now time.Time, period time.Duration, subscriber *digestSubscriber, cache map[string][]digestEvent) (bool, error) {
+	since := subscriber.preference.LastDigestAt
+	if since == 0 {
+		since = now.Add(-period).Unix()
+	}
+
+	var events []digestEvent
+	for _, repository := range subscriber.repositories {
+		repositoryEvents, err := j.repositoryEvents(ctx, repository, cache)
+		if err != nil {
+			return false, err
+		}
+		for _, event := range repositoryEvents {
+			if event.at > since && event.at <= now.Unix() {
+				events = append(events, event)
+			}
+		}
+	}
+	sort.SliceStable(events, func(i, k int) bool { return events[i].at < events[k].at })
+
+	if len(events) > 0 {
+		var items []string
+		for _, event := range events {
+			items = append(items, fmt.Sprintf("<li><strong>%s</strong> by %s was %s %s on %s</li>",
+				html.EscapeString(event.proposal.Name),
+				html.EscapeString(event.proposal.OwnerName),
+				event.action,
+				html.EscapeString(event.repository.DisplayName),
+				time.Unix(event.at, 0).UTC().Format("January 2, 2006")))
+		}
+		messageAttributes := notification.MessageAttributes{
+			"AppURL":        fmt.Sprintf("app.%s", j.pennsieveDomain),
+			"RecipientName": subscriber.publisher.UserName,
+			"Period":        subscriber.preference.Frequency,
+			"EventCount":    strconv.Itoa(len(events)),
+			"Events":        strings.Join(items, ""),
+		}
+		if err := j.notifier.ProposalDigest(ctx, messageAttributes, []string{subscriber.publisher.UserEmailAddress}); err != nil {
+			return false, fmt.Errorf("sending digest: %w", err)
+		}
+	}
+
+	// only LastDigestAt is written, so that a preference changed since it was read is kept
+	err := j.store.RecordDigest(ctx, subscriber.publisher.UserId, now.Unix())
+	if errors.Is(err, store.ErrConflict) {
+		log.WithFields(log.Fields{"userId": subscriber.publisher.UserId}).Info("Digests.send() preference removed, skipped")
+	} else if err != nil {
+		return false, fmt.Errorf("recording digest: %w", err)
+	}
+	subscriber.preference.LastDigestAt = now.Unix()
+
+	return len(events) > 0, nil
+}
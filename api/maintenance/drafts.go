@@ -0,0 +1,147 @@
+// Package maintenance holds the scheduled jobs that keep the Publishing Service's data tidy.
+// They run outside of API requests, so they act on behalf of the service rather than a user.
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/pennsieve/publishing-service/api/config"
+	"github.com/pennsieve/publishing-service/api/models"
+	"github.com/pennsieve/publishing-service/api/notification"
+	"github.com/pennsieve/publishing-service/api/store"
+	log "github.com/sirupsen/logrus"
+	"time"
+)
+
+// DraftExpiryResult counts what a run of the DraftExpiry job did
+type DraftExpiryResult struct {
+	Reminded int `json:"reminded"`
+	Archived int `json:"archived"`
+	Deleted  int `json:"deleted"`
+	Failed   int `json:"failed"`
+}
+
+// DraftExpiry cleans up abandoned DRAFT proposals. A draft untouched for the policy's ReminderAfter
+// period is sent a reminder, and one still untouched a Grace period after its reminder is archived
+// (ARCHIVED, with a DynamoDB TTL) or deleted. Editing a draft after its reminder starts over.
+type DraftExpiry struct {
+	store           store.PublishingStore
+	notifier        notification.Notifier
+	policy          config.DraftExpiry
+	pennsieveDomain string
+}
+
+func NewDraftExpiry(store store.PublishingStore, notifier notification.Notifier, policy config.DraftExpiry, pennsieveDomain string) *DraftExpiry {
+	return &DraftExpiry{
+		store:           store,
+		notifier:        notifier,
+		policy:          policy,
+		pennsieveDomain: pennsieveDomain,
+	}
+}
+
+// Run expires the drafts of every Repository as of now. A failure on one draft is logged and
+// counted, and does not stop the run; the draft is tried again on the next run.
+func (j *DraftExpiry) Run(ctx context.Context, now time.Time) (*DraftExpiryResult, error) {
+	log.WithFields(log.Fields{"now": now, "policy": fmt.Sprintf("%+v", j.policy)}).Info("DraftExpiry.Run()")
+
+	repositories, err := j.store.GetRepositories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting repositories: %w", err)
+	}
+
+	result := &DraftExpiryResult{}
+	for i := range repositories {
+		repository := &repositories[i]
+		drafts, err := j.store.GetDatasetProposalsForWorkspace(ctx, repository.OrganizationNodeId, "DRAFT")
+		if err != nil {
+			return result, fmt.Errorf("getting drafts for repository %s: %w", repository.OrganizationNodeId, err)
+		}
+
+		for k := range drafts {
+			if err := j.expire(ctx, now, repository, &drafts[k], result); err != nil {
+				log.WithFields(log.Fields{"nodeId": drafts[k].NodeId, "error": fmt.Sprintf("%+v", err)}).Error("DraftExpiry.Run()")
+				result.Failed++
+			}
+		}
+	}
+
+	log.WithFields(log.Fields{"result": fmt.Sprintf("%+v", result)}).Info("DraftExpiry.Run()")
+	return result, nil
+}
+
+// expire takes the next step for a single draft: nothing, a reminder, or expiry
+func (j *DraftExpiry) expire(ctx context.Context, now time.Time, repository *models.Repository, draft *models.DatasetProposal, result *DraftExpiryResult) error {
+	if now.Sub(time.Unix(draft.UpdatedAt, 0)) < j.policy.ReminderAfter {
+		return nil
+	}
+
+	// the author has not been reminded since the draft was last changed
+	if draft.ReminderSentAt < draft.UpdatedAt {
+		reminded, err := j.remind(ctx, now, repository, draft)
+		if err != nil {
+			return err
+		}
+		if reminded {
+			result.Reminded++
+		}
+		return nil
+	}
+
+	if now.Sub(time.Unix(draft.ReminderSentAt, 0)) < j.policy.Grace {
+		return nil
+	}
+
+	// each step is conditional on the draft being unchanged since it was read, so that one edited, submitted
+	// or deleted in the meantime is skipped
+	var err error
+	var count *int
+	switch j.policy.Action {
+	case config.DraftExpiryDelete:
+		err = j.store.DeleteDraft(ctx, draft)
+		count = &result.Deleted
+	default:
+		err = j.store.ArchiveDraft(ctx, draft, now.Add(j.policy.ArchiveRetention).Unix())
+		count = &result.Archived
+	}
+	if errors.Is(err, store.ErrConflict) {
+		log.WithFields(log.Fields{"nodeId": draft.NodeId, "userId": draft.UserId}).Info("DraftExpiry.expire() draft changed, skipped")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("expiring draft (%s): %w", j.policy.Action, err)
+	}
+	*count++
+	log.WithFields(log.Fields{"nodeId": draft.NodeId, "userId": draft.UserId, "action": j.policy.Action}).Info("DraftExpiry.expire()")
+
+	return nil
+}
+
+// remind emails the author, then records the reminder; UpdatedAt is left as it is, so that
+// a change made after the reminder can be told apart from the reminder itself. The reminder is only recorded
+// on a draft unchanged since it was read; otherwise it is skipped, and false returned.
+func (j *DraftExpiry) remind(ctx context.Context, now time.Time, repository *models.Repository, draft *models.DatasetProposal) (bool, error) {
+	messageAttributes := notification.MessageAttributes{
+		"AppURL":        fmt.Sprintf("app.%s", j.pennsieveDomain),
+		"AuthorName":    draft.OwnerName,
+		"AuthorEmail":   draft.EmailAddress,
+		"ProposalTitle": draft.Name,
+		"WorkspaceName": repository.DisplayName,
+		"ExpiresOn":     now.Add(j.policy.Grace).Format("January 2, 2006"),
+	}
+	if err := j.notifier.ProposalDraftReminder(ctx, messageAttributes, []string{draft.EmailAddress}); err != nil {
+		return false, fmt.Errorf("sending reminder: %w", err)
+	}
+
+	err := j.store.RecordDraftReminder(ctx, draft, now.Unix())
+	if errors.Is(err, store.ErrConflict) {
+		log.WithFields(log.Fields{"nodeId": draft.NodeId, "userId": draft.UserId}).Info("DraftExpiry.remind() draft changed, skipped")
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("recording reminder: %w", err)
+	}
+
+	return true, nil
+}
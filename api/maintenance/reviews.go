@@ -0,0 +1,213 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/pennsieve/publishing-service/api/config"
+	"github.com/pennsieve/publishing-service/api/models"
+	"github.com/pennsieve/publishing-service/api/notification"
+	"github.com/pennsieve/publishing-service/api/store"
+	log "github.com/sirupsen/logrus"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReviewRemindersResult counts what a run of the ReviewReminders job did
+type ReviewRemindersResult struct {
+	Reminded  int `json:"reminded"`
+	Escalated int `json:"escalated"`
+	Failed    int `json:"failed"`
+}
+
+// ReviewReminders chases SUBMITTED proposals awaiting review. Once a proposal has waited past its
+// Repository's reminder threshold, it is included in a digest sent to the publishers team; once it
+// has waited past the escalation threshold, in a digest sent to the workspace admins. Each digest
+// includes a proposal once per submission, which is recorded on the proposal.
+type ReviewReminders struct {
+	store           store.PublishingStore
+	pennsieve       store.PennsievePublishingStore
+	notifier        notification.Notifier
+	policy          config.ReviewSLA
+	pennsieveDomain string
+}
+
+func NewReviewReminders(store store.PublishingStore, pennsieve store.PennsievePublishingStore, notifier notification.Notifier, policy config.ReviewSLA, pennsieveDomain string) *ReviewReminders {
+	return &ReviewReminders{
+		store:           store,
+		pennsieve:       pennsieve,
+		notifier:        notifier,
+		policy:          policy,
+		pennsieveDomain: pennsieveDomain,
+	}
+}
+
+// reviewDigest is a digest that is yet to be sent
+type reviewDigest struct {
+	action    notification.Notification
+	proposals []*models.DatasetProposal
+	after     time.Duration
+}
+
+// Run sends the digests of every Repository as of now. A failure on one Repository is logged and
+// counted, and does not stop the run; its proposals are included again on the next run.
+func (j *ReviewReminders) Run(ctx context.Context, now time.Time) (*ReviewRemindersResult, error) {
+	log.WithFields(log.Fields{"now": now, "policy": fmt.Sprintf("%+v", j.policy)}).Info("ReviewReminders.Run()")
+
+	repositories, err := j.store.GetRepositories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting repositories: %w", err)
+	}
+
+	result := &ReviewRemindersResult{}
+	for i := range repositories {
+		if err := j.review(ctx, now, &repositories[i], result); err != nil {
+			log.WithFields(log.Fields{"repository": repositories[i].OrganizationNodeId, "error": fmt.Sprintf("%+v", err)}).Error("ReviewReminders.Run()")
+			result.Failed++
+		}
+	}
+
+	log.WithFields(log.Fields{"result": fmt.Sprintf("%+v", result)}).Info("ReviewReminders.Run()")
+	return result, nil
+}
+
+// thresholds returns the Repository's reminder and escalation thresholds
+func (j *ReviewReminders) thresholds(repository *models.Repository) (time.Duration, time.Duration) {
+	reminderAfter, escalateAfter := j.policy.ReminderAfter, j.policy.EscalateAfter
+	if sla := repository.ReviewSLA; sla != nil {
+		if sla.ReminderDays > 0 {
+			reminderAfter = time.Duration(sla.ReminderDays) * 24 * time.Hour
+		}
+		if sla.EscalationDays > 0 {
+			escalateAfter = time.Duration(sla.EscalationDays) * 24 * time.Hour
+		}
+	}
+	return reminderAfter, escalateAfter
+}
+
+// review sends the Repository's reminder and escalation digests, if it has overdue proposals
+func (j *ReviewReminders) review(ctx context.Context, now time.Time, repository *models.Repository, result *ReviewRemindersResult) error {
+	submitted, err := j.store.GetDatasetProposalsForWorkspace(ctx, repository.OrganizationNodeId, "SUBMITTED")
+	if err != nil {
+		return fmt.Errorf("getting submitted proposals: %w", err)
+	}
+
+	reminderAfter, escalateAfter := j.thresholds(repository)
+	reminder := reviewDigest{action: notification.ReviewReminder, after: reminderAfter}
+	escalation := reviewDigest{action: notification.ReviewEscalation, after: escalateAfter}
+	for i := range submitted {
+		proposal := &submitted[i]
+		waiting := now.Sub(time.Unix(proposal.SubmittedAt, 0))
+		// a proposal withdrawn and submitted again is chased afresh
+		if waiting >= reminderAfter && proposal.ReviewRemindedAt < proposal.SubmittedAt {
+			reminder.proposals = append(reminder.proposals, proposal)
+		}
+		if waiting >= escalateAfter && proposal.ReviewEscalatedAt < proposal.SubmittedAt {
+			escalation.proposals = append(escalation.proposals, proposal)
+		}
+	}
+
+	for _, digest := range []reviewDigest{reminder, escalation} {
+		if len(digest.proposals) == 0 {
+			continue
+		}
+		if err := j.send(ctx, now, repository, digest); err != nil {
+			return err
+		}
+		switch digest.action {
+		case notification.ReviewReminder:
+			result.Reminded += len(digest.proposals)
+		case notification.ReviewEscalation:
+			result.Escalated += len(digest.proposals)
+		}
+	}
+
+	return nil
+}
+
+// send emails the digest to its recipients, then records it on each of its proposals
+func (j *ReviewReminders) send(ctx context.Context, now time.Time, repository *models.Repository, digest reviewDigest) error {
+	recipients, err := j.recipients(ctx, repository, digest.action)
+	if err != nil {
+		return err
+	}
+	if len(recipients) == 0 {
+		return fmt.Errorf("no recipients for review digest %d", digest.action)
+	}
+
+	var items []string
+	for _, proposal := range digest.proposals {
+		items = append(items, fmt.Sprintf("<li><strong>%s</strong> by %s, submitted %s</li>",
+			html.EscapeString(proposal.Name),
+			html.EscapeString(proposal.OwnerName),
+			time.Unix(proposal.SubmittedAt, 0).UTC().Format("January 2, 2006")))
+	}
+	messageAttributes := notification.MessageAttributes{
+		"AppURL":          fmt.Sprintf("app.%s", j.pennsieveDomain),
+		"WorkspaceName":   repository.DisplayName,
+		"WorkspaceNodeId": repository.OrganizationNodeId,
+		"ProposalCount":   strconv.Itoa(len(digest.proposals)),
+		"WaitingDays":     strconv.Itoa(int(digest.after.Hours() / 24)),
+		"Proposals":       strings.Join(items, ""),
+	}
+
+	switch digest.action {
+	case notification.ReviewReminder:
+		err = j.notifier.ProposalReviewReminder(ctx, messageAttributes, recipients)
+	case notification.ReviewEscalation:
+		err = j.notifier.ProposalReviewEscalation(ctx, messageAttributes, recipients)
+	}
+	if err != nil {
+		return fmt.Errorf("sending review digest: %w", err)
+	}
+
+	// a proposal reviewed, or withdrawn, since it was read is skipped
+	for _, proposal := range digest.proposals {
+		switch digest.action {
+		case notification.ReviewReminder:
+			err = j.store.RecordReviewReminder(ctx, proposal, now.Unix())
+		case notification.ReviewEscalation:
+			err = j.store.RecordReviewEscalation(ctx, proposal, now.Unix())
+		}
+		if errors.Is(err, store.ErrConflict) {
+			log.WithFields(log.Fields{"nodeId": proposal.NodeId, "action": digest.action}).Info("ReviewReminders.send() proposal changed, skipped")
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("recording review digest for %s: %w", proposal.NodeId, err)
+		}
+	}
+	log.WithFields(log.Fields{"repository": repository.OrganizationNodeId, "action": digest.action, "proposals": len(digest.proposals), "recipients": recipients}).Info("ReviewReminders.send()")
+
+	return nil
+}
+
+// recipients are the publishers team for a reminder, and the workspace admins for an escalation
+func (j *ReviewReminders) recipients(ctx context.Context, repository *models.Repository, action notification.Notification) ([]string, error) {
+	var recipients []string
+	switch action {
+	case notification.ReviewReminder:
+		publishers, err := j.pennsieve.GetPublishingTeamMembers(ctx, repository)
+		if err != nil {
+			return nil, fmt.Errorf("getting publishing team: %w", err)
+		}
+		for _, publisher := range publishers {
+			if publisher.UserEmailAddress != "" {
+				recipients = append(recipients, publisher.UserEmailAddress)
+			}
+		}
+	case notification.ReviewEscalation:
+		admins, err := j.pennsieve.GetWorkspaceAdmins(ctx, repository)
+		if err != nil {
+			return nil, fmt.Errorf("getting workspace admins: %w", err)
+		}
+		for _, admin := range admins {
+			if admin.UserEmailAddress != "" {
+				recipients = append(recipients, admin.UserEmailAddress)
+			}
+		}
+	}
+	return recipients, nil
+}
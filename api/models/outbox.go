@@ -0,0 +1,48 @@
+package models
+
+// Outbox statuses, of a message and of each of its recipients
+const (
+	OutboxPending   = "PENDING"
+	OutboxDelivered = "DELIVERED"
+	OutboxDead      = "DEAD"
+	// OutboxSkipped is a recipient that is no longer to be delivered to, e.g. a Webhook that has been removed
+	OutboxSkipped = "SKIPPED"
+)
+
+// Outbox channels: messages are emailed, POSTed to the Webhooks that are their recipients, or published to the event bus
+const (
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+	ChannelEvent   = "event"
+)
+
+type OutboxRecipient struct {
+	Address     string `dynamodbav:"Address"`
+	Status      string `dynamodbav:"Status"`
+	Attempts    int    `dynamodbav:"Attempts"`
+	LastError   string `dynamodbav:"LastError"`
+	DeliveredAt int64  `dynamodbav:"DeliveredAt"`
+}
+
+// OutboxMessage is a notification that is yet to be delivered, written in the same transaction as the
+// Dataset Proposal change it reports. Delivered messages expire (ExpiresAt is the TTL attribute); dead ones are kept.
+// Email messages carry MessageAttributes and are sent to email addresses; webhook messages carry the JSON Payload
+// of the event and are sent to the Ids of the Repository's Webhooks; event messages carry the JSON Payload of the
+// domain event, have no Notification, and are sent to the event bus. A message without a Channel is an email.
+type OutboxMessage struct {
+	Id                 string            `dynamodbav:"Id"`
+	Channel            string            `dynamodbav:"Channel,omitempty"`
+	ProposalNodeId     string            `dynamodbav:"ProposalNodeId"`
+	OrganizationNodeId string            `dynamodbav:"OrganizationNodeId,omitempty"`
+	Payload            string            `dynamodbav:"Payload,omitempty"`
+	Notification       string            `dynamodbav:"Notification"`
+	MessageAttributes  map[string]string `dynamodbav:"MessageAttributes"`
+	Recipients         []OutboxRecipient `dynamodbav:"Recipients"`
+	Status             string            `dynamodbav:"Status"`
+	Attempts           int               `dynamodbav:"Attempts"`
+	NextAttemptAt      int64             `dynamodbav:"NextAttemptAt"`
+	Version            int64             `dynamodbav:"Version"`
+	CreatedAt          int64             `dynamodbav:"CreatedAt"`
+	UpdatedAt          int64             `dynamodbav:"UpdatedAt"`
+	ExpiresAt          int64             `dynamodbav:"ExpiresAt,omitempty"`
+}
@@ -0,0 +1,17 @@
+package models
+
+// Notification frequencies: a publisher is emailed about each submitted or withdrawn proposal
+// immediately, in a daily or weekly digest, or not at all
+const (
+	NotifyImmediately = "immediate"
+	NotifyDaily       = "daily"
+	NotifyWeekly      = "weekly"
+	NotifyOff         = "off"
+)
+
+type NotificationPreference struct {
+	UserId       int64  `dynamodbav:"UserId"`
+	Frequency    string `dynamodbav:"Frequency"`
+	LastDigestAt int64  `dynamodbav:"LastDigestAt"`
+	UpdatedAt    int64  `dynamodbav:"UpdatedAt"`
+}
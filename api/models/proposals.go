@@ -23,6 +23,11 @@ type DatasetProposal struct {
 	WithdrawnAt        int64         `dynamodbav:"WithdrawnAt"`
 	AcceptedAt         int64         `dynamodbav:"AcceptedAt"`
 	RejectedAt         int64         `dynamodbav:"RejectedAt"`
+	ReminderSentAt     int64         `dynamodbav:"ReminderSentAt"`
+	ReviewRemindedAt   int64         `dynamodbav:"ReviewRemindedAt"`
+	ReviewEscalatedAt  int64         `dynamodbav:"ReviewEscalatedAt"`
+	ExpiresAt          int64         `dynamodbav:"ExpiresAt,omitempty"`
+	Version            int64         `dynamodbav:"Version"`
 }
 
 type DatasetProposalKey struct {
@@ -22,3 +22,12 @@ type Publisher struct {
 	UserTeamPermissionBit      int64
 	UserWorkspacePermissionBit int64
 }
+
+type WorkspaceAdmin struct {
+	WorkspaceId      int64
+	WorkspaceName    string
+	UserId           int64
+	UserName         string
+	UserEmailAddress string
+	PermissionBit    int64
+}
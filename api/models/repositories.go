@@ -6,15 +6,35 @@ type S3Location struct {
 }
 
 type Repository struct {
-	OrganizationNodeId string     `dynamodbav:"OrganizationNodeId"`
-	Name               string     `dynamodbav:"Name"`
-	DisplayName        string     `dynamodbav:"DisplayName"`
-	Type               string     `dynamodbav:"Type"`
-	Description        string     `dynamodbav:"Description"`
-	URL                string     `dynamodbav:"URL"`
-	OverviewDocument   S3Location `dynamodbav:"OverviewDocument"`
-	LogoFile           S3Location `dynamodbav:"LogoFile"`
-	Questions          []int      `dynamodbav:"Questions"`
-	CreatedAt          int64      `dynamodbav:"CreatedAt"`
-	UpdatedAt          int64      `dynamodbav:"UpdatedAt"`
+	OrganizationNodeId string         `dynamodbav:"OrganizationNodeId"`
+	Name               string         `dynamodbav:"Name"`
+	DisplayName        string         `dynamodbav:"DisplayName"`
+	Type               string         `dynamodbav:"Type"`
+	Description        string         `dynamodbav:"Description"`
+	URL                string         `dynamodbav:"URL"`
+	OverviewDocument   S3Location     `dynamodbav:"OverviewDocument"`
+	LogoFile           S3Location     `dynamodbav:"LogoFile"`
+	Questions          []int          `dynamodbav:"Questions"`
+	ReviewSLA          *ReviewSLA     `dynamodbav:"ReviewSLA,omitempty"`
+	EmailBranding      *EmailBranding `dynamodbav:"EmailBranding,omitempty"`
+	CreatedAt          int64          `dynamodbav:"CreatedAt"`
+	UpdatedAt          int64          `dynamodbav:"UpdatedAt"`
+}
+
+// ReviewSLA overrides the default thresholds after which a Repository's publishers are reminded of
+// SUBMITTED proposals awaiting review, and after which its workspace admins are told. Zero uses the default.
+type ReviewSLA struct {
+	ReminderDays   int `dynamodbav:"ReminderDays"`
+	EscalationDays int `dynamodbav:"EscalationDays"`
+}
+
+// EmailBranding overrides the emails sent about the Dataset Proposals made to a Repository. Subjects and Templates
+// are keyed by the name of the notification, e.g. ProposalSubmitted; a notification without an override, or an
+// empty field, uses the default.
+type EmailBranding struct {
+	// HeaderLogo puts the Repository's LogoFile in the header of its emails, in place of the Pennsieve logo
+	HeaderLogo bool                  `dynamodbav:"HeaderLogo"`
+	ReplyTo    string                `dynamodbav:"ReplyTo,omitempty"`
+	Subjects   map[string]string     `dynamodbav:"Subjects,omitempty"`
+	Templates  map[string]S3Location `dynamodbav:"Templates,omitempty"`
 }
@@ -0,0 +1,32 @@
+package models
+
+// Webhook is an HTTPS endpoint registered by a Repository to receive its Dataset Proposal lifecycle events.
+// An empty Events list subscribes to every event. The Secret signs each payload sent to the endpoint.
+type Webhook struct {
+	OrganizationNodeId string   `dynamodbav:"OrganizationNodeId"`
+	Id                 string   `dynamodbav:"Id"`
+	URL                string   `dynamodbav:"URL"`
+	Events             []string `dynamodbav:"Events"`
+	Secret             string   `dynamodbav:"Secret"`
+	Active             bool     `dynamodbav:"Active"`
+	CreatedBy          int64    `dynamodbav:"CreatedBy"`
+	CreatedAt          int64    `dynamodbav:"CreatedAt"`
+	UpdatedAt          int64    `dynamodbav:"UpdatedAt"`
+}
+
+// WebhookDelivery records one attempt to deliver an event to a Webhook; together they are its delivery log.
+// The Id sorts the attempts in the order they were made. Attempts expire (ExpiresAt is the TTL attribute).
+type WebhookDelivery struct {
+	WebhookId      string `dynamodbav:"WebhookId"`
+	Id             string `dynamodbav:"Id"`
+	EventId        string `dynamodbav:"EventId"`
+	EventType      string `dynamodbav:"EventType"`
+	ProposalNodeId string `dynamodbav:"ProposalNodeId"`
+	Attempt        int    `dynamodbav:"Attempt"`
+	StatusCode     int    `dynamodbav:"StatusCode"`
+	Error          string `dynamodbav:"Error"`
+	Succeeded      bool   `dynamodbav:"Succeeded"`
+	DurationMillis int64  `dynamodbav:"DurationMillis"`
+	AttemptedAt    int64  `dynamodbav:"AttemptedAt"`
+	ExpiresAt      int64  `dynamodbav:"ExpiresAt,omitempty"`
+}
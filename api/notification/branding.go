@@ -0,0 +1,68 @@
+package notification
+
+import (
+	"github.com/pennsieve/publishing-service/api/models"
+	"net/url"
+	"strings"
+)
+
+// Message attributes that carry a Repository's EmailBranding. They are resolved from the Repository when the
+// notification is built, so that they travel with it through the outbox, and each Notifier falls back to its
+// defaults for those that are not set.
+const (
+	SubjectAttribute        = "Subject"
+	ReplyToAttribute        = "ReplyTo"
+	LogoURLAttribute        = "LogoURL"
+	TemplateBucketAttribute = "TemplateBucket"
+	TemplateKeyAttribute    = "TemplateKey"
+)
+
+// DefaultLogoURL is the Pennsieve logo in the header of the email templates
+const DefaultLogoURL = "https://app.pennsieve.net/assets/Upenn_FullLogo_Reverse_RGB-24d7f51c.png"
+
+var brandingAttributes = []string{SubjectAttribute, ReplyToAttribute, LogoURLAttribute, TemplateBucketAttribute, TemplateKeyAttribute}
+
+// Brand adds the Repository's overrides of the notification to the message attributes. The Repository's logo is
+// linked by its S3 key under logoBaseURL, a public (e.g. CloudFront) URL, as the email may be opened long after it
+// is sent; without a logoBaseURL, the Pennsieve logo is kept.
+func Brand(messageAttributes MessageAttributes, n Notification, repository *models.Repository, logoBaseURL string) MessageAttributes {
+	if repository == nil || repository.EmailBranding == nil {
+		return messageAttributes
+	}
+	branding := repository.EmailBranding
+
+	if branding.ReplyTo != "" {
+		messageAttributes[ReplyToAttribute] = branding.ReplyTo
+	}
+	if subject := branding.Subjects[n.String()]; subject != "" {
+		messageAttributes[SubjectAttribute] = subject
+	}
+	if template := branding.Templates[n.String()]; template.S3Key != "" {
+		messageAttributes[TemplateBucketAttribute] = template.S3Bucket
+		messageAttributes[TemplateKeyAttribute] = template.S3Key
+	}
+	if branding.HeaderLogo && repository.LogoFile.S3Key != "" && logoBaseURL != "" {
+		messageAttributes[LogoURLAttribute] = logoURL(logoBaseURL, repository.LogoFile.S3Key)
+	}
+
+	return messageAttributes
+}
+
+// logoURL is the URL of the logo with the S3 key under the base URL
+func logoURL(baseURL string, key string) string {
+	segments := strings.Split(key, "/")
+	for i := range segments {
+		segments[i] = url.PathEscape(segments[i])
+	}
+	return strings.TrimSuffix(baseURL, "/") + "/" + strings.Join(segments, "/")
+}
+
+// Branded reports whether the message attributes carry any of a Repository's overrides
+func Branded(messageAttributes MessageAttributes) bool {
+	for _, attribute := range brandingAttributes {
+		if messageAttributes[attribute] != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -6,111 +6,204 @@ import (
 	"github.com/pennsieve/publishing-service/api/aws/s3"
 	"github.com/pennsieve/publishing-service/api/aws/ses"
 	sesTypes "github.com/pennsieve/publishing-service/api/aws/ses/types"
+	"github.com/pennsieve/publishing-service/api/config"
 	log "github.com/sirupsen/logrus"
-	"os"
-	"strings"
 )
 
-func NewEmailNotifier(ctx context.Context) *EmailNotifier {
+func NewEmailNotifier(emailAgent *ses.Emailer, fileReader *s3.FileReader, pennsieveDomain string, templates config.EmailTemplates) *EmailNotifier {
 	return &EmailNotifier{
-		ctx:        ctx,
-		sender:     fmt.Sprintf("support@%s", os.Getenv("PENNSIEVE_DOMAIN")),
-		emailAgent: ses.MakeEmailer(),
-		fileReader: s3.MakeFileReader(),
+		sender:     fmt.Sprintf("support@%s", pennsieveDomain),
+		emailAgent: emailAgent,
+		templates:  templates,
+		cache:      newTemplateCache(fileReader, templates.CacheTTL),
 	}
 }
 
 type EmailNotifier struct {
-	ctx        context.Context
 	sender     string
-	fileReader *s3.FileReader
 	emailAgent *ses.Emailer
+	templates  config.EmailTemplates
+	cache      *templateCache
 }
 
-func (e *EmailNotifier) replaceTemplateFields(template string, messageAttributes MessageAttributes) string {
-	modified := template
+// Rendering is a notification's email as it is sent
+type Rendering struct {
+	Subject string
+	HTML    string
+	Text    string
+	ReplyTo []string
+}
 
-	for key := range messageAttributes {
-		search := fmt.Sprintf("${%s}", key)
-		replace := messageAttributes[key]
-		log.WithFields(log.Fields{"key": key, "search": search, "replace": replace}).Info("EmailNotifier.replaceTemplateFields()")
-		modified = strings.Replace(modified, search, replace, -1)
+// defaultEmail returns the default subject and template of the notification's email
+func (e *EmailNotifier) defaultEmail(n Notification, messageAttributes MessageAttributes) (subject string, s3Key string, err error) {
+	switch n {
+	case Submitted:
+		return "A Dataset Proposal has been submitted", e.templates.Submitted, nil
+	case Withdrawn:
+		return "A Dataset Proposal has been withdrawn", e.templates.Withdrawn, nil
+	case Accepted:
+		return "Your Dataset Proposal has been accepted", e.templates.Accepted, nil
+	case Rejected:
+		return "Your Dataset Proposal has been rejected", e.templates.Rejected, nil
+	case DraftReminder:
+		return "Your draft Dataset Proposal will expire soon", e.templates.DraftReminder, nil
+	case ReviewReminder:
+		return "Dataset Proposals are awaiting review", e.templates.ReviewReminder, nil
+	case ReviewEscalation:
+		return "Dataset Proposals are overdue for review", e.templates.ReviewEscalation, nil
+	case Digest:
+		return fmt.Sprintf("Your %s Dataset Proposal digest", messageAttributes["Period"]), e.templates.Digest, nil
+	case SubmittedReceipt:
+		return "Your Dataset Proposal has been submitted", e.templates.SubmittedReceipt, nil
+	case WithdrawnReceipt:
+		return "Your Dataset Proposal has been withdrawn", e.templates.WithdrawnReceipt, nil
+	case ContributorAccepted:
+		return "A Dataset Proposal you contributed to has been accepted", e.templates.ContributorAccepted, nil
+	case AdminCopy:
+		return fmt.Sprintf("A Dataset Proposal to %s has been %s", messageAttributes["WorkspaceName"], messageAttributes["Action"]), e.templates.AdminCopy, nil
 	}
-
-	return modified
+	return "", "", fmt.Errorf("no email for notification %s", n)
 }
 
-func (e *EmailNotifier) generateAndSendEmail(s3Bucket string, s3Key string, messageAttributes MessageAttributes, recipients []string, subject string) error {
-	log.WithFields(log.Fields{}).Info("EmailNotifier.generateAndSendEmail()")
+// render renders the notification's email. The Repository's branding, carried in the message attributes, overrides
+// the default template, subject and logo, and sets the reply-to address.
+func (e *EmailNotifier) render(ctx context.Context, n Notification, messageAttributes MessageAttributes) (*Rendering, error) {
+	subject, s3Key, err := e.defaultEmail(n, messageAttributes)
+	if err != nil {
+		return nil, err
+	}
+	s3Bucket := e.templates.Bucket
+
+	attributes := make(MessageAttributes, len(messageAttributes)+1)
+	for key, value := range messageAttributes {
+		attributes[key] = value
+	}
+	if attributes[LogoURLAttribute] == "" {
+		attributes[LogoURLAttribute] = DefaultLogoURL
+	}
+
+	if key := attributes[TemplateKeyAttribute]; key != "" {
+		if bucket := attributes[TemplateBucketAttribute]; bucket != "" {
+			s3Bucket = bucket
+		}
+		s3Key = key
+	}
+	if override := attributes[SubjectAttribute]; override != "" {
+		rendered, err := renderText("subject", override, attributes)
+		if err != nil {
+			log.WithFields(log.Fields{"subject": override, "error": fmt.Sprintf("%+v", err)}).Error("EmailNotifier.render()")
+			return nil, fmt.Errorf("rendering subject: %w", err)
+		}
+		subject = rendered
+	}
+	var replyTo []string
+	if address := attributes[ReplyToAttribute]; address != "" {
+		replyTo = []string{address}
+	}
+	log.WithFields(log.Fields{
+		"notification":      n,
+		"messageAttributes": fmt.Sprintf("%s", messageAttributes),
+		"subject":           subject,
+		"s3Bucket":          s3Bucket,
+		"s3Key":             s3Key,
+		"replyTo":           replyTo}).Info("EmailNotifier.render()")
 
 	// load email template
-	template, err := e.fileReader.ReadFile(e.ctx, s3Bucket, s3Key)
+	template, err := e.cache.get(ctx, s3Bucket, s3Key)
 	if err != nil {
-		log.WithFields(log.Fields{"error": fmt.Sprintf("%+v", err)}).Error("EmailNotifier.generateAndSendEmail()")
-		return err
+		log.WithFields(log.Fields{"error": fmt.Sprintf("%+v", err)}).Error("EmailNotifier.render()")
+		return nil, err
+	}
+
+	// substitute values, escaped, and derive the plain-text alternative
+	body, err := renderHTML(template, attributes)
+	if err != nil {
+		log.WithFields(log.Fields{"template": template.Name(), "error": fmt.Sprintf("%+v", err)}).Error("EmailNotifier.render()")
+		return nil, fmt.Errorf("rendering %s: %w", template.Name(), err)
 	}
 
-	// substitute values
-	body := e.replaceTemplateFields(template, messageAttributes)
+	return &Rendering{Subject: subject, HTML: body, Text: plainText(body), ReplyTo: replyTo}, nil
+}
+
+// Preview renders the notification's email without sending it
+func (e *EmailNotifier) Preview(ctx context.Context, n Notification, messageAttributes MessageAttributes) (*Rendering, error) {
+	log.WithFields(log.Fields{"notification": n}).Info("EmailNotifier.Preview()")
+	return e.render(ctx, n, messageAttributes)
+}
+
+// generateAndSendEmail renders the notification's email and sends it, tagged with the notification
+func (e *EmailNotifier) generateAndSendEmail(ctx context.Context, n Notification, messageAttributes MessageAttributes, recipients []string) error {
+	log.WithFields(log.Fields{"notification": n, "recipients": recipients}).Info("EmailNotifier.generateAndSendEmail()")
+
+	rendering, err := e.render(ctx, n, messageAttributes)
+	if err != nil {
+		return err
+	}
 
 	// send email
-	err = e.emailAgent.SendMessage(e.ctx, e.sender, recipients, subject, body, sesTypes.HTML)
+	results, err := e.emailAgent.Send(ctx, sesTypes.Email{
+		Sender:     e.sender,
+		Recipients: recipients,
+		ReplyTo:    rendering.ReplyTo,
+		Subject:    rendering.Subject,
+		Body:       sesTypes.Body{HTML: rendering.HTML, Text: rendering.Text},
+		Tags:       map[string]string{"notification": n.String()},
+	})
+	for _, result := range results {
+		if result.Err != nil {
+			log.WithFields(log.Fields{"notification": n, "recipient": result.Recipient, "error": fmt.Sprintf("%+v", result.Err)}).Error("EmailNotifier.generateAndSendEmail()")
+		} else {
+			log.WithFields(log.Fields{"notification": n, "recipient": result.Recipient, "messageId": result.MessageId}).Info("EmailNotifier.generateAndSendEmail()")
+		}
+	}
 
 	return err
 }
 
-func (e *EmailNotifier) ProposalSubmitted(messageAttributes MessageAttributes, recipients []string) error {
-	subject := "A Dataset Proposal has been submitted"
-	s3Bucket := os.Getenv("EMAIL_TEMPLATE_BUCKET")
-	s3Key := os.Getenv("EMAIL_TEMPLATE_SUBMITTED")
-	log.WithFields(log.Fields{
-		"messageAttributes": fmt.Sprintf("%s", messageAttributes),
-		"subject":           subject,
-		"s3Bucket":          s3Bucket,
-		"s3Key":             s3Key,
-		"recipients":        recipients}).Info("EmailNotifier.ProposalSubmitted()")
+func (e *EmailNotifier) ProposalSubmitted(ctx context.Context, messageAttributes MessageAttributes, recipients []string) error {
+	return e.generateAndSendEmail(ctx, Submitted, messageAttributes, recipients)
+}
 
-	return e.generateAndSendEmail(s3Bucket, s3Key, messageAttributes, recipients, subject)
+func (e *EmailNotifier) ProposalWithdrawn(ctx context.Context, messageAttributes MessageAttributes, recipients []string) error {
+	return e.generateAndSendEmail(ctx, Withdrawn, messageAttributes, recipients)
 }
 
-func (e *EmailNotifier) ProposalWithdrawn(messageAttributes MessageAttributes, recipients []string) error {
-	subject := "A Dataset Proposal has been withdrawn"
-	s3Bucket := os.Getenv("EMAIL_TEMPLATE_BUCKET")
-	s3Key := os.Getenv("EMAIL_TEMPLATE_WITHDRAWN")
-	log.WithFields(log.Fields{
-		"messageAttributes": fmt.Sprintf("%s", messageAttributes),
-		"subject":           subject,
-		"s3Bucket":          s3Bucket,
-		"s3Key":             s3Key,
-		"recipients":        recipients}).Info("EmailNotifier.ProposalWithdrawn()")
+func (e *EmailNotifier) ProposalAccepted(ctx context.Context, messageAttributes MessageAttributes, recipients []string) error {
+	return e.generateAndSendEmail(ctx, Accepted, messageAttributes, recipients)
+}
 
-	return e.generateAndSendEmail(s3Bucket, s3Key, messageAttributes, recipients, subject)
+func (e *EmailNotifier) ProposalRejected(ctx context.Context, messageAttributes MessageAttributes, recipients []string) error {
+	return e.generateAndSendEmail(ctx, Rejected, messageAttributes, recipients)
 }
 
-func (e *EmailNotifier) ProposalAccepted(messageAttributes MessageAttributes, recipients []string) error {
-	subject := "Your Dataset Proposal has been accepted"
-	s3Bucket := os.Getenv("EMAIL_TEMPLATE_BUCKET")
-	s3Key := os.Getenv("EMAIL_TEMPLATE_ACCEPTED")
-	log.WithFields(log.Fields{
-		"messageAttributes": fmt.Sprintf("%s", messageAttributes),
-		"subject":           subject,
-		"s3Bucket":          s3Bucket,
-		"s3Key":             s3Key,
-		"recipients":        recipients}).Info("EmailNotifier.ProposalAccepted()")
+func (e *EmailNotifier) ProposalDraftReminder(ctx context.Context, messageAttributes MessageAttributes, recipients []string) error {
+	return e.generateAndSendEmail(ctx, DraftReminder, messageAttributes, recipients)
+}
 
-	return e.generateAndSendEmail(s3Bucket, s3Key, messageAttributes, recipients, subject)
+func (e *EmailNotifier) ProposalReviewReminder(ctx context.Context, messageAttributes MessageAttributes, recipients []string) error {
+	return e.generateAndSendEmail(ctx, ReviewReminder, messageAttributes, recipients)
 }
 
-func (e *EmailNotifier) ProposalRejected(messageAttributes MessageAttributes, recipients []string) error {
-	subject := "Your Dataset Proposal has been rejected"
-	s3Bucket := os.Getenv("EMAIL_TEMPLATE_BUCKET")
-	s3Key := os.Getenv("EMAIL_TEMPLATE_REJECTED")
-	log.WithFields(log.Fields{
-		"messageAttributes": fmt.Sprintf("%s", messageAttributes),
-		"subject":           subject,
-		"s3Bucket":          s3Bucket,
-		"s3Key":             s3Key,
-		"recipients":        recipients}).Info("EmailNotifier.ProposalRejected()")
+func (e *EmailNotifier) ProposalReviewEscalation(ctx context.Context, messageAttributes MessageAttributes, recipients []string) error {
+	return e.generateAndSendEmail(ctx, ReviewEscalation, messageAttributes, recipients)
+}
+
+func (e *EmailNotifier) ProposalDigest(ctx context.Context, messageAttributes MessageAttributes, recipients []string) error {
+	return e.generateAndSendEmail(ctx, Digest, messageAttributes, recipients)
+}
+
+func (e *EmailNotifier) ProposalSubmittedReceipt(ctx context.Context, messageAttributes MessageAttributes, recipients []string) error {
+	return e.generateAndSendEmail(ctx, SubmittedReceipt, messageAttributes, recipients)
+}
+
+func (e *EmailNotifier) ProposalWithdrawnReceipt(ctx context.Context, messageAttributes MessageAttributes, recipients []string) error {
+	return e.generateAndSendEmail(ctx, WithdrawnReceipt, messageAttributes, recipients)
+}
+
+func (e *EmailNotifier) ProposalContributorAccepted(ctx context.Context, messageAttributes MessageAttributes, recipients []string) error {
+	return e.generateAndSendEmail(ctx, ContributorAccepted, messageAttributes, recipients)
+}
 
-	return e.generateAndSendEmail(s3Bucket, s3Key, messageAttributes, recipients, subject)
+func (e *EmailNotifier) ProposalAdminCopy(ctx context.Context, messageAttributes MessageAttributes, recipients []string) error {
+	return e.generateAndSendEmail(ctx, AdminCopy, messageAttributes, recipients)
 }
@@ -1,6 +1,10 @@
 package notification
 
-import "fmt"
+import (
+	"context"
+	"errors"
+	"fmt"
+)
 
 type Notification int64
 
@@ -9,8 +13,50 @@ const (
 	Withdrawn
 	Accepted
 	Rejected
+	DraftReminder
+	ReviewReminder
+	ReviewEscalation
+	Digest
+	SubmittedReceipt
+	WithdrawnReceipt
+	ContributorAccepted
+	AdminCopy
 )
 
+var names = map[Notification]string{
+	Submitted:        "ProposalSubmitted",
+	Withdrawn:        "ProposalWithdrawn",
+	Accepted:         "ProposalAccepted",
+	Rejected:         "ProposalRejected",
+	DraftReminder:    "ProposalDraftReminder",
+	ReviewReminder:   "ProposalReviewReminder",
+	ReviewEscalation: "ProposalReviewEscalation",
+	Digest:           "ProposalDigest",
+
+	SubmittedReceipt:    "ProposalSubmittedReceipt",
+	WithdrawnReceipt:    "ProposalWithdrawnReceipt",
+	ContributorAccepted: "ProposalContributorAccepted",
+	AdminCopy:           "ProposalAdminCopy",
+}
+
+// String names the notification after its Notifier method, e.g. ProposalSubmitted
+func (n Notification) String() string {
+	if name, found := names[n]; found {
+		return name
+	}
+	return fmt.Sprintf("Notification(%d)", int64(n))
+}
+
+// ParseNotification returns the notification with the given name
+func ParseNotification(name string) (Notification, error) {
+	for n, known := range names {
+		if known == name {
+			return n, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown notification: %q", name)
+}
+
 type MessageAttributes map[string]string
 
 func (ma MessageAttributes) String() string {
@@ -23,8 +69,57 @@ func (ma MessageAttributes) String() string {
 }
 
 type Notifier interface {
-	ProposalSubmitted(messageAttributes MessageAttributes, recipients []string) error
-	ProposalWithdrawn(messageAttributes MessageAttributes, recipients []string) error
-	ProposalAccepted(messageAttributes MessageAttributes, recipients []string) error
-	ProposalRejected(messageAttributes MessageAttributes, recipients []string) error
+	ProposalSubmitted(ctx context.Context, messageAttributes MessageAttributes, recipients []string) error
+	ProposalWithdrawn(ctx context.Context, messageAttributes MessageAttributes, recipients []string) error
+	ProposalAccepted(ctx context.Context, messageAttributes MessageAttributes, recipients []string) error
+	ProposalRejected(ctx context.Context, messageAttributes MessageAttributes, recipients []string) error
+	ProposalDraftReminder(ctx context.Context, messageAttributes MessageAttributes, recipients []string) error
+	ProposalReviewReminder(ctx context.Context, messageAttributes MessageAttributes, recipients []string) error
+	ProposalReviewEscalation(ctx context.Context, messageAttributes MessageAttributes, recipients []string) error
+	ProposalDigest(ctx context.Context, messageAttributes MessageAttributes, recipients []string) error
+	ProposalSubmittedReceipt(ctx context.Context, messageAttributes MessageAttributes, recipients []string) error
+	ProposalWithdrawnReceipt(ctx context.Context, messageAttributes MessageAttributes, recipients []string) error
+	ProposalContributorAccepted(ctx context.Context, messageAttributes MessageAttributes, recipients []string) error
+	ProposalAdminCopy(ctx context.Context, messageAttributes MessageAttributes, recipients []string) error
+}
+
+// Previewer renders a notification's email as it would be sent, without sending it, e.g. the EmailNotifier
+type Previewer interface {
+	Preview(ctx context.Context, n Notification, messageAttributes MessageAttributes) (*Rendering, error)
+}
+
+// ErrNotPreviewable is returned by a Previewer for a notification that is rendered elsewhere, e.g. by the
+// email-service from its own templates, so that it cannot be previewed as it would be sent
+var ErrNotPreviewable = errors.New("notification is rendered by the email-service and cannot be previewed")
+
+// Send sends the notification with the matching Notifier method
+func Send(ctx context.Context, notifier Notifier, n Notification, messageAttributes MessageAttributes, recipients []string) error {
+	switch n {
+	case Submitted:
+		return notifier.ProposalSubmitted(ctx, messageAttributes, recipients)
+	case Withdrawn:
+		return notifier.ProposalWithdrawn(ctx, messageAttributes, recipients)
+	case Accepted:
+		return notifier.ProposalAccepted(ctx, messageAttributes, recipients)
+	case Rejected:
+		return notifier.ProposalRejected(ctx, messageAttributes, recipients)
+	case DraftReminder:
+		return notifier.ProposalDraftReminder(ctx, messageAttributes, recipients)
+	case ReviewReminder:
+		return notifier.ProposalReviewReminder(ctx, messageAttributes, recipients)
+	case ReviewEscalation:
+		return notifier.ProposalReviewEscalation(ctx, messageAttributes, recipients)
+	case Digest:
+		return notifier.ProposalDigest(ctx, messageAttributes, recipients)
+	case SubmittedReceipt:
+		return notifier.ProposalSubmittedReceipt(ctx, messageAttributes, recipients)
+	case WithdrawnReceipt:
+		return notifier.ProposalWithdrawnReceipt(ctx, messageAttributes, recipients)
+	case ContributorAccepted:
+		return notifier.ProposalContributorAccepted(ctx, messageAttributes, recipients)
+	case AdminCopy:
+		return notifier.ProposalAdminCopy(ctx, messageAttributes, recipients)
+	default:
+		return fmt.Errorf("unknown notification: %s", n)
+	}
 }
@@ -2,12 +2,13 @@ package notification
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"os"
+	"time"
 
-	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	emailclient "github.com/pennsieve/email-service/client"
+	"github.com/pennsieve/publishing-service/api/config"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -17,49 +18,97 @@ import (
 // template (from the shared email-templates) and delivers via SES.
 //
 // It is a drop-in replacement for EmailNotifier behind the Notifier interface,
-// so the call sites in api/service do not change.
+// so the call sites in api/service do not change. Notifications the email-service
+// has no template for, and those branded for a Repository, are sent by the fallback Notifier.
 type QueueNotifier struct {
-	ctx    context.Context
-	client *emailclient.Client
+	client   *emailclient.Client
+	timeout  time.Duration
+	fallback Notifier
 }
 
-// NewQueueNotifier constructs a QueueNotifier. EMAIL_SERVICE_QUEUE_URL is the
-// URL of the email-service send queue for the environment.
-func NewQueueNotifier(ctx context.Context) (*QueueNotifier, error) {
-	cfg, err := awsconfig.LoadDefaultConfig(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("loading AWS config for QueueNotifier: %w", err)
-	}
-	queueURL := os.Getenv("EMAIL_SERVICE_QUEUE_URL")
+// NewQueueNotifier constructs a QueueNotifier. queueURL is the URL of the
+// email-service send queue for the environment (EMAIL_SERVICE_QUEUE_URL).
+// fallback, which may be nil, sends the notifications the email-service does not support.
+func NewQueueNotifier(sqsClient *sqs.Client, queueURL string, timeout time.Duration, fallback Notifier) (*QueueNotifier, error) {
 	if queueURL == "" {
-		return nil, fmt.Errorf("EMAIL_SERVICE_QUEUE_URL is not set")
+		return nil, fmt.Errorf("email-service queue URL (EMAIL_SERVICE_QUEUE_URL) is not set")
 	}
 	return &QueueNotifier{
-		ctx:    ctx,
-		client: emailclient.New(sqs.NewFromConfig(cfg), queueURL),
+		client:   emailclient.New(sqsClient, queueURL),
+		timeout:  timeout,
+		fallback: fallback,
 	}, nil
 }
 
+// unsupported reports a notification that the email-service cannot send and there is no fallback for
+func (q *QueueNotifier) unsupported(name string) error {
+	return fmt.Errorf("%s is not supported by the email-service and no fallback notifier is configured", name)
+}
+
+// branded reports whether the notification should be sent by the fallback Notifier because it carries a
+// Repository's branding, which the email-service's shared templates cannot apply. Without a fallback, it is sent
+// by the email-service, unbranded.
+func (q *QueueNotifier) branded(name string, a MessageAttributes) bool {
+	if !Branded(a) {
+		return false
+	}
+	if q.fallback == nil {
+		log.WithFields(log.Fields{"notification": name}).Warn("QueueNotifier: no fallback notifier for a branded notification; sending it unbranded")
+		return false
+	}
+	return true
+}
+
+// Preview renders the notification's email as it would be sent. Those sent by the fallback Notifier are previewed
+// by it, if it is a Previewer; those the email-service sends, with its own templates, return ErrNotPreviewable.
+func (q *QueueNotifier) Preview(ctx context.Context, n Notification, a MessageAttributes) (*Rendering, error) {
+	previewer, ok := q.fallback.(Previewer)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", n, ErrNotPreviewable)
+	}
+
+	switch n {
+	case Submitted, Withdrawn, Accepted, Rejected:
+		if !q.branded(n.String(), a) {
+			return nil, fmt.Errorf("%s: %w", n, ErrNotPreviewable)
+		}
+	}
+	return previewer.Preview(ctx, n, a)
+}
+
 // send enqueues one request per recipient. The email-service handles a "to" of
 // one recipient per message (it dedupes/journals per recipient), so we fan out
-// here, matching the previous SES-per-recipient behavior.
-func (q *QueueNotifier) send(build func(to emailclient.To) emailclient.EmailRequest, recipients []string) error {
+// here, matching the previous SES-per-recipient behavior. A failure for one
+// recipient does not stop the others; the failures are returned together.
+func (q *QueueNotifier) send(ctx context.Context, build func(to emailclient.To) emailclient.EmailRequest, recipients []string) error {
+	var errs []error
 	for _, addr := range recipients {
 		if addr == "" {
 			continue
 		}
 		req := build(emailclient.To{Email: addr})
-		if err := q.client.Send(q.ctx, req); err != nil {
+		if err := q.enqueue(ctx, req); err != nil {
 			log.WithFields(log.Fields{"recipient": addr, "messageId": req.MessageId, "error": fmt.Sprintf("%+v", err)}).
 				Error("QueueNotifier.send()")
-			return err
+			errs = append(errs, fmt.Errorf("%s: %w", addr, err))
 		}
 	}
-	return nil
+	return errors.Join(errs...)
+}
+
+// enqueue sends a single request to the email-service queue, bounded by the SQS timeout
+func (q *QueueNotifier) enqueue(ctx context.Context, req emailclient.EmailRequest) error {
+	ctx, cancel := config.WithTimeout(ctx, q.timeout)
+	defer cancel()
+
+	return q.client.Send(ctx, req)
 }
 
-func (q *QueueNotifier) ProposalSubmitted(a MessageAttributes, recipients []string) error {
-	return q.send(func(to emailclient.To) emailclient.EmailRequest {
+func (q *QueueNotifier) ProposalSubmitted(ctx context.Context, a MessageAttributes, recipients []string) error {
+	if q.branded("ProposalSubmitted", a) {
+		return q.fallback.ProposalSubmitted(ctx, a, recipients)
+	}
+	return q.send(ctx, func(to emailclient.To) emailclient.EmailRequest {
 		return emailclient.DatasetProposalSubmitted(to, emailclient.DatasetProposalSubmittedArgs{
 			AppURL:          a["AppURL"],
 			AuthorEmail:     a["AuthorEmail"],
@@ -71,8 +120,11 @@ func (q *QueueNotifier) ProposalSubmitted(a MessageAttributes, recipients []stri
 	}, recipients)
 }
 
-func (q *QueueNotifier) ProposalWithdrawn(a MessageAttributes, recipients []string) error {
-	return q.send(func(to emailclient.To) emailclient.EmailRequest {
+func (q *QueueNotifier) ProposalWithdrawn(ctx context.Context, a MessageAttributes, recipients []string) error {
+	if q.branded("ProposalWithdrawn", a) {
+		return q.fallback.ProposalWithdrawn(ctx, a, recipients)
+	}
+	return q.send(ctx, func(to emailclient.To) emailclient.EmailRequest {
 		return emailclient.DatasetProposalWithdrawn(to, emailclient.DatasetProposalWithdrawnArgs{
 			AppURL:          a["AppURL"],
 			AuthorEmail:     a["AuthorEmail"],
@@ -84,8 +136,11 @@ func (q *QueueNotifier) ProposalWithdrawn(a MessageAttributes, recipients []stri
 	}, recipients)
 }
 
-func (q *QueueNotifier) ProposalAccepted(a MessageAttributes, recipients []string) error {
-	return q.send(func(to emailclient.To) emailclient.EmailRequest {
+func (q *QueueNotifier) ProposalAccepted(ctx context.Context, a MessageAttributes, recipients []string) error {
+	if q.branded("ProposalAccepted", a) {
+		return q.fallback.ProposalAccepted(ctx, a, recipients)
+	}
+	return q.send(ctx, func(to emailclient.To) emailclient.EmailRequest {
 		return emailclient.DatasetProposalAccepted(to, emailclient.DatasetProposalAcceptedArgs{
 			AppURL:                 a["AppURL"],
 			ProposalTitle:          a["ProposalTitle"],
@@ -95,8 +150,11 @@ func (q *QueueNotifier) ProposalAccepted(a MessageAttributes, recipients []strin
 	}, recipients)
 }
 
-func (q *QueueNotifier) ProposalRejected(a MessageAttributes, recipients []string) error {
-	return q.send(func(to emailclient.To) emailclient.EmailRequest {
+func (q *QueueNotifier) ProposalRejected(ctx context.Context, a MessageAttributes, recipients []string) error {
+	if q.branded("ProposalRejected", a) {
+		return q.fallback.ProposalRejected(ctx, a, recipients)
+	}
+	return q.send(ctx, func(to emailclient.To) emailclient.EmailRequest {
 		return emailclient.DatasetProposalRejected(to, emailclient.DatasetProposalRejectedArgs{
 			AppURL:                 a["AppURL"],
 			ProposalTitle:          a["ProposalTitle"],
@@ -105,3 +163,67 @@ func (q *QueueNotifier) ProposalRejected(a MessageAttributes, recipients []strin
 		})
 	}, recipients)
 }
+
+// ProposalDraftReminder is sent by the fallback Notifier; the email-service has no draft reminder template
+func (q *QueueNotifier) ProposalDraftReminder(ctx context.Context, a MessageAttributes, recipients []string) error {
+	if q.fallback == nil {
+		return q.unsupported("ProposalDraftReminder")
+	}
+	return q.fallback.ProposalDraftReminder(ctx, a, recipients)
+}
+
+// ProposalReviewReminder is sent by the fallback Notifier; the email-service has no review reminder template
+func (q *QueueNotifier) ProposalReviewReminder(ctx context.Context, a MessageAttributes, recipients []string) error {
+	if q.fallback == nil {
+		return q.unsupported("ProposalReviewReminder")
+	}
+	return q.fallback.ProposalReviewReminder(ctx, a, recipients)
+}
+
+// ProposalReviewEscalation is sent by the fallback Notifier; the email-service has no review escalation template
+func (q *QueueNotifier) ProposalReviewEscalation(ctx context.Context, a MessageAttributes, recipients []string) error {
+	if q.fallback == nil {
+		return q.unsupported("ProposalReviewEscalation")
+	}
+	return q.fallback.ProposalReviewEscalation(ctx, a, recipients)
+}
+
+// ProposalDigest is sent by the fallback Notifier; the email-service has no digest template
+func (q *QueueNotifier) ProposalDigest(ctx context.Context, a MessageAttributes, recipients []string) error {
+	if q.fallback == nil {
+		return q.unsupported("ProposalDigest")
+	}
+	return q.fallback.ProposalDigest(ctx, a, recipients)
+}
+
+// ProposalSubmittedReceipt is sent by the fallback Notifier; the email-service has no submission receipt template
+func (q *QueueNotifier) ProposalSubmittedReceipt(ctx context.Context, a MessageAttributes, recipients []string) error {
+	if q.fallback == nil {
+		return q.unsupported("ProposalSubmittedReceipt")
+	}
+	return q.fallback.ProposalSubmittedReceipt(ctx, a, recipients)
+}
+
+// ProposalWithdrawnReceipt is sent by the fallback Notifier; the email-service has no withdrawal receipt template
+func (q *QueueNotifier) ProposalWithdrawnReceipt(ctx context.Context, a MessageAttributes, recipients []string) error {
+	if q.fallback == nil {
+		return q.unsupported("ProposalWithdrawnReceipt")
+	}
+	return q.fallback.ProposalWithdrawnReceipt(ctx, a, recipients)
+}
+
+// ProposalContributorAccepted is sent by the fallback Notifier; the email-service has no contributor template
+func (q *QueueNotifier) ProposalContributorAccepted(ctx context.Context, a MessageAttributes, recipients []string) error {
+	if q.fallback == nil {
+		return q.unsupported("ProposalContributorAccepted")
+	}
+	return q.fallback.ProposalContributorAccepted(ctx, a, recipients)
+}
+
+// ProposalAdminCopy is sent by the fallback Notifier; the email-service has no workspace admin template
+func (q *QueueNotifier) ProposalAdminCopy(ctx context.Context, a MessageAttributes, recipients []string) error {
+	if q.fallback == nil {
+		return q.unsupported("ProposalAdminCopy")
+	}
+	return q.fallback.ProposalAdminCopy(ctx, a, recipients)
+}
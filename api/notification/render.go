@@ -0,0 +1,203 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"golang.org/x/net/html"
+	htmlTemplate "html/template"
+	"regexp"
+	"strings"
+	"sync"
+	textTemplate "text/template"
+	"time"
+)
+
+// TemplateReader reads an email template, e.g. the s3.FileReader
+type TemplateReader interface {
+	ReadFile(ctx context.Context, s3Bucket string, s3Key string) (string, error)
+}
+
+// htmlAttributes hold HTML built by the Publishing Service, which escapes the values it puts in it, e.g. the list
+// of proposals in a review reminder. They are rendered as they are; every other attribute is escaped.
+var htmlAttributes = map[string]bool{
+	"Proposals": true,
+	"Events":    true,
+}
+
+var (
+	placeholder = regexp.MustCompile(`\$\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}`)
+	comment     = regexp.MustCompile(`(?s)<!--.*?-->`)
+)
+
+// parseTemplate parses an email template, in which ${Key} is replaced by the message attribute Key. A template
+// that uses an attribute that is not in the message attributes fails to render. The comments in the template, which
+// html/template would strip, are kept, since the conditional comments lay out the email in Outlook.
+func parseTemplate(name string, source string) (*htmlTemplate.Template, error) {
+	var comments []string
+	source = comment.ReplaceAllStringFunc(source, func(c string) string {
+		comments = append(comments, c)
+		return fmt.Sprintf("${comment %d}", len(comments)-1)
+	})
+	source = placeholder.ReplaceAllString(source, "${.$1}")
+
+	return htmlTemplate.New(name).
+		Delims("${", "}").
+		Option("missingkey=error").
+		Funcs(htmlTemplate.FuncMap{"comment": func(i int) htmlTemplate.HTML {
+			return htmlTemplate.HTML(comments[i])
+		}}).
+		Parse(source)
+}
+
+// renderHTML renders the parsed template with the message attributes
+func renderHTML(template *htmlTemplate.Template, messageAttributes MessageAttributes) (string, error) {
+	data := make(map[string]any, len(messageAttributes))
+	for key, value := range messageAttributes {
+		if htmlAttributes[key] {
+			data[key] = htmlTemplate.HTML(value)
+		} else {
+			data[key] = value
+		}
+	}
+
+	var body bytes.Buffer
+	if err := template.Execute(&body, data); err != nil {
+		return "", err
+	}
+	return body.String(), nil
+}
+
+// renderText renders a plain-text template, e.g. a subject line, with the message attributes
+func renderText(name string, source string, messageAttributes MessageAttributes) (string, error) {
+	template, err := textTemplate.New(name).
+		Delims("${", "}").
+		Option("missingkey=error").
+		Parse(placeholder.ReplaceAllString(source, "${.$1}"))
+	if err != nil {
+		return "", err
+	}
+
+	var text bytes.Buffer
+	if err := template.Execute(&text, map[string]string(messageAttributes)); err != nil {
+		return "", err
+	}
+	return text.String(), nil
+}
+
+// blockElements start a new line in the plain-text part
+var blockElements = map[string]bool{
+	"br": true, "div": true, "h1": true, "h2": true, "h3": true, "h4": true, "li": true,
+	"p": true, "table": true, "tr": true, "ul": true, "ol": true,
+}
+
+// skippedElements have no text in the plain-text part
+var skippedElements = map[string]bool{"head": true, "script": true, "style": true, "title": true}
+
+var blankLines = regexp.MustCompile(`\n{3,}`)
+
+// plainText is the plain-text alternative of a rendered HTML email: its text, a line for each block, and the
+// address of each link after its text
+func plainText(body string) string {
+	var text strings.Builder
+	var href string
+	skipping := 0
+
+	tokenizer := html.NewTokenizer(strings.NewReader(body))
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			lines := strings.Split(text.String(), "\n")
+			for i, line := range lines {
+				lines[i] = strings.Join(strings.Fields(line), " ")
+			}
+			return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			switch {
+			case skippedElements[token.Data]:
+				skipping++
+			case token.Data == "a":
+				for _, attribute := range token.Attr {
+					if attribute.Key == "href" {
+						href = attribute.Val
+					}
+				}
+			case token.Data == "li":
+				text.WriteString("\n- ")
+			case blockElements[token.Data]:
+				text.WriteString("\n")
+			}
+		case html.EndTagToken:
+			token := tokenizer.Token()
+			switch {
+			case skippedElements[token.Data]:
+				if skipping > 0 {
+					skipping--
+				}
+			case token.Data == "a" && href != "":
+				text.WriteString(fmt.Sprintf(" (%s)", href))
+				href = ""
+			case blockElements[token.Data]:
+				text.WriteString("\n")
+			}
+		case html.TextToken:
+			if skipping == 0 {
+				text.WriteString(strings.ReplaceAll(html.UnescapeString(string(tokenizer.Text())), "\n", " "))
+			}
+		}
+	}
+}
+
+// templateCache holds the parsed email templates, so that they are not read on every send. A template is
+// read again once it has been cached for the TTL, so that a change to it is picked up; a TTL of zero caches
+// templates until the cache is dropped.
+type templateCache struct {
+	reader  TemplateReader
+	ttl     time.Duration
+	now     func() time.Time
+	mu      sync.Mutex
+	entries map[string]cachedTemplate
+}
+
+type cachedTemplate struct {
+	template *htmlTemplate.Template
+	readAt   time.Time
+}
+
+func newTemplateCache(reader TemplateReader, ttl time.Duration) *templateCache {
+	return &templateCache{
+		reader:  reader,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]cachedTemplate),
+	}
+}
+
+// get returns the parsed template, reading it if it is not cached, or its TTL has passed
+func (c *templateCache) get(ctx context.Context, s3Bucket string, s3Key string) (*htmlTemplate.Template, error) {
+	name := fmt.Sprintf("s3://%s/%s", s3Bucket, s3Key)
+	now := c.now()
+
+	c.mu.Lock()
+	entry, found := c.entries[name]
+	c.mu.Unlock()
+	if found && (c.ttl == 0 || now.Sub(entry.readAt) < c.ttl) {
+		return entry.template, nil
+	}
+
+	source, err := c.reader.ReadFile(ctx, s3Bucket, s3Key)
+	if err != nil {
+		return nil, fmt.Errorf("reading template %s: %w", name, err)
+	}
+	template, err := parseTemplate(name, source)
+	if err != nil {
+		return nil, fmt.Errorf("parsing template %s: %w", name, err)
+	}
+
+	c.mu.Lock()
+	c.entries[name] = cachedTemplate{template: template, readAt: now}
+	c.mu.Unlock()
+
+	return template, nil
+}
@@ -0,0 +1,221 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"github.com/pennsieve/publishing-service/api/config"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeReader serves templates from memory, counting the reads
+type fakeReader struct {
+	templates map[string]string
+	reads     int
+}
+
+func (r *fakeReader) ReadFile(ctx context.Context, s3Bucket string, s3Key string) (string, error) {
+	r.reads++
+	return r.templates[s3Key], nil
+}
+
+func render(t *testing.T, source string, messageAttributes MessageAttributes) (string, error) {
+	t.Helper()
+	template, err := parseTemplate("test", source)
+	if err != nil {
+		t.Fatalf("parseTemplate() error: %v", err)
+	}
+	return renderHTML(template, messageAttributes)
+}
+
+func TestRenderEscapesAttributes(t *testing.T) {
+	source := `<h1>${ProposalTitle}</h1><p>by ${ AuthorName }</p><a href="https://${AppURL}/submit/">View</a><ul>${Proposals}</ul>`
+	body, err := render(t, source, MessageAttributes{
+		"ProposalTitle": `<script>alert("title")</script>`,
+		"AuthorName":    `Ada <b>Lovelace</b>`,
+		"AppURL":        "app.pennsieve.net",
+		"Proposals":     "<li><strong>Proposal</strong></li>",
+	})
+	if err != nil {
+		t.Fatalf("renderHTML() error: %v", err)
+	}
+
+	for _, unwanted := range []string{"<script>", "<b>"} {
+		if strings.Contains(body, unwanted) {
+			t.Errorf("body has %s, want it escaped: %s", unwanted, body)
+		}
+	}
+	for _, wanted := range []string{
+		"&lt;script&gt;alert(&#34;title&#34;)&lt;/script&gt;",
+		"by Ada &lt;b&gt;Lovelace&lt;/b&gt;",
+		`href="https://app.pennsieve.net/submit/"`,
+		"<ul><li><strong>Proposal</strong></li></ul>",
+	} {
+		if !strings.Contains(body, wanted) {
+			t.Errorf("body does not have %s: %s", wanted, body)
+		}
+	}
+}
+
+func TestRenderFailsOnMissingAttribute(t *testing.T) {
+	if body, err := render(t, `<h1>${ProposalTitle}</h1>`, MessageAttributes{"AuthorName": "Ada"}); err == nil {
+		t.Errorf("renderHTML() = %s, want an error for the missing ProposalTitle", body)
+	}
+	if subject, err := renderText("subject", "New proposal: ${ProposalTitle}", MessageAttributes{}); err == nil {
+		t.Errorf("renderText() = %s, want an error for the missing ProposalTitle", subject)
+	}
+}
+
+func TestRenderKeepsConditionalComments(t *testing.T) {
+	comment := `<!--[if mso | IE]><table role="presentation"><tr><td style="width:600px;" ><![endif]-->`
+	body, err := render(t, "<div>"+comment+"${WorkspaceName}</div>", MessageAttributes{"WorkspaceName": "Repository"})
+	if err != nil {
+		t.Fatalf("renderHTML() error: %v", err)
+	}
+	if body != "<div>"+comment+"Repository</div>" {
+		t.Errorf("body = %s, want the comment kept", body)
+	}
+}
+
+func TestMessageTemplatesRender(t *testing.T) {
+	files, err := filepath.Glob("../../message-templates/html/dataset-proposal-*.html")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no message templates found (error: %v)", err)
+	}
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			source, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatalf("reading template: %v", err)
+			}
+
+			// give every attribute the template uses a value that must be escaped, but for the HTML ones
+			messageAttributes := MessageAttributes{}
+			for _, match := range placeholder.FindAllStringSubmatch(string(source), -1) {
+				messageAttributes[match[1]] = "<attribute>" + match[1]
+				if htmlAttributes[match[1]] {
+					messageAttributes[match[1]] = "<li>" + match[1] + "</li>"
+				}
+			}
+			messageAttributes[LogoURLAttribute] = DefaultLogoURL
+
+			body, err := render(t, string(source), messageAttributes)
+			if err != nil {
+				t.Fatalf("renderHTML() error: %v", err)
+			}
+			if strings.Contains(body, "${") || strings.Contains(body, "<attribute>") {
+				t.Errorf("body has an unrendered or unescaped attribute")
+			}
+			if strings.Count(body, "<!--") != strings.Count(string(source), "<!--") {
+				t.Errorf("body has %d comments, want %d", strings.Count(body, "<!--"), strings.Count(string(source), "<!--"))
+			}
+
+			text := plainText(body)
+			if strings.Contains(text, "<table") || strings.Contains(text, "mso") || strings.Contains(text, "{") {
+				t.Errorf("plain text has markup: %s", text)
+			}
+		})
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	body := `<html><head><title>Email</title><style>h1 { color: red; }</style></head><body>
+<h1>Proposal Accepted</h1>
+<div>Your proposal &amp; its    dataset</div>
+<ul><li>One</li><li>Two</li></ul>
+<a href="https://app.pennsieve.net/submit/">View Your Dataset Proposals</a>
+</body></html>`
+
+	want := "Proposal Accepted\n\nYour proposal & its dataset\n\n- One\n\n- Two\n\nView Your Dataset Proposals (https://app.pennsieve.net/submit/)"
+	if text := plainText(body); text != want {
+		t.Errorf("plainText() = %q, want %q", text, want)
+	}
+}
+
+func TestTemplateCache(t *testing.T) {
+	reader := &fakeReader{templates: map[string]string{"submitted.html": "<h1>${ProposalTitle}</h1>"}}
+	cache := newTemplateCache(reader, time.Minute)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if _, err := cache.get(context.Background(), "bucket", "submitted.html"); err != nil {
+			t.Fatalf("get() error: %v", err)
+		}
+	}
+	if reader.reads != 1 {
+		t.Errorf("read the template %d times, want it cached after the first", reader.reads)
+	}
+
+	now = now.Add(time.Minute)
+	if _, err := cache.get(context.Background(), "bucket", "submitted.html"); err != nil {
+		t.Fatalf("get() error: %v", err)
+	}
+	if reader.reads != 2 {
+		t.Errorf("read the template %d times, want it read again after the TTL", reader.reads)
+	}
+}
+
+func TestPreviewUsesBranding(t *testing.T) {
+	reader := &fakeReader{templates: map[string]string{
+		"accepted.html":            `<img src="${LogoURL}"><h1>${ProposalTitle}</h1>`,
+		"repository/accepted.html": `<img src="${LogoURL}"><h1>Welcome to ${WorkspaceName}</h1>`,
+	}}
+	notifier := &EmailNotifier{
+		templates: config.EmailTemplates{Bucket: "templates", Accepted: "accepted.html"},
+		cache:     newTemplateCache(reader, 0),
+	}
+	messageAttributes := MessageAttributes{"ProposalTitle": "Proposal", "WorkspaceName": "Repository"}
+
+	rendering, err := notifier.Preview(context.Background(), Accepted, messageAttributes)
+	if err != nil {
+		t.Fatalf("Preview() error: %v", err)
+	}
+	if rendering.Subject != "Your Dataset Proposal has been accepted" || !strings.Contains(rendering.HTML, DefaultLogoURL) || rendering.Text != "Proposal" || rendering.ReplyTo != nil {
+		t.Errorf("Preview() = %+v, want the default subject, template and logo", rendering)
+	}
+
+	messageAttributes[SubjectAttribute] = "Accepted by ${WorkspaceName}"
+	messageAttributes[ReplyToAttribute] = "curators@repository.org"
+	messageAttributes[TemplateKeyAttribute] = "repository/accepted.html"
+	rendering, err = notifier.Preview(context.Background(), Accepted, messageAttributes)
+	if err != nil {
+		t.Fatalf("Preview() error: %v", err)
+	}
+	if rendering.Subject != "Accepted by Repository" || rendering.Text != "Welcome to Repository" || len(rendering.ReplyTo) != 1 || rendering.ReplyTo[0] != "curators@repository.org" {
+		t.Errorf("Preview() = %+v, want the Repository's subject, template and reply-to", rendering)
+	}
+}
+
+func TestQueueNotifierPreviewsWhatItSends(t *testing.T) {
+	reader := &fakeReader{templates: map[string]string{
+		"accepted.html":       `<h1>${ProposalTitle}</h1>`,
+		"draft-reminder.html": `<h1>${ProposalTitle}</h1>`,
+	}}
+	notifier := &QueueNotifier{fallback: &EmailNotifier{
+		templates: config.EmailTemplates{Bucket: "templates", Accepted: "accepted.html", DraftReminder: "draft-reminder.html"},
+		cache:     newTemplateCache(reader, 0),
+	}}
+	messageAttributes := MessageAttributes{"ProposalTitle": "Proposal", "WorkspaceName": "Repository", "ExpiresOn": "today"}
+
+	// the email-service sends an unbranded notification, with its own template
+	if _, err := notifier.Preview(context.Background(), Accepted, messageAttributes); !errors.Is(err, ErrNotPreviewable) {
+		t.Errorf("Preview(Accepted) error = %v, want ErrNotPreviewable", err)
+	}
+	if _, err := notifier.Preview(context.Background(), DraftReminder, messageAttributes); err != nil {
+		t.Errorf("Preview(DraftReminder) error: %v", err)
+	}
+
+	messageAttributes[ReplyToAttribute] = "curators@repository.org"
+	if _, err := notifier.Preview(context.Background(), Accepted, messageAttributes); err != nil {
+		t.Errorf("Preview(Accepted) of a branded notification error: %v", err)
+	}
+
+	if _, err := (&QueueNotifier{}).Preview(context.Background(), DraftReminder, messageAttributes); !errors.Is(err, ErrNotPreviewable) {
+		t.Errorf("Preview() without a fallback error = %v, want ErrNotPreviewable", err)
+	}
+}
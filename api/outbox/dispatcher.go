@@ -0,0 +1,257 @@
+// Package outbox delivers the notifications that the Publishing Service records in its outbox. A message
+// is written in the same transaction as the Dataset Proposal change it reports, then delivered to each
+// recipient, with retries, until it is delivered to all of them or given up on as dead.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/pennsieve/publishing-service/api/config"
+	"github.com/pennsieve/publishing-service/api/events"
+	"github.com/pennsieve/publishing-service/api/models"
+	"github.com/pennsieve/publishing-service/api/notification"
+	"github.com/pennsieve/publishing-service/api/store"
+	"github.com/pennsieve/publishing-service/api/webhook"
+	log "github.com/sirupsen/logrus"
+	"time"
+)
+
+// NewMessage creates a pending outbox message emailing the notification. Its first delivery is attempted
+// by the request that writes it; the Dispatcher's run picks it up once the lease on that attempt has passed.
+func NewMessage(n notification.Notification, proposalNodeId string, messageAttributes notification.MessageAttributes, recipients []string, policy config.Outbox, now time.Time) models.OutboxMessage {
+	message := newMessage(models.ChannelEmail, n.String(), proposalNodeId, recipients, policy, now)
+	message.MessageAttributes = messageAttributes
+	return message
+}
+
+// NewWebhookMessage creates a pending outbox message POSTing the event payload to the Repository's Webhooks
+func NewWebhookMessage(n notification.Notification, proposalNodeId string, orgNodeId string, payload []byte, webhookIds []string, policy config.Outbox, now time.Time) models.OutboxMessage {
+	message := newMessage(models.ChannelWebhook, n.String(), proposalNodeId, webhookIds, policy, now)
+	message.OrganizationNodeId = orgNodeId
+	message.Payload = string(payload)
+	return message
+}
+
+// EventBusRecipient is the only recipient of an event message
+const EventBusRecipient = "event-bus"
+
+// NewEventMessage creates a pending outbox message publishing the domain event payload to the event bus
+func NewEventMessage(proposalNodeId string, payload []byte, policy config.Outbox, now time.Time) models.OutboxMessage {
+	message := newMessage(models.ChannelEvent, "", proposalNodeId, []string{EventBusRecipient}, policy, now)
+	message.Payload = string(payload)
+	return message
+}
+
+func newMessage(channel string, n string, proposalNodeId string, recipients []string, policy config.Outbox, now time.Time) models.OutboxMessage {
+	message := models.OutboxMessage{
+		Id:             uuid.NewString(),
+		Channel:        channel,
+		ProposalNodeId: proposalNodeId,
+		Notification:   n,
+		Status:         models.OutboxPending,
+		NextAttemptAt:  now.Add(policy.Lease).Unix(),
+		Version:        1,
+		CreatedAt:      now.Unix(),
+		UpdatedAt:      now.Unix(),
+	}
+	for _, recipient := range recipients {
+		if recipient == "" {
+			continue
+		}
+		message.Recipients = append(message.Recipients, models.OutboxRecipient{
+			Address: recipient,
+			Status:  models.OutboxPending,
+		})
+	}
+	return message
+}
+
+// DispatchResult counts what a run of the Dispatcher did
+type DispatchResult struct {
+	Delivered int `json:"delivered"`
+	Retrying  int `json:"retrying"`
+	Dead      int `json:"dead"`
+	Skipped   int `json:"skipped"`
+}
+
+// Dispatcher delivers outbox messages through the Notifier, or Publisher, of their channel. Without a
+// webhook.Notifier or events.Publisher, messages of that channel are not delivered, and are retried.
+type Dispatcher struct {
+	store     store.PublishingStore
+	notifier  notification.Notifier
+	webhooks  webhook.Notifier
+	publisher events.Publisher
+	policy    config.Outbox
+}
+
+func NewDispatcher(store store.PublishingStore, notifier notification.Notifier, webhooks webhook.Notifier, publisher events.Publisher, policy config.Outbox) *Dispatcher {
+	return &Dispatcher{
+		store:     store,
+		notifier:  notifier,
+		webhooks:  webhooks,
+		publisher: publisher,
+		policy:    policy,
+	}
+}
+
+// Run delivers every pending message that is due as of now
+func (d *Dispatcher) Run(ctx context.Context, now time.Time) (*DispatchResult, error) {
+	log.WithFields(log.Fields{"now": now}).Info("Dispatcher.Run()")
+
+	messages, err := d.store.GetDueOutboxMessages(ctx, now.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("getting due outbox messages: %w", err)
+	}
+
+	result := &DispatchResult{}
+	for i := range messages {
+		message := &messages[i]
+		err := d.Deliver(ctx, message, now)
+		switch {
+		case errors.Is(err, store.ErrConflict):
+			result.Skipped++
+		case err != nil && message.Status == models.OutboxPending:
+			result.Retrying++
+		case message.Status == models.OutboxDead:
+			result.Dead++
+		case message.Status == models.OutboxDelivered:
+			result.Delivered++
+		default:
+			result.Retrying++
+		}
+	}
+
+	log.WithFields(log.Fields{"result": fmt.Sprintf("%+v", result)}).Info("Dispatcher.Run()")
+	return result, nil
+}
+
+// Deliver attempts to deliver the message to each of its pending recipients, and records the outcome.
+// The message is first leased, so that it is not delivered concurrently; if another delivery has changed
+// it since it was read, Deliver returns store.ErrConflict without delivering it. An error is returned
+// when the message was not delivered to every recipient; it is retried, or is dead, according to the policy.
+func (d *Dispatcher) Deliver(ctx context.Context, message *models.OutboxMessage, now time.Time) error {
+	var n notification.Notification
+	if message.Channel != models.ChannelEvent {
+		var err error
+		if n, err = notification.ParseNotification(message.Notification); err != nil {
+			d.bury(message, err)
+			return d.save(ctx, message, now, err)
+		}
+	}
+
+	// lease the message for the duration of the delivery
+	message.NextAttemptAt = now.Add(d.policy.Lease).Unix()
+	if err := d.save(ctx, message, now, nil); err != nil {
+		return err
+	}
+
+	message.Attempts++
+	var errs []error
+	for i := range message.Recipients {
+		recipient := &message.Recipients[i]
+		if recipient.Status != models.OutboxPending {
+			continue
+		}
+
+		recipient.Attempts++
+		err := d.send(ctx, n, message, recipient)
+		switch {
+		case err == nil:
+			recipient.Status = models.OutboxDelivered
+			recipient.DeliveredAt = now.Unix()
+			recipient.LastError = ""
+		case errors.Is(err, webhook.ErrInactive):
+			recipient.Status = models.OutboxSkipped
+			recipient.LastError = err.Error()
+		case recipient.Attempts >= d.policy.MaxAttempts:
+			recipient.Status = models.OutboxDead
+			recipient.LastError = err.Error()
+			errs = append(errs, fmt.Errorf("%s: %w", recipient.Address, err))
+		default:
+			recipient.LastError = err.Error()
+			errs = append(errs, fmt.Errorf("%s: %w", recipient.Address, err))
+		}
+	}
+	d.settle(message, now)
+
+	return d.save(ctx, message, now, errors.Join(errs...))
+}
+
+// send delivers the message to a single recipient
+func (d *Dispatcher) send(ctx context.Context, n notification.Notification, message *models.OutboxMessage, recipient *models.OutboxRecipient) error {
+	switch message.Channel {
+	case models.ChannelWebhook:
+		if d.webhooks == nil {
+			return fmt.Errorf("webhook delivery is not configured")
+		}
+		return d.webhooks.Deliver(ctx, webhook.Delivery{
+			OrganizationNodeId: message.OrganizationNodeId,
+			WebhookId:          recipient.Address,
+			Payload:            []byte(message.Payload),
+			Attempt:            recipient.Attempts,
+		})
+	case models.ChannelEvent:
+		if d.publisher == nil {
+			return fmt.Errorf("event publishing is not configured")
+		}
+		var event events.Event
+		if err := json.Unmarshal([]byte(message.Payload), &event); err != nil {
+			return fmt.Errorf("invalid event payload: %w", err)
+		}
+		return d.publisher.Publish(ctx, event)
+	default:
+		return notification.Send(ctx, d.notifier, n, message.MessageAttributes, []string{recipient.Address})
+	}
+}
+
+// settle sets the status of the message from those of its recipients
+func (d *Dispatcher) settle(message *models.OutboxMessage, now time.Time) {
+	pending, dead := 0, 0
+	for _, recipient := range message.Recipients {
+		switch recipient.Status {
+		case models.OutboxPending:
+			pending++
+		case models.OutboxDead:
+			dead++
+		}
+	}
+
+	switch {
+	case pending > 0:
+		message.Status = models.OutboxPending
+		message.NextAttemptAt = now.Add(d.policy.Backoff(message.Attempts)).Unix()
+	case dead > 0:
+		d.bury(message, fmt.Errorf("%d of %d recipients were not delivered", dead, len(message.Recipients)))
+	default:
+		message.Status = models.OutboxDelivered
+		message.ExpiresAt = now.Add(d.policy.Retention).Unix()
+	}
+}
+
+// bury marks the message dead; dead messages are kept, without a TTL, as the dead-letter record
+func (d *Dispatcher) bury(message *models.OutboxMessage, cause error) {
+	message.Status = models.OutboxDead
+	log.WithFields(log.Fields{
+		"id":             message.Id,
+		"notification":   message.Notification,
+		"proposalNodeId": message.ProposalNodeId,
+		"attempts":       message.Attempts,
+		"error":          fmt.Sprintf("%+v", cause),
+	}).Error("Dispatcher.bury()")
+}
+
+// save writes the message as the next version of the one that was read, and returns the delivery error
+func (d *Dispatcher) save(ctx context.Context, message *models.OutboxMessage, now time.Time, deliveryErr error) error {
+	message.Version++
+	message.UpdatedAt = now.Unix()
+	if err := d.store.PutOutboxMessage(ctx, message, message.Version-1); err != nil {
+		if !errors.Is(err, store.ErrConflict) {
+			log.WithFields(log.Fields{"id": message.Id, "error": fmt.Sprintf("%+v", err)}).Error("Dispatcher.save()")
+		}
+		return errors.Join(deliveryErr, err)
+	}
+	return deliveryErr
+}
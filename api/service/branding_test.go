@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"github.com/pennsieve/publishing-service/api/config"
+	"github.com/pennsieve/publishing-service/api/inmemory"
+	"github.com/pennsieve/publishing-service/api/models"
+	"github.com/pennsieve/publishing-service/api/notification"
+	"testing"
+)
+
+// brandRepository replaces the test Repository with one that has the given branding and a logo
+func brandRepository(t *testing.T, s *publishingService, branding *models.EmailBranding) {
+	t.Helper()
+	pubStore := s.store.(*inmemory.PublishingStore)
+	repository, err := pubStore.GetRepository(context.Background(), testRepositoryNodeId)
+	if err != nil {
+		t.Fatalf("getting repository: %v", err)
+	}
+	repository.LogoFile = models.S3Location{S3Bucket: "assets", S3Key: "logos/repository.png"}
+	repository.EmailBranding = branding
+	pubStore.AddRepository(*repository)
+	s.logoBaseURL = "https://assets.example.com/"
+}
+
+func TestRepositoryBrandingIsSentWithNotifications(t *testing.T) {
+	service, notifier := newNotificationTestService(t, "DRAFT", config.DefaultNotificationMatrix())
+	brandRepository(t, service, &models.EmailBranding{
+		HeaderLogo: true,
+		ReplyTo:    "curators@repository.org",
+		Subjects:   map[string]string{"ProposalSubmitted": "New ${WorkspaceName} proposal: ${ProposalTitle}"},
+		Templates:  map[string]models.S3Location{"ProposalSubmitted": {S3Bucket: "branded", S3Key: "repository/submitted.html"}},
+	})
+
+	transition(t, service, config.TransitionSubmitted)
+
+	for _, sent := range notifier.Sent() {
+		a := sent.MessageAttributes
+		if a[notification.ReplyToAttribute] != "curators@repository.org" || a[notification.LogoURLAttribute] != "https://assets.example.com/logos/repository.png" {
+			t.Errorf("%s reply-to = %q, logo = %q, want the Repository's", sent.Notification, a[notification.ReplyToAttribute], a[notification.LogoURLAttribute])
+		}
+
+		switch sent.Notification {
+		case notification.Submitted:
+			if a[notification.SubjectAttribute] != "New ${WorkspaceName} proposal: ${ProposalTitle}" || a[notification.TemplateBucketAttribute] != "branded" || a[notification.TemplateKeyAttribute] != "repository/submitted.html" {
+				t.Errorf("%s attributes = %v, want the Repository's subject and template", sent.Notification, a)
+			}
+		default:
+			// the notifications without overrides use the default subject and template
+			if a[notification.SubjectAttribute] != "" || a[notification.TemplateKeyAttribute] != "" {
+				t.Errorf("%s attributes = %v, want the default subject and template", sent.Notification, a)
+			}
+		}
+	}
+	if len(notifier.Sent()) != 2 {
+		t.Errorf("sent %+v, want the submission and its receipt", notifier.Sent())
+	}
+}
+
+func TestRepositoryWithoutBrandingUsesDefaults(t *testing.T) {
+	tests := map[string]*models.EmailBranding{
+		"no branding": nil,
+		"no logo":     {ReplyTo: ""},
+	}
+	for name, branding := range tests {
+		t.Run(name, func(t *testing.T) {
+			service, notifier := newNotificationTestService(t, "SUBMITTED", config.DefaultNotificationMatrix())
+			brandRepository(t, service, branding)
+
+			transition(t, service, config.TransitionAccepted)
+
+			for _, sent := range notifier.Sent() {
+				if notification.Branded(sent.MessageAttributes) {
+					t.Errorf("%s attributes = %v, want no branding", sent.Notification, sent.MessageAttributes)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	pgdbModels "github.com/pennsieve/pennsieve-go-core/pkg/models/pgdb"
+	"github.com/pennsieve/publishing-service/api/config"
+	"github.com/pennsieve/publishing-service/api/dtos"
+	"github.com/pennsieve/publishing-service/api/events"
+	"github.com/pennsieve/publishing-service/api/inmemory"
+	"github.com/pennsieve/publishing-service/api/models"
+	"github.com/pennsieve/publishing-service/api/outbox"
+	"testing"
+	"time"
+)
+
+// newEventTestService creates a test service that publishes events to an in-memory publisher, holding one proposal
+// in the given status with the survey answered, and a Pennsieve store holding the proposal's owner, the Repository's workspace and publishing team, and the Welcome workspace
+func newEventTestService(t *testing.T, status string) (*publishingService, *inmemory.PublishingStore, *inmemory.EventPublisher) {
+	t.Helper()
+
+	service, pubStore, proposal := newTestService(t, status)
+
+	// answer every Repository question, so that the proposal can be submitted
+	proposal.Survey = append(proposal.Survey, models.Survey{QuestionId: 2, Response: "answer 2"})
+	if _, err := pubStore.UpdateDatasetProposal(context.Background(), proposal); err != nil {
+		t.Fatalf("answering survey: %v", err)
+	}
+
+	pennsieve := inmemory.NewPennsieveStore(1)
+	pennsieve.AddUser(pgdbModels.User{Id: 1, NodeId: "N:user:1", Email: "owner@example.com", FirstName: "Owner", LastName: "Name"})
+	pennsieve.AddOrganization(pgdbModels.Organization{Id: 1, Name: "Repository", Slug: "repository", NodeId: testRepositoryNodeId})
+	pennsieve.AddOrganization(pgdbModels.Organization{Id: 2, Name: "Welcome", Slug: "welcome_to_pennsieve", NodeId: "N:organization:welcome"})
+	pennsieve.AddPublishingTeam(models.PublishingTeam{WorkspaceId: 1, TeamId: 1, TeamName: "Publishers", SystemTeamType: "publishers"})
+	service.pennsieve = pennsieve
+
+	publisher := inmemory.NewEventPublisher()
+	service.events = publisher
+	return service, pubStore, publisher
+}
+
+// eventMessages returns the outbox messages on the event channel
+func eventMessages(pubStore *inmemory.PublishingStore) []models.OutboxMessage {
+	var messages []models.OutboxMessage
+	for _, message := range pubStore.OutboxMessages() {
+		if message.Channel == models.ChannelEvent {
+			messages = append(messages, message)
+		}
+	}
+	return messages
+}
+
+// messageEventId returns the Id of the event carried by the outbox message
+func messageEventId(t *testing.T, message models.OutboxMessage) string {
+	t.Helper()
+	var event events.Event
+	if err := json.Unmarshal([]byte(message.Payload), &event); err != nil {
+		t.Fatalf("unmarshalling event payload: %v", err)
+	}
+	return event.Id
+}
+
+func TestLifecycleTransitionsPublishEvents(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    string
+		eventType string
+		transform func(s *publishingService) error
+	}{
+		{"submit", "DRAFT", events.ProposalSubmitted, func(s *publishingService) error {
+			_, err := s.SubmitDatasetProposal(context.Background(), 1, testProposalNodeId)
+			return err
+		}},
+		{"withdraw", "SUBMITTED", events.ProposalWithdrawn, func(s *publishingService) error {
+			_, err := s.WithdrawDatasetProposal(context.Background(), 1, testProposalNodeId)
+			return err
+		}},
+		{"accept", "SUBMITTED", events.ProposalAccepted, func(s *publishingService) error {
+			_, err := s.AcceptDatasetProposal(context.Background(), testRepositoryNodeId, testProposalNodeId)
+			return err
+		}},
+		{"reject", "SUBMITTED", events.ProposalRejected, func(s *publishingService) error {
+			_, err := s.RejectDatasetProposal(context.Background(), testRepositoryNodeId, testProposalNodeId)
+			return err
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service, pubStore, publisher := newEventTestService(t, test.status)
+
+			if err := test.transform(service); err != nil {
+				t.Fatalf("%s error: %v", test.name, err)
+			}
+
+			published := publisher.Published()
+			if len(published) != 1 {
+				t.Fatalf("published %d events, want 1: %+v", len(published), published)
+			}
+			event := published[0]
+			stored := getStored(t, pubStore)
+			if event.Type != test.eventType || event.SchemaVersion != events.SchemaVersion {
+				t.Errorf("event type = %s (version %s), want %s (version %s)", event.Type, event.SchemaVersion, test.eventType, events.SchemaVersion)
+			}
+			if event.Proposal.NodeId != stored.NodeId || event.Proposal.Status != stored.ProposalStatus || event.Proposal.UpdatedAt != stored.UpdatedAt {
+				t.Errorf("event proposal = %+v, want the stored proposal %+v", event.Proposal, stored)
+			}
+			if event.Proposal.DatasetNodeId != stored.DatasetNodeId {
+				t.Errorf("event datasetNodeId = %q, want %q", event.Proposal.DatasetNodeId, stored.DatasetNodeId)
+			}
+
+			messages := eventMessages(pubStore)
+			if len(messages) != 1 || messages[0].Status != models.OutboxDelivered {
+				t.Errorf("event outbox messages = %+v, want one delivered", messages)
+			}
+		})
+	}
+}
+
+func TestAcceptedEventHasDatasetNodeId(t *testing.T) {
+	service, pubStore, publisher := newEventTestService(t, "SUBMITTED")
+
+	if _, err := service.AcceptDatasetProposal(context.Background(), testRepositoryNodeId, testProposalNodeId); err != nil {
+		t.Fatalf("AcceptDatasetProposal() error: %v", err)
+	}
+
+	published := publisher.Published()
+	if len(published) != 1 || published[0].Proposal.DatasetNodeId == "" {
+		t.Fatalf("published %+v, want a ProposalAccepted event with the dataset", published)
+	}
+	if stored := getStored(t, pubStore); published[0].Proposal.DatasetNodeId != stored.DatasetNodeId {
+		t.Errorf("event datasetNodeId = %q, want %q", published[0].Proposal.DatasetNodeId, stored.DatasetNodeId)
+	}
+}
+
+func TestCreateDatasetProposalPublishesProposalCreated(t *testing.T) {
+	service, pubStore, publisher := newEventTestService(t, "DRAFT")
+
+	created, err := service.CreateDatasetProposal(context.Background(), 1, dtos.DatasetProposalCreateRequest{
+		Name:               "New proposal",
+		Description:        "New description",
+		OrganizationNodeId: testRepositoryNodeId,
+		Survey:             []dtos.SurveyDTO{{QuestionId: 1, Response: "answer 1"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateDatasetProposal() error: %v", err)
+	}
+
+	published := publisher.Published()
+	if len(published) != 1 || published[0].Type != events.ProposalCreated {
+		t.Fatalf("published %+v, want one ProposalCreated event", published)
+	}
+	if event := published[0]; event.Proposal.NodeId != created.NodeId || event.Proposal.Status != "DRAFT" || event.Proposal.UserId != 1 {
+		t.Errorf("event proposal = %+v, want the created proposal %+v", event.Proposal, created)
+	}
+	if _, err := pubStore.GetDatasetProposal(context.Background(), 1, created.NodeId); err != nil {
+		t.Errorf("created proposal was not stored: %v", err)
+	}
+}
+
+func TestUnpublishedEventIsRetriedFromOutbox(t *testing.T) {
+	service, pubStore, publisher := newEventTestService(t, "DRAFT")
+	publisher.Err = errors.New("event bus unavailable")
+
+	if _, err := service.SubmitDatasetProposal(context.Background(), 1, testProposalNodeId); err != nil {
+		t.Fatalf("SubmitDatasetProposal() error: %v, want the proposal submitted while the event bus is unavailable", err)
+	}
+	if stored := getStored(t, pubStore); stored.ProposalStatus != "SUBMITTED" {
+		t.Fatalf("proposal status = %s, want SUBMITTED", stored.ProposalStatus)
+	}
+	messages := eventMessages(pubStore)
+	if len(messages) != 1 || messages[0].Status != models.OutboxPending {
+		t.Fatalf("event outbox messages = %+v, want one pending", messages)
+	}
+
+	publisher.Err = nil
+	publisher.Reset()
+	dispatcher := outbox.NewDispatcher(pubStore, inmemory.NewNotifier(), nil, publisher, config.DefaultOutbox())
+	if _, err := dispatcher.Run(context.Background(), time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+
+	published := publisher.Published()
+	if len(published) != 1 || published[0].Type != events.ProposalSubmitted || published[0].Id != messageEventId(t, messages[0]) {
+		t.Errorf("published %+v, want the ProposalSubmitted event of the outbox message", published)
+	}
+	if messages := eventMessages(pubStore); messages[0].Status != models.OutboxDelivered {
+		t.Errorf("event outbox message status = %s, want %s", messages[0].Status, models.OutboxDelivered)
+	}
+}
+
+func TestEventsAreNotPublishedWithoutPublisher(t *testing.T) {
+	service, pubStore, _ := newEventTestService(t, "DRAFT")
+	service.events = nil
+
+	if _, err := service.SubmitDatasetProposal(context.Background(), 1, testProposalNodeId); err != nil {
+		t.Fatalf("SubmitDatasetProposal() error: %v", err)
+	}
+	if messages := eventMessages(pubStore); len(messages) != 0 {
+		t.Errorf("event outbox messages = %+v, want none", messages)
+	}
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"github.com/pennsieve/publishing-service/api/dtos"
+	"github.com/pennsieve/publishing-service/api/models"
+	"slices"
+)
+
+// ProposalField is a client-writable field of a Dataset Proposal
+type ProposalField string
+
+const (
+	FieldName         ProposalField = "name"
+	FieldDescription  ProposalField = "description"
+	FieldSurvey       ProposalField = "survey"
+	FieldContributors ProposalField = "contributors"
+)
+
+// MutableFields are the client-writable fields that an update may change in each Dataset Proposal status.
+// Once a proposal is submitted it is frozen: the publishing team reviews, and accepts or rejects, what was submitted.
+// Every other field (owner, repository, status, linked dataset and timestamps) is owned by the service and is
+// preserved by updates.
+var MutableFields = map[string][]ProposalField{
+	"DRAFT":     {FieldName, FieldDescription, FieldSurvey, FieldContributors},
+	"WITHDRAWN": {FieldName, FieldDescription, FieldSurvey, FieldContributors},
+	"SUBMITTED": {},
+	"ACCEPTED":  {},
+	"REJECTED":  {},
+}
+
+func isMutable(status string, field ProposalField) bool {
+	return slices.Contains(MutableFields[status], field)
+}
+
+// applyUpdate changes the client-writable fields of the stored Dataset Proposal, leaving every other field as it is.
+// A field that the update would change, but which is not mutable in the proposal's status, is reported to the validator.
+func applyUpdate(v *validator, proposal *models.DatasetProposal, update dtos.DatasetProposalUpdateRequest) {
+	var survey []models.Survey
+	for i := 0; i < len(update.Survey); i++ {
+		survey = append(survey, dtos.BuildSurvey(update.Survey[i]))
+	}
+
+	var contributors []models.Contributor
+	for i := 0; i < len(update.Contributors); i++ {
+		contributors = append(contributors, dtos.BuildContributor(update.Contributors[i]))
+	}
+
+	change := func(field ProposalField, changed bool, apply func()) {
+		if !changed {
+			return
+		}
+		if !isMutable(proposal.ProposalStatus, field) {
+			v.add(string(field), "cannot be changed while the proposal is %s", proposal.ProposalStatus)
+			return
+		}
+		apply()
+	}
+
+	change(FieldName, update.Name != proposal.Name, func() { proposal.Name = update.Name })
+	change(FieldDescription, update.Description != proposal.Description, func() { proposal.Description = update.Description })
+	change(FieldSurvey, !slices.Equal(survey, proposal.Survey), func() { proposal.Survey = survey })
+	change(FieldContributors, !slices.Equal(contributors, proposal.Contributors), func() { proposal.Contributors = contributors })
+}
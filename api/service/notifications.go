@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/pennsieve/publishing-service/api/config"
+	"github.com/pennsieve/publishing-service/api/models"
+	"github.com/pennsieve/publishing-service/api/notification"
+	"github.com/pennsieve/publishing-service/api/outbox"
+	log "github.com/sirupsen/logrus"
+	"strings"
+	"time"
+)
+
+// the notification each audience is sent of a transition
+var (
+	publisherNotifications = map[string]notification.Notification{
+		config.TransitionSubmitted: notification.Submitted,
+		config.TransitionWithdrawn: notification.Withdrawn,
+	}
+	authorNotifications = map[string]notification.Notification{
+		config.TransitionSubmitted: notification.SubmittedReceipt,
+		config.TransitionWithdrawn: notification.WithdrawnReceipt,
+		config.TransitionAccepted:  notification.Accepted,
+		config.TransitionRejected:  notification.Rejected,
+	}
+	contributorNotifications = map[string]notification.Notification{
+		config.TransitionAccepted: notification.ContributorAccepted,
+	}
+)
+
+// WithNotificationMatrix sets who is notified of each lifecycle transition of a Dataset Proposal
+func WithNotificationMatrix(matrix config.NotificationMatrix) Option {
+	return func(s *publishingService) {
+		s.notifications = matrix
+	}
+}
+
+// notificationMessages builds the outbox messages that notify the audiences of the transition named by the
+// notification matrix. Contributors and workspace admins are not sent a notification of the transition when
+// they have been sent one already, as publishers or as the author.
+func (s *publishingService) notificationMessages(ctx context.Context, proposal *models.DatasetProposal, transition string, repository *models.Repository, now time.Time) ([]*models.OutboxMessage, error) {
+	log.WithFields(log.Fields{"nodeId": proposal.NodeId, "transition": transition, "audiences": s.notifications[transition]}).Info("service.notificationMessages()")
+
+	var messages []*models.OutboxMessage
+	notified := make(map[string]bool)
+	add := func(message *models.OutboxMessage) {
+		if message == nil {
+			return
+		}
+		for _, recipient := range message.Recipients {
+			notified[strings.ToLower(recipient.Address)] = true
+		}
+		messages = append(messages, message)
+	}
+
+	if n, found := publisherNotifications[transition]; found && s.notifications.Notifies(transition, config.AudiencePublishers) {
+		message, err := s.publishingTeamMessage(ctx, proposal, n, repository, now)
+		if err != nil {
+			return nil, err
+		}
+		add(message)
+	}
+
+	if n, found := authorNotifications[transition]; found && s.notifications.Notifies(transition, config.AudienceAuthor) {
+		message, err := s.proposalOwnerMessage(ctx, proposal, n, repository, now)
+		if err != nil {
+			return nil, err
+		}
+		add(message)
+	}
+
+	if n, found := contributorNotifications[transition]; found && s.notifications.Notifies(transition, config.AudienceContributors) {
+		add(s.contributorsMessage(ctx, proposal, n, repository, notified, now))
+	}
+
+	if s.notifications.Notifies(transition, config.AudienceWorkspaceAdmins) {
+		message, err := s.workspaceAdminsMessage(ctx, proposal, transition, repository, notified, now)
+		if err != nil {
+			return nil, err
+		}
+		add(message)
+	}
+
+	return messages, nil
+}
+
+// contributorsMessage builds the outbox message that notifies the proposal's Contributors of the action,
+// or returns nil if none of them have an email address that has not been notified
+func (s *publishingService) contributorsMessage(ctx context.Context, proposal *models.DatasetProposal, action notification.Notification, repository *models.Repository, notified map[string]bool, now time.Time) *models.OutboxMessage {
+	var addresses []string
+	for _, contributor := range proposal.Contributors {
+		addresses = append(addresses, contributor.EmailAddress)
+	}
+	recipients := unnotified(addresses, notified)
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	messageAttributes := s.contributorsAttributes(ctx, proposal, action, repository)
+	message := outbox.NewMessage(action, proposal.NodeId, messageAttributes, recipients, s.outbox, now)
+	return &message
+}
+
+// contributorsAttributes builds the message attributes of the notification of the action sent to the Contributors
+func (s *publishingService) contributorsAttributes(ctx context.Context, proposal *models.DatasetProposal, action notification.Notification, repository *models.Repository) notification.MessageAttributes {
+	messageAttributes := notification.MessageAttributes{
+		"AppURL":        s.appURL(),
+		"AuthorName":    proposal.OwnerName,
+		"ProposalTitle": proposal.Name,
+		"WorkspaceName": repository.DisplayName,
+	}
+	return notification.Brand(messageAttributes, action, repository, s.logoBaseURL)
+}
+
+// workspaceAdminsMessage builds the outbox message that sends the admins of the Repository's workspace a copy of
+// the transition, or returns nil if none of them have an email address that has not been notified
+func (s *publishingService) workspaceAdminsMessage(ctx context.Context, proposal *models.DatasetProposal, transition string, repository *models.Repository, notified map[string]bool, now time.Time) (*models.OutboxMessage, error) {
+	admins, err := s.pennsieve.GetWorkspaceAdmins(ctx, repository)
+	if err != nil {
+		log.WithFields(log.Fields{"failed": "GetWorkspaceAdmins()", "error": fmt.Sprintf("%+v", err)}).Error("service.workspaceAdminsMessage()")
+		return nil, err
+	}
+
+	var addresses []string
+	for _, admin := range admins {
+		addresses = append(addresses, admin.UserEmailAddress)
+	}
+	recipients := unnotified(addresses, notified)
+	if len(recipients) == 0 {
+		return nil, nil
+	}
+
+	messageAttributes := s.workspaceAdminsAttributes(ctx, proposal, transition, repository)
+	message := outbox.NewMessage(notification.AdminCopy, proposal.NodeId, messageAttributes, recipients, s.outbox, now)
+	return &message, nil
+}
+
+// workspaceAdminsAttributes builds the message attributes of the copy of the transition sent to the workspace admins
+func (s *publishingService) workspaceAdminsAttributes(ctx context.Context, proposal *models.DatasetProposal, transition string, repository *models.Repository) notification.MessageAttributes {
+	messageAttributes := notification.MessageAttributes{
+		"Action":          transition,
+		"AppURL":          s.appURL(),
+		"AuthorName":      proposal.OwnerName,
+		"AuthorEmail":     proposal.EmailAddress,
+		"ProposalTitle":   proposal.Name,
+		"WorkspaceName":   repository.DisplayName,
+		"WorkspaceNodeId": repository.OrganizationNodeId,
+	}
+	return notification.Brand(messageAttributes, notification.AdminCopy, repository, s.logoBaseURL)
+}
+
+// unnotified returns the non-empty addresses that have not been notified, once each, and marks them notified
+func unnotified(addresses []string, notified map[string]bool) []string {
+	var recipients []string
+	for _, address := range addresses {
+		key := strings.ToLower(strings.TrimSpace(address))
+		if key == "" || notified[key] {
+			continue
+		}
+		notified[key] = true
+		recipients = append(recipients, address)
+	}
+	return recipients
+}
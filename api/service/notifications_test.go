@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"github.com/pennsieve/publishing-service/api/config"
+	"github.com/pennsieve/publishing-service/api/inmemory"
+	"github.com/pennsieve/publishing-service/api/models"
+	"github.com/pennsieve/publishing-service/api/notification"
+	"slices"
+	"testing"
+)
+
+// newNotificationTestService creates a test service, holding one proposal in the given status, whose Repository has a
+// publisher and whose workspace has an admin
+func newNotificationTestService(t *testing.T, status string, matrix config.NotificationMatrix) (*publishingService, *inmemory.Notifier) {
+	t.Helper()
+
+	service, _, _ := newEventTestService(t, status)
+	service.notifications = matrix
+
+	pennsieve := service.pennsieve.(*inmemory.PennsieveStore)
+	pennsieve.AddPublisher(testRepositoryNodeId, models.Publisher{UserId: 2, UserName: "Publisher", UserEmailAddress: "publisher@example.com"})
+	pennsieve.AddWorkspaceAdmin(testRepositoryNodeId, models.WorkspaceAdmin{UserId: 3, UserName: "Admin", UserEmailAddress: "admin@example.com"})
+
+	return service, service.notifier.(*inmemory.Notifier)
+}
+
+// sentTo returns the recipients of each notification sent
+func sentTo(notifier *inmemory.Notifier) map[notification.Notification][]string {
+	sent := make(map[notification.Notification][]string)
+	for _, s := range notifier.Sent() {
+		sent[s.Notification] = append(sent[s.Notification], s.Recipients...)
+	}
+	return sent
+}
+
+func transition(t *testing.T, s *publishingService, name string) {
+	t.Helper()
+
+	var err error
+	switch name {
+	case config.TransitionSubmitted:
+		_, err = s.SubmitDatasetProposal(context.Background(), 1, testProposalNodeId)
+	case config.TransitionWithdrawn:
+		_, err = s.WithdrawDatasetProposal(context.Background(), 1, testProposalNodeId)
+	case config.TransitionAccepted:
+		_, err = s.AcceptDatasetProposal(context.Background(), testRepositoryNodeId, testProposalNodeId)
+	case config.TransitionRejected:
+		_, err = s.RejectDatasetProposal(context.Background(), testRepositoryNodeId, testProposalNodeId)
+	}
+	if err != nil {
+		t.Fatalf("%s error: %v", name, err)
+	}
+}
+
+func TestDefaultNotificationMatrix(t *testing.T) {
+	tests := []struct {
+		transition string
+		status     string
+		want       map[notification.Notification][]string
+	}{
+		{config.TransitionSubmitted, "DRAFT", map[notification.Notification][]string{
+			notification.Submitted:        {"publisher@example.com"},
+			notification.SubmittedReceipt: {"owner@example.com"},
+		}},
+		{config.TransitionWithdrawn, "SUBMITTED", map[notification.Notification][]string{
+			notification.Withdrawn:        {"publisher@example.com"},
+			notification.WithdrawnReceipt: {"owner@example.com"},
+		}},
+		{config.TransitionAccepted, "SUBMITTED", map[notification.Notification][]string{
+			notification.Accepted:            {"owner@example.com"},
+			notification.ContributorAccepted: {"ada@example.com"},
+		}},
+		{config.TransitionRejected, "SUBMITTED", map[notification.Notification][]string{
+			notification.Rejected: {"owner@example.com"},
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.transition, func(t *testing.T) {
+			service, notifier := newNotificationTestService(t, test.status, config.DefaultNotificationMatrix())
+
+			transition(t, service, test.transition)
+
+			sent := sentTo(notifier)
+			if len(sent) != len(test.want) {
+				t.Errorf("sent %v, want %v", sent, test.want)
+			}
+			for n, recipients := range test.want {
+				if !slices.Equal(sent[n], recipients) {
+					t.Errorf("%s sent to %v, want %v", n, sent[n], recipients)
+				}
+			}
+		})
+	}
+}
+
+func TestWorkspaceAdminsAreSentACopy(t *testing.T) {
+	matrix := config.DefaultNotificationMatrix()
+	matrix[config.TransitionSubmitted] = []string{config.AudiencePublishers, config.AudienceAuthor, config.AudienceWorkspaceAdmins}
+	service, notifier := newNotificationTestService(t, "DRAFT", matrix)
+
+	// admins who are already notified, as a publisher or as the author, are not sent a copy
+	pennsieve := service.pennsieve.(*inmemory.PennsieveStore)
+	pennsieve.AddWorkspaceAdmin(testRepositoryNodeId, models.WorkspaceAdmin{UserId: 2, UserEmailAddress: "Publisher@Example.com"})
+	pennsieve.AddWorkspaceAdmin(testRepositoryNodeId, models.WorkspaceAdmin{UserId: 1, UserEmailAddress: "owner@example.com"})
+
+	transition(t, service, config.TransitionSubmitted)
+
+	var copies []inmemory.SentNotification
+	for _, sent := range notifier.Sent() {
+		if sent.Notification == notification.AdminCopy {
+			copies = append(copies, sent)
+		}
+	}
+	if len(copies) != 1 || !slices.Equal(copies[0].Recipients, []string{"admin@example.com"}) {
+		t.Fatalf("admin copies = %+v, want one to admin@example.com", copies)
+	}
+	if action := copies[0].MessageAttributes["Action"]; action != config.TransitionSubmitted {
+		t.Errorf("admin copy Action = %q, want %q", action, config.TransitionSubmitted)
+	}
+}
+
+func TestContributorsAreNotifiedOnceAndNotAsAuthor(t *testing.T) {
+	service, notifier := newNotificationTestService(t, "SUBMITTED", config.DefaultNotificationMatrix())
+
+	pubStore := service.store.(*inmemory.PublishingStore)
+	proposal := getStored(t, pubStore)
+	proposal.Contributors = append(proposal.Contributors,
+		models.Contributor{FirstName: "Owner", LastName: "Name", EmailAddress: "OWNER@example.com"},
+		models.Contributor{FirstName: "Ada", LastName: "Lovelace", EmailAddress: "Ada@Example.com"},
+		models.Contributor{FirstName: "Grace", LastName: "Hopper", EmailAddress: "grace@example.com"},
+		models.Contributor{FirstName: "No", LastName: "Email"},
+	)
+	if _, err := pubStore.UpdateDatasetProposal(context.Background(), proposal); err != nil {
+		t.Fatalf("adding contributors: %v", err)
+	}
+
+	transition(t, service, config.TransitionAccepted)
+
+	want := []string{"ada@example.com", "grace@example.com"}
+	if got := sentTo(notifier)[notification.ContributorAccepted]; !slices.Equal(got, want) {
+		t.Errorf("contributors notified = %v, want %v", got, want)
+	}
+}
+
+func TestTransitionWithoutAudiencesNotifiesNoOne(t *testing.T) {
+	matrix := config.DefaultNotificationMatrix()
+	matrix[config.TransitionRejected] = nil
+	service, notifier := newNotificationTestService(t, "SUBMITTED", matrix)
+
+	transition(t, service, config.TransitionRejected)
+
+	if sent := notifier.Sent(); len(sent) != 0 {
+		t.Errorf("sent %+v, want none", sent)
+	}
+}
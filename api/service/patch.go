@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/pennsieve/publishing-service/api/dtos"
+	log "github.com/sirupsen/logrus"
+	"sort"
+)
+
+// PatchDatasetProposal merges the patch onto the user's stored Dataset Proposal, then updates it like UpdateDatasetProposal
+func (s *publishingService) PatchDatasetProposal(ctx context.Context, userId int64, existing dtos.DatasetProposalDTO, patch dtos.DatasetProposalPatchRequest) (*dtos.DatasetProposalDTO, error) {
+	log.WithFields(log.Fields{"userId": userId, "nodeId": existing.NodeId, "patch": fmt.Sprintf("%+v", patch)}).Info("service.PatchDatasetProposal()")
+
+	update, err := applyPatch(existing, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.UpdateDatasetProposal(ctx, userId, existing, update)
+}
+
+// applyPatch merges the patch onto the Dataset Proposal, returning the resulting update request
+func applyPatch(existing dtos.DatasetProposalDTO, patch dtos.DatasetProposalPatchRequest) (dtos.DatasetProposalUpdateRequest, error) {
+	v := &validator{}
+
+	update := dtos.DatasetProposalUpdateRequest{
+		NodeId:       existing.NodeId,
+		Version:      &existing.Version,
+		Name:         existing.Name,
+		Description:  existing.Description,
+		Survey:       existing.Survey,
+		Contributors: existing.Contributors,
+	}
+
+	if patch.Name.Set {
+		if patch.Name.Null {
+			v.add("name", "is required")
+		}
+		update.Name = patch.Name.Value
+	}
+	if patch.Description.Set {
+		update.Description = patch.Description.Value
+	}
+	if patch.Survey.Set {
+		update.Survey = patchSurvey(existing.Survey, patch.Survey)
+	}
+	if patch.Contributors.Set {
+		update.Contributors = patchContributors(v, existing.Contributors, patch.Contributors)
+	}
+
+	return update, v.err()
+}
+
+func patchSurvey(survey []dtos.SurveyDTO, patch dtos.SurveyPatch) []dtos.SurveyDTO {
+	if patch.Answers == nil {
+		return patch.Replace
+	}
+
+	var patched []dtos.SurveyDTO
+	for _, answer := range survey {
+		response, found := patch.Answers[answer.QuestionId]
+		switch {
+		case !found:
+			patched = append(patched, answer)
+		case response != nil:
+			patched = append(patched, dtos.SurveyDTO{QuestionId: answer.QuestionId, Response: *response})
+		}
+	}
+
+	// answers to questions that had not been answered are appended in question order
+	var questionIds []int
+	for questionId, response := range patch.Answers {
+		if response != nil && !isAnswered(survey, questionId) {
+			questionIds = append(questionIds, questionId)
+		}
+	}
+	sort.Ints(questionIds)
+	for _, questionId := range questionIds {
+		patched = append(patched, dtos.SurveyDTO{QuestionId: questionId, Response: *patch.Answers[questionId]})
+	}
+
+	return patched
+}
+
+func isAnswered(survey []dtos.SurveyDTO, questionId int) bool {
+	for _, answer := range survey {
+		if answer.QuestionId == questionId {
+			return true
+		}
+	}
+	return false
+}
+
+func patchContributors(v *validator, contributors []dtos.ContributorDTO, patch dtos.ContributorsPatch) []dtos.ContributorDTO {
+	if patch.Changes == nil {
+		return patch.Replace
+	}
+
+	patched := append([]dtos.ContributorDTO{}, contributors...)
+	removed := make(map[int]bool)
+	for index, change := range patch.Changes {
+		field := fmt.Sprintf("contributors[%d]", index)
+		switch {
+		case index > len(contributors):
+			v.add(field, "index is out of range (there are %d contributors)", len(contributors))
+		case change == nil && index == len(contributors):
+			v.add(field, "index is out of range (there are %d contributors)", len(contributors))
+		case change == nil:
+			removed[index] = true
+		case index == len(contributors):
+			patched = append(patched, mergeContributor(dtos.ContributorDTO{}, change))
+		default:
+			patched[index] = mergeContributor(patched[index], change)
+		}
+	}
+
+	var result []dtos.ContributorDTO
+	for index, contributor := range patched {
+		if !removed[index] {
+			result = append(result, contributor)
+		}
+	}
+	return result
+}
+
+func mergeContributor(contributor dtos.ContributorDTO, change *dtos.ContributorPatch) dtos.ContributorDTO {
+	if change.FirstName != nil {
+		contributor.FirstName = *change.FirstName
+	}
+	if change.LastName != nil {
+		contributor.LastName = *change.LastName
+	}
+	if change.EmailAddress != nil {
+		contributor.EmailAddress = *change.EmailAddress
+	}
+	return contributor
+}
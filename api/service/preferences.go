@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/pennsieve/publishing-service/api/dtos"
+	"github.com/pennsieve/publishing-service/api/models"
+	log "github.com/sirupsen/logrus"
+	"slices"
+	"strings"
+	"time"
+)
+
+// NotificationFrequencies are the frequencies a publisher may choose to be notified of submitted and withdrawn proposals
+var NotificationFrequencies = []string{models.NotifyImmediately, models.NotifyDaily, models.NotifyWeekly, models.NotifyOff}
+
+func buildNotificationPreferenceDTO(preference *models.NotificationPreference) *dtos.NotificationPreferenceDTO {
+	return &dtos.NotificationPreferenceDTO{
+		Frequency:    preference.Frequency,
+		LastDigestAt: preference.LastDigestAt,
+		UpdatedAt:    preference.UpdatedAt,
+	}
+}
+
+func (s *publishingService) GetNotificationPreference(ctx context.Context, userId int64) (*dtos.NotificationPreferenceDTO, error) {
+	log.WithFields(log.Fields{"userId": userId}).Info("service.GetNotificationPreference()")
+
+	preference, err := s.store.GetNotificationPreference(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildNotificationPreferenceDTO(preference), nil
+}
+
+// UpdateNotificationPreference sets how often the user is notified; the time of their last digest is kept,
+// so that switching between daily and weekly digests does not repeat or skip events
+func (s *publishingService) UpdateNotificationPreference(ctx context.Context, userId int64, request dtos.NotificationPreferenceRequest) (*dtos.NotificationPreferenceDTO, error) {
+	log.WithFields(log.Fields{"userId": userId, "request": fmt.Sprintf("%+v", request)}).Info("service.UpdateNotificationPreference()")
+
+	if !slices.Contains(NotificationFrequencies, request.Frequency) {
+		return nil, NewValidationError("frequency", fmt.Sprintf("must be one of %s", strings.Join(NotificationFrequencies, ", ")))
+	}
+
+	preference, err := s.store.GetNotificationPreference(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	preference.Frequency = request.Frequency
+	preference.UpdatedAt = time.Now().Unix()
+
+	if err := s.store.PutNotificationPreference(ctx, preference); err != nil {
+		return nil, err
+	}
+
+	return buildNotificationPreferenceDTO(preference), nil
+}
+
+// immediateRecipients are the email addresses of the publishers who are notified of each event as it happens
+func (s *publishingService) immediateRecipients(ctx context.Context, publishers []models.Publisher) []string {
+	var recipients []string
+	for _, publisher := range publishers {
+		if publisher.UserEmailAddress == "" {
+			continue
+		}
+		preference, err := s.store.GetNotificationPreference(ctx, publisher.UserId)
+		if err != nil {
+			// notifying a publisher who wanted a digest is better than dropping the notification
+			log.WithFields(log.Fields{"userId": publisher.UserId, "error": fmt.Sprintf("%+v", err)}).Warn("service.immediateRecipients()")
+		} else if preference.Frequency != models.NotifyImmediately {
+			continue
+		}
+		recipients = append(recipients, publisher.UserEmailAddress)
+	}
+	return recipients
+}
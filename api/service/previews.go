@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/pennsieve/publishing-service/api/dtos"
+	"github.com/pennsieve/publishing-service/api/models"
+	"github.com/pennsieve/publishing-service/api/notification"
+	log "github.com/sirupsen/logrus"
+	"sort"
+	"strings"
+)
+
+// ErrPreviewUnavailable is returned when the service has no Previewer to render notifications with, or the
+// notification is not rendered by the service, e.g. an unbranded one sent by the email-service
+var ErrPreviewUnavailable = errors.New("notification previews are not available")
+
+// ErrSubmissionNotFound is returned for a Dataset Proposal that was not submitted to the Repository
+var ErrSubmissionNotFound = errors.New("dataset proposal not found")
+
+// WithPreviewer sets the Previewer that renders notifications without sending them
+func WithPreviewer(previewer notification.Previewer) Option {
+	return func(s *publishingService) {
+		s.previewer = previewer
+	}
+}
+
+// previewableNotifications are the notifications sent of a Dataset Proposal's transitions, which can be previewed
+func previewableNotifications() []string {
+	var names []string
+	for _, notifications := range []map[string]notification.Notification{publisherNotifications, authorNotifications, contributorNotifications} {
+		for _, n := range notifications {
+			names = append(names, n.String())
+		}
+	}
+	names = append(names, notification.AdminCopy.String())
+	sort.Strings(names)
+	return names
+}
+
+// previewAttributes builds the message attributes of the notification of the proposal with the builder that
+// builds them when the notification is sent. The workspace admins' copy is of the proposal's latest transition.
+func (s *publishingService) previewAttributes(ctx context.Context, proposal *models.DatasetProposal, n notification.Notification, repository *models.Repository) (notification.MessageAttributes, error) {
+	for _, sent := range publisherNotifications {
+		if n == sent {
+			return s.publishingTeamAttributes(ctx, proposal, n, repository), nil
+		}
+	}
+	for _, sent := range authorNotifications {
+		if n == sent {
+			return s.proposalOwnerAttributes(ctx, proposal, n, repository)
+		}
+	}
+	for _, sent := range contributorNotifications {
+		if n == sent {
+			return s.contributorsAttributes(ctx, proposal, n, repository), nil
+		}
+	}
+	if n == notification.AdminCopy {
+		return s.workspaceAdminsAttributes(ctx, proposal, strings.ToLower(proposal.ProposalStatus), repository), nil
+	}
+	return nil, NewValidationError("notification", fmt.Sprintf("must be one of %s", strings.Join(previewableNotifications(), ", ")))
+}
+
+// PreviewNotification renders the named notification of a Dataset Proposal submitted to the Repository, with the
+// Repository's branding, without sending it, so that the Repository's admins can review their wording.
+// An unknown notification, or one that is not sent of a proposal's transitions, is rejected with a *ValidationError.
+func (s *publishingService) PreviewNotification(ctx context.Context, orgNodeId string, nodeId string, name string) (*dtos.NotificationPreviewDTO, error) {
+	log.WithFields(log.Fields{"orgNodeId": orgNodeId, "nodeId": nodeId, "notification": name}).Info("service.PreviewNotification()")
+
+	n, err := notification.ParseNotification(name)
+	if err != nil {
+		return nil, NewValidationError("notification", fmt.Sprintf("must be one of %s", strings.Join(previewableNotifications(), ", ")))
+	}
+	if s.previewer == nil {
+		return nil, ErrPreviewUnavailable
+	}
+
+	proposal, err := s.getRepositoryProposal(ctx, orgNodeId, nodeId)
+	if err != nil {
+		log.WithFields(log.Fields{"failed": "getRepositoryProposal()", "error": fmt.Sprintf("%+v", err)}).Error("service.PreviewNotification()")
+		return nil, fmt.Errorf("%w: %s", ErrSubmissionNotFound, nodeId)
+	}
+	repository, err := s.store.GetRepository(ctx, proposal.OrganizationNodeId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository: %w", err)
+	}
+
+	messageAttributes, err := s.previewAttributes(ctx, proposal, n, repository)
+	if err != nil {
+		return nil, err
+	}
+
+	rendering, err := s.previewer.Preview(ctx, n, messageAttributes)
+	if errors.Is(err, notification.ErrNotPreviewable) {
+		return nil, fmt.Errorf("%w: %w", ErrPreviewUnavailable, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to render %s: %w", n, err)
+	}
+
+	return &dtos.NotificationPreviewDTO{
+		Notification: n.String(),
+		Subject:      rendering.Subject,
+		HTML:         rendering.HTML,
+		Text:         rendering.Text,
+		ReplyTo:      append([]string{}, rendering.ReplyTo...),
+	}, nil
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/pennsieve/publishing-service/api/config"
+	"github.com/pennsieve/publishing-service/api/models"
+	"github.com/pennsieve/publishing-service/api/notification"
+	"maps"
+	"testing"
+)
+
+// fakePreviewer renders the subject of every notification as its name, recording the message attributes
+type fakePreviewer struct {
+	previewed map[notification.Notification]notification.MessageAttributes
+}
+
+func (p *fakePreviewer) Preview(ctx context.Context, n notification.Notification, messageAttributes notification.MessageAttributes) (*notification.Rendering, error) {
+	p.previewed[n] = messageAttributes
+	return &notification.Rendering{Subject: n.String(), HTML: "<p>" + n.String() + "</p>", Text: n.String()}, nil
+}
+
+func TestPreviewNotificationUsesTheAttributesSent(t *testing.T) {
+	service, notifier := newNotificationTestService(t, "SUBMITTED", config.DefaultNotificationMatrix())
+	brandRepository(t, service, &models.EmailBranding{HeaderLogo: true, ReplyTo: "curators@repository.org"})
+	previewer := &fakePreviewer{previewed: make(map[notification.Notification]notification.MessageAttributes)}
+	service.previewer = previewer
+
+	for _, n := range []notification.Notification{notification.Accepted, notification.ContributorAccepted} {
+		preview, err := service.PreviewNotification(context.Background(), testRepositoryNodeId, testProposalNodeId, n.String())
+		if err != nil {
+			t.Fatalf("PreviewNotification(%s) error: %v", n, err)
+		}
+		if preview.Notification != n.String() || preview.Subject != n.String() || preview.Text != n.String() {
+			t.Errorf("preview = %+v, want the rendering of %s", preview, n)
+		}
+	}
+	if len(notifier.Sent()) != 0 {
+		t.Fatalf("sent %+v, want previews not to be sent", notifier.Sent())
+	}
+
+	transition(t, service, config.TransitionAccepted)
+
+	for _, sent := range notifier.Sent() {
+		if !maps.Equal(previewer.previewed[sent.Notification], sent.MessageAttributes) {
+			t.Errorf("%s previewed with %v, want the attributes sent: %v", sent.Notification, previewer.previewed[sent.Notification], sent.MessageAttributes)
+		}
+	}
+}
+
+func TestPreviewAdminCopyIsOfTheLatestTransition(t *testing.T) {
+	service, _ := newNotificationTestService(t, "SUBMITTED", config.DefaultNotificationMatrix())
+	previewer := &fakePreviewer{previewed: make(map[notification.Notification]notification.MessageAttributes)}
+	service.previewer = previewer
+
+	if _, err := service.PreviewNotification(context.Background(), testRepositoryNodeId, testProposalNodeId, "ProposalAdminCopy"); err != nil {
+		t.Fatalf("PreviewNotification() error: %v", err)
+	}
+	if action := previewer.previewed[notification.AdminCopy]["Action"]; action != config.TransitionSubmitted {
+		t.Errorf("Action = %q, want %q", action, config.TransitionSubmitted)
+	}
+}
+
+func TestPreviewNotificationErrors(t *testing.T) {
+	tests := map[string]struct {
+		orgNodeId string
+		name      string
+		previewer bool
+		check     func(err error) bool
+	}{
+		"unknown notification": {testRepositoryNodeId, "ProposalApproved", true, func(err error) bool {
+			var validationErr *ValidationError
+			return errors.As(err, &validationErr)
+		}},
+		"not sent of a transition": {testRepositoryNodeId, "ProposalDigest", true, func(err error) bool {
+			var validationErr *ValidationError
+			return errors.As(err, &validationErr)
+		}},
+		"another repository": {"N:organization:other", "ProposalAccepted", true, func(err error) bool {
+			return errors.Is(err, ErrSubmissionNotFound)
+		}},
+		"no previewer": {testRepositoryNodeId, "ProposalAccepted", false, func(err error) bool {
+			return errors.Is(err, ErrPreviewUnavailable)
+		}},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			service, _ := newNotificationTestService(t, "SUBMITTED", config.DefaultNotificationMatrix())
+			if test.previewer {
+				service.previewer = &fakePreviewer{previewed: make(map[notification.Notification]notification.MessageAttributes)}
+			}
+
+			if _, err := service.PreviewNotification(context.Background(), test.orgNodeId, testProposalNodeId, test.name); !test.check(err) {
+				t.Errorf("PreviewNotification() error = %v", err)
+			}
+		})
+	}
+}
@@ -2,121 +2,252 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/google/uuid"
 	pgdbModels "github.com/pennsieve/pennsieve-go-core/pkg/models/pgdb"
-	"github.com/pennsieve/publishing-service/api/aws/ses"
-	sesTypes "github.com/pennsieve/publishing-service/api/aws/ses/types"
+	"github.com/pennsieve/publishing-service/api/aws/s3"
+	"github.com/pennsieve/publishing-service/api/config"
 	"github.com/pennsieve/publishing-service/api/dtos"
+	"github.com/pennsieve/publishing-service/api/events"
 	"github.com/pennsieve/publishing-service/api/models"
 	"github.com/pennsieve/publishing-service/api/notification"
+	"github.com/pennsieve/publishing-service/api/outbox"
 	"github.com/pennsieve/publishing-service/api/store"
+	"github.com/pennsieve/publishing-service/api/webhook"
 	log "github.com/sirupsen/logrus"
-	"os"
+	"slices"
+	"strings"
 	"time"
 )
 
 type PublishingService interface {
-	GetPublishingInfo() ([]dtos.InfoDTO, error)
-	GetPublishingRepositories() ([]dtos.RepositoryDTO, error)
-	GetProposalQuestions() ([]dtos.QuestionDTO, error)
-	GetDatasetProposal(userId int, nodeId string) (dtos.DatasetProposalDTO, error)
-	GetDatasetProposalsForUser(id int64) ([]dtos.DatasetProposalDTO, error)
-	GetDatasetProposalsForWorkspace(orgNodeId string, status string) ([]dtos.DatasetProposalDTO, error)
-	CreateDatasetProposal(userId int64, dto dtos.DatasetProposalDTO) (*dtos.DatasetProposalDTO, error)
-	UpdateDatasetProposal(userId int64, existing dtos.DatasetProposalDTO, dto dtos.DatasetProposalDTO) (*dtos.DatasetProposalDTO, error)
-	DeleteDatasetProposal(proposal dtos.DatasetProposalDTO) (bool, error)
-	SubmitDatasetProposal(userId int, nodeId string) (*dtos.DatasetProposalDTO, error)
-	WithdrawDatasetProposal(userId int, nodeId string) (*dtos.DatasetProposalDTO, error)
-	AcceptDatasetProposal(orgNodeId string, nodeId string) (*dtos.DatasetProposalDTO, error)
-	RejectDatasetProposal(orgNodeId string, nodeId string) (*dtos.DatasetProposalDTO, error)
-}
-
-func NewPublishingService(pubStore store.PublishingStore, pennsieve store.PennsievePublishingStore, notifier notification.Notifier) *publishingService {
-	return &publishingService{
+	GetPublishingInfo(ctx context.Context) ([]dtos.InfoDTO, error)
+	GetPublishingRepositories(ctx context.Context) ([]dtos.RepositoryDTO, error)
+	GetProposalQuestions(ctx context.Context) ([]dtos.QuestionDTO, error)
+	GetDatasetProposal(ctx context.Context, userId int, nodeId string) (dtos.DatasetProposalDTO, error)
+	GetDatasetProposalForViewer(ctx context.Context, viewer ProposalViewer, nodeId string) (*dtos.DatasetProposalDTO, error)
+	GetDatasetProposalsForUser(ctx context.Context, id int64) ([]dtos.DatasetProposalDTO, error)
+	GetDatasetProposalsForWorkspace(ctx context.Context, orgNodeId string, status string) ([]dtos.DatasetProposalDTO, error)
+	GetDatasetSubmissionsForWorkspace(ctx context.Context, orgNodeId string, statuses []string) (*dtos.DatasetSubmissionsDTO, error)
+	SearchDatasetSubmissionsForWorkspace(ctx context.Context, orgNodeId string, query string) (*dtos.DatasetSubmissionsDTO, error)
+	CreateDatasetProposal(ctx context.Context, userId int64, request dtos.DatasetProposalCreateRequest) (*dtos.DatasetProposalDTO, error)
+	UpdateDatasetProposal(ctx context.Context, userId int64, existing dtos.DatasetProposalDTO, request dtos.DatasetProposalUpdateRequest) (*dtos.DatasetProposalDTO, error)
+	PatchDatasetProposal(ctx context.Context, userId int64, existing dtos.DatasetProposalDTO, patch dtos.DatasetProposalPatchRequest) (*dtos.DatasetProposalDTO, error)
+	DeleteDatasetProposal(ctx context.Context, proposal dtos.DatasetProposalDTO) (bool, error)
+	SubmitDatasetProposal(ctx context.Context, userId int, nodeId string) (*dtos.DatasetProposalDTO, error)
+	WithdrawDatasetProposal(ctx context.Context, userId int, nodeId string) (*dtos.DatasetProposalDTO, error)
+	AcceptDatasetProposal(ctx context.Context, orgNodeId string, nodeId string) (*dtos.DatasetProposalDTO, error)
+	RejectDatasetProposal(ctx context.Context, orgNodeId string, nodeId string) (*dtos.DatasetProposalDTO, error)
+	GetNotificationPreference(ctx context.Context, userId int64) (*dtos.NotificationPreferenceDTO, error)
+	UpdateNotificationPreference(ctx context.Context, userId int64, request dtos.NotificationPreferenceRequest) (*dtos.NotificationPreferenceDTO, error)
+	GetWebhooks(ctx context.Context, orgNodeId string) ([]dtos.WebhookDTO, error)
+	CreateWebhook(ctx context.Context, orgNodeId string, userId int64, request dtos.WebhookRequest) (*dtos.WebhookDTO, error)
+	UpdateWebhook(ctx context.Context, orgNodeId string, id string, request dtos.WebhookRequest) (*dtos.WebhookDTO, error)
+	DeleteWebhook(ctx context.Context, orgNodeId string, id string) error
+	GetWebhookDeliveries(ctx context.Context, orgNodeId string, id string) ([]dtos.WebhookDeliveryDTO, error)
+	PreviewNotification(ctx context.Context, orgNodeId string, nodeId string, name string) (*dtos.NotificationPreviewDTO, error)
+}
+
+// ProposalViewer identifies a user reading a Dataset Proposal: its owner, or a member of the
+// publishing team of the Repository (PublisherOf is the Repository's OrganizationNodeId) it was submitted to
+type ProposalViewer struct {
+	UserId      int
+	PublisherOf string
+}
+
+// SubmissionStatuses are the Dataset Proposal statuses visible to a Repository's publishing team
+var SubmissionStatuses = []string{"SUBMITTED", "ACCEPTED", "REJECTED", "WITHDRAWN"}
+
+// ParseSubmissionStatuses parses a comma-separated list of statuses (or "ALL") and verifies that
+// each one is a known submission status. An empty value defaults to SUBMITTED.
+func ParseSubmissionStatuses(value string) ([]string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return []string{"SUBMITTED"}, nil
+	}
+	if strings.ToUpper(value) == "ALL" {
+		return slices.Clone(SubmissionStatuses), nil
+	}
+
+	var statuses []string
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(value, ",") {
+		status := strings.ToUpper(strings.TrimSpace(part))
+		if !isSubmissionStatus(status) {
+			return nil, fmt.Errorf("invalid status: %q (must be one of %s or ALL)", part, strings.Join(SubmissionStatuses, ", "))
+		}
+		if !seen[status] {
+			seen[status] = true
+			statuses = append(statuses, status)
+		}
+	}
+
+	return statuses, nil
+}
+
+func isSubmissionStatus(status string) bool {
+	for _, known := range SubmissionStatuses {
+		if status == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Option configures optional dependencies of the publishingService
+type Option func(s *publishingService)
+
+// WithPresigner sets the presigner used to create URLs for Repository and Info documents
+func WithPresigner(presigner s3.ObjectPresigner) Option {
+	return func(s *publishingService) {
+		s.presigner = presigner
+	}
+}
+
+// WithLogoBaseURL sets the public URL under which Repository logos are served, by S3 key, for branded emails.
+// Without it, emails keep the Pennsieve logo.
+func WithLogoBaseURL(baseURL string) Option {
+	return func(s *publishingService) {
+		s.logoBaseURL = baseURL
+	}
+}
+
+// WithPennsieveDomain sets the domain used to build links to the Pennsieve app in notifications
+func WithPennsieveDomain(domain string) Option {
+	return func(s *publishingService) {
+		s.pennsieveDomain = domain
+	}
+}
+
+// WithOutbox sets the delivery policy of the notification outbox
+func WithOutbox(policy config.Outbox) Option {
+	return func(s *publishingService) {
+		s.outbox = policy
+	}
+}
+
+// WithWebhooks sets the Notifier the outbox dispatcher uses to deliver webhook messages to Repositories' Webhooks,
+// and the policy by which Webhooks are registered. Without a Notifier, the dispatcher cannot deliver webhook messages.
+func WithWebhooks(notifier webhook.Notifier, policy config.Webhooks) Option {
+	return func(s *publishingService) {
+		s.webhooks = notifier
+		s.webhookPolicy = policy
+	}
+}
+
+// WithEvents sets the Publisher of Dataset Proposal lifecycle events. Without a Publisher, no events are published.
+func WithEvents(publisher events.Publisher) Option {
+	return func(s *publishingService) {
+		s.events = publisher
+	}
+}
+
+func NewPublishingService(pubStore store.PublishingStore, pennsieve store.PennsievePublishingStore, notifier notification.Notifier, options ...Option) *publishingService {
+	s := &publishingService{
 		store:     pubStore,
 		pennsieve: pennsieve,
 		notifier:  notifier,
+		outbox:    config.DefaultOutbox(),
+
+		webhookPolicy: config.DefaultWebhooks(),
+		notifications: config.DefaultNotificationMatrix(),
+	}
+	for _, option := range options {
+		option(s)
 	}
+	return s
 }
 
 type publishingService struct {
-	store     store.PublishingStore
-	pennsieve store.PennsievePublishingStore
-	notifier  notification.Notifier
+	store           store.PublishingStore
+	pennsieve       store.PennsievePublishingStore
+	notifier        notification.Notifier
+	presigner       s3.ObjectPresigner
+	logoBaseURL     string
+	pennsieveDomain string
+	outbox          config.Outbox
+	webhooks        webhook.Notifier
+	webhookPolicy   config.Webhooks
+	events          events.Publisher
+	notifications   config.NotificationMatrix
+	previewer       notification.Previewer
 }
 
 func usersName(user *pgdbModels.User) string {
 	return fmt.Sprintf("%s %s", user.FirstName, user.LastName)
 }
 
-func sendEmail(ctx context.Context, sender string, recipients []string, subject string, body string) error {
-	// send email message
-	emailAgent := ses.MakeEmailer()
-	err := emailAgent.SendMessage(ctx, sender, recipients, subject, body, sesTypes.Text)
-	if err != nil {
-		log.WithFields(log.Fields{"error": fmt.Sprintf("%+v", err)}).Error("service.sendEmail()")
-	}
-	return err
+func (s *publishingService) appURL() string {
+	return fmt.Sprintf("app.%s", s.pennsieveDomain)
 }
 
-func (s *publishingService) notifyPublishingTeam(proposal *models.DatasetProposal, action notification.Notification, repository *models.Repository) error {
-	log.WithFields(log.Fields{"proposal": fmt.Sprintf("%+v", proposal), "action": action, "repository": fmt.Sprintf("%+v", repository)}).Info("service.notifyPublishingTeam()")
-
-	ctx := context.TODO()
+// publishingTeamMessage builds the outbox message that notifies the Repository's publishers of the action,
+// or returns nil if none of them are notified immediately
+func (s *publishingService) publishingTeamMessage(ctx context.Context, proposal *models.DatasetProposal, action notification.Notification, repository *models.Repository, now time.Time) (*models.OutboxMessage, error) {
+	log.WithFields(log.Fields{"proposal": fmt.Sprintf("%+v", proposal), "action": action, "repository": fmt.Sprintf("%+v", repository)}).Info("service.publishingTeamMessage()")
 
 	// get Publishing team for the Repository
 	publishers, err := s.pennsieve.GetPublishingTeamMembers(ctx, repository)
 	if err != nil {
-		log.WithFields(log.Fields{"failed": "GetPublishingTeamMembers()", "error": fmt.Sprintf("%+v", err)}).Error("service.notifyPublishingTeam()")
-		return err
+		log.WithFields(log.Fields{"failed": "GetPublishingTeamMembers()", "error": fmt.Sprintf("%+v", err)}).Error("service.publishingTeamMessage()")
+		return nil, err
 	}
-	log.WithFields(log.Fields{"publishers": fmt.Sprintf("%+v", publishers)}).Info("service.notifyPublishingTeam()")
+	log.WithFields(log.Fields{"publishers": fmt.Sprintf("%+v", publishers)}).Info("service.publishingTeamMessage()")
 
-	// build list of Publisher's email addresses
-	var recipients []string
-	for _, publisher := range publishers {
-		// TODO: make sure email address is not null and not the empty string
-		recipients = append(recipients, publisher.UserEmailAddress)
+	// build list of the email addresses of Publishers who are notified immediately; the others get a digest
+	recipients := s.immediateRecipients(ctx, publishers)
+	if len(recipients) == 0 {
+		return nil, nil
 	}
 
+	messageAttributes := s.publishingTeamAttributes(ctx, proposal, action, repository)
+	message := outbox.NewMessage(action, proposal.NodeId, messageAttributes, recipients, s.outbox, now)
+	return &message, nil
+}
+
+// publishingTeamAttributes builds the message attributes of the notification of the action sent to the publishers
+func (s *publishingService) publishingTeamAttributes(ctx context.Context, proposal *models.DatasetProposal, action notification.Notification, repository *models.Repository) notification.MessageAttributes {
 	messageAttributes := notification.MessageAttributes{
-		"AppURL":          fmt.Sprintf("app.%s", os.Getenv("PENNSIEVE_DOMAIN")),
+		"AppURL":          s.appURL(),
 		"AuthorName":      proposal.OwnerName,
 		"AuthorEmail":     proposal.EmailAddress,
 		"ProposalTitle":   proposal.Name,
 		"WorkspaceName":   repository.DisplayName,
 		"WorkspaceNodeId": repository.OrganizationNodeId,
 	}
-
-	switch action {
-	case notification.Submitted:
-		err = s.notifier.ProposalSubmitted(messageAttributes, recipients)
-	case notification.Withdrawn:
-		err = s.notifier.ProposalWithdrawn(messageAttributes, recipients)
-	}
-
-	return err
+	return notification.Brand(messageAttributes, action, repository, s.logoBaseURL)
 }
 
-func (s *publishingService) notifyProposalOwner(proposal *models.DatasetProposal, action notification.Notification, repository *models.Repository) error {
-	log.WithFields(log.Fields{"proposal": fmt.Sprintf("%+v", proposal), "action": action, "repository": fmt.Sprintf("%+v", repository)}).Info("service.notifyProposalOwner()")
+// proposalOwnerMessage builds the outbox message that notifies the proposal's owner/author of the action
+func (s *publishingService) proposalOwnerMessage(ctx context.Context, proposal *models.DatasetProposal, action notification.Notification, repository *models.Repository, now time.Time) (*models.OutboxMessage, error) {
+	log.WithFields(log.Fields{"proposal": fmt.Sprintf("%+v", proposal), "action": action, "repository": fmt.Sprintf("%+v", repository)}).Info("service.proposalOwnerMessage()")
 
-	ctx := context.TODO()
-
-	// lookup the Welcome Workspace
-	welcomeWorkspace, err := s.pennsieve.GetWelcomeWorkspace(ctx)
+	messageAttributes, err := s.proposalOwnerAttributes(ctx, proposal, action, repository)
 	if err != nil {
-		log.WithFields(log.Fields{"error": fmt.Sprintf("%+v", err)}).Error("service.notifyProposalOwner()")
-		return err
+		return nil, err
 	}
 
 	// the recipients are just the proposal owner/author
 	var recipients []string
 	recipients = append(recipients, proposal.EmailAddress)
 
+	message := outbox.NewMessage(action, proposal.NodeId, messageAttributes, recipients, s.outbox, now)
+	return &message, nil
+}
+
+// proposalOwnerAttributes builds the message attributes of the notification of the action sent to the proposal's
+// owner/author
+func (s *publishingService) proposalOwnerAttributes(ctx context.Context, proposal *models.DatasetProposal, action notification.Notification, repository *models.Repository) (notification.MessageAttributes, error) {
+	// lookup the Welcome Workspace
+	welcomeWorkspace, err := s.pennsieve.GetWelcomeWorkspace(ctx)
+	if err != nil {
+		log.WithFields(log.Fields{"error": fmt.Sprintf("%+v", err)}).Error("service.proposalOwnerAttributes()")
+		return nil, err
+	}
+
 	messageAttributes := notification.MessageAttributes{
-		"AppURL":                 fmt.Sprintf("app.%s", os.Getenv("PENNSIEVE_DOMAIN")),
+		"AppURL":                 s.appURL(),
 		"AuthorName":             proposal.OwnerName,
 		"AuthorEmail":            proposal.EmailAddress,
 		"ProposalTitle":          proposal.Name,
@@ -124,22 +255,62 @@ func (s *publishingService) notifyProposalOwner(proposal *models.DatasetProposal
 		"WorkspaceNodeId":        repository.OrganizationNodeId,
 		"WelcomeWorkspaceNodeId": welcomeWorkspace.NodeId,
 	}
+	return notification.Brand(messageAttributes, action, repository, s.logoBaseURL), nil
+}
 
-	switch action {
-	case notification.Accepted:
-		err = s.notifier.ProposalAccepted(messageAttributes, recipients)
-	case notification.Rejected:
-		err = s.notifier.ProposalRejected(messageAttributes, recipients)
+// eventMessage builds the outbox message that publishes the lifecycle event of the given type for the proposal,
+// or returns nil if events are not published
+func (s *publishingService) eventMessage(proposal *models.DatasetProposal, eventType string, now time.Time) (*models.OutboxMessage, error) {
+	if s.events == nil {
+		return nil, nil
 	}
 
-	return err
+	payload, err := json.Marshal(events.NewEvent(eventType, proposal, now))
+	if err != nil {
+		return nil, fmt.Errorf("marshalling %s event: %w", eventType, err)
+	}
+
+	message := outbox.NewEventMessage(proposal.NodeId, payload, s.outbox, now)
+	return &message, nil
 }
 
-func (s *publishingService) GetPublishingInfo() ([]dtos.InfoDTO, error) {
+// updateWithOutbox updates, or creates, the Dataset Proposal together with the outbox messages reporting the change, so
+// that the notifications are recorded if, and only if, the change is. Email and event messages are then delivered
+// straight away; any that fail are left in the outbox for the dispatcher to retry. Webhook messages are only ever
+// delivered by the dispatcher, with the Webhooks Notifier, as a slow subscriber endpoint would otherwise hold up the
+// request. Nil messages are ignored.
+func (s *publishingService) updateWithOutbox(ctx context.Context, proposal *models.DatasetProposal, now time.Time, messages ...*models.OutboxMessage) (*models.DatasetProposal, error) {
+	var pending []models.OutboxMessage
+	for _, message := range messages {
+		if message != nil {
+			pending = append(pending, *message)
+		}
+	}
+
+	updated, err := s.store.UpdateDatasetProposalWithOutbox(ctx, proposal, pending)
+	if err != nil {
+		return nil, err
+	}
+
+	dispatcher := outbox.NewDispatcher(s.store, s.notifier, s.webhooks, s.events, s.outbox)
+	for i := range pending {
+		message := &pending[i]
+		if message.Channel == models.ChannelWebhook {
+			continue
+		}
+		if err := dispatcher.Deliver(ctx, message, now); err != nil {
+			log.WithFields(log.Fields{"notifyStatus": "pending", "id": message.Id, "channel": message.Channel, "error": fmt.Sprintf("%+v", err)}).Warn("service.updateWithOutbox()")
+		}
+	}
+
+	return updated, nil
+}
+
+func (s *publishingService) GetPublishingInfo(ctx context.Context) ([]dtos.InfoDTO, error) {
 	log.Println("GetPublishingInfo()")
 	var err error
 
-	info, err := s.store.GetInfo()
+	info, err := s.store.GetInfo(ctx)
 	if err != nil {
 		log.Fatalln("GetPublishingInfo() store.GetInfo() err: ", err)
 		return nil, err
@@ -147,23 +318,23 @@ func (s *publishingService) GetPublishingInfo() ([]dtos.InfoDTO, error) {
 
 	var infoDTOs []dtos.InfoDTO
 	for i := 0; i < len(info); i++ {
-		infoDTOs = append(infoDTOs, dtos.BuildInfoDTO(info[i]))
+		infoDTOs = append(infoDTOs, dtos.BuildInfoDTO(ctx, s.presigner, info[i]))
 	}
 
 	return infoDTOs, nil
 }
 
-func (s *publishingService) GetPublishingRepositories() ([]dtos.RepositoryDTO, error) {
+func (s *publishingService) GetPublishingRepositories(ctx context.Context) ([]dtos.RepositoryDTO, error) {
 	log.Println("GetPublishingRepositories()")
 	var err error
 
-	repositories, err := s.store.GetRepositories()
+	repositories, err := s.store.GetRepositories(ctx)
 	if err != nil {
 		log.Fatalln("GetPublishingRepositories() store.GetRepositories() err: ", err)
 		return nil, err
 	}
 
-	questions, err := s.store.GetQuestions()
+	questions, err := s.store.GetQuestions(ctx)
 	if err != nil {
 		log.Fatalln("GetPublishingRepositories() store.GetQuestions() err: ", err)
 		return nil, err
@@ -178,16 +349,16 @@ func (s *publishingService) GetPublishingRepositories() ([]dtos.RepositoryDTO, e
 	// TODO: create RepositoryDTO from repositories and questions
 	var repositoryDTOs []dtos.RepositoryDTO
 	for i := 0; i < len(repositories); i++ {
-		repositoryDTOs = append(repositoryDTOs, dtos.BuildRepositoryDTO(repositories[i], questionMap))
+		repositoryDTOs = append(repositoryDTOs, dtos.BuildRepositoryDTO(ctx, s.presigner, repositories[i], questionMap))
 	}
 	return repositoryDTOs, nil
 }
 
-func (s *publishingService) GetProposalQuestions() ([]dtos.QuestionDTO, error) {
+func (s *publishingService) GetProposalQuestions(ctx context.Context) ([]dtos.QuestionDTO, error) {
 	log.Println("GetProposalQuestions()")
 	var err error
 
-	questions, err := s.store.GetQuestions()
+	questions, err := s.store.GetQuestions(ctx)
 	if err != nil {
 		log.Fatalln("GetProposalQuestions() store.GetQuestions() err: ", err)
 		return nil, err
@@ -212,10 +383,10 @@ func proposalDTOsList(proposals []models.DatasetProposal) []dtos.DatasetProposal
 	return proposalDTOs
 }
 
-func (s *publishingService) GetDatasetProposal(userId int, nodeId string) (dtos.DatasetProposalDTO, error) {
+func (s *publishingService) GetDatasetProposal(ctx context.Context, userId int, nodeId string) (dtos.DatasetProposalDTO, error) {
 	log.WithFields(log.Fields{"userId": userId, "nodeId": nodeId}).Info("service.GetDatasetProposal()")
 
-	proposal, err := s.store.GetDatasetProposal(userId, nodeId)
+	proposal, err := s.store.GetDatasetProposal(ctx, userId, nodeId)
 	if err != nil {
 		// TODO: fix this, we should not return anything for the value
 		return dtos.DatasetProposalDTO{}, err
@@ -226,24 +397,62 @@ func (s *publishingService) GetDatasetProposal(userId int, nodeId string) (dtos.
 	return proposalDTO, nil
 }
 
-func (s *publishingService) GetDatasetProposalsForUser(userId int64) ([]dtos.DatasetProposalDTO, error) {
+// GetDatasetProposalForViewer returns the Dataset Proposal, with the Repository questions resolved to
+// their text, when the viewer owns it or publishes for the Repository it was submitted to
+func (s *publishingService) GetDatasetProposalForViewer(ctx context.Context, viewer ProposalViewer, nodeId string) (*dtos.DatasetProposalDTO, error) {
+	log.WithFields(log.Fields{"userId": viewer.UserId, "publisherOf": viewer.PublisherOf, "nodeId": nodeId}).Info("service.GetDatasetProposalForViewer()")
+
+	proposal, err := s.store.GetDatasetProposal(ctx, viewer.UserId, nodeId)
+	if err != nil && viewer.PublisherOf != "" {
+		proposal, err = s.getRepositoryProposal(ctx, viewer.PublisherOf, nodeId)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("dataset proposal %s not found for user %d: %w", nodeId, viewer.UserId, err)
+	}
+
+	questions, err := s.store.GetQuestions(ctx)
+	if err != nil {
+		log.WithFields(log.Fields{"failure": "store.GetQuestions", "err": fmt.Sprintf("%+v", err)}).Error("service.GetDatasetProposalForViewer()")
+		return nil, err
+	}
+	questionText := make(map[int]string)
+	for _, question := range questions {
+		questionText[question.Id] = question.Question
+	}
+
+	proposalDTO := dtos.BuildDatasetProposalDTO(proposal)
+	for i := range proposalDTO.Survey {
+		proposalDTO.Survey[i].Question = questionText[proposalDTO.Survey[i].QuestionId]
+	}
+
+	return &proposalDTO, nil
+}
+
+func (s *publishingService) GetDatasetProposalsForUser(ctx context.Context, userId int64) ([]dtos.DatasetProposalDTO, error) {
 	log.WithFields(log.Fields{"userId": userId}).Info("service.GetDatasetProposalsForUser()")
 
-	proposals, err := s.store.GetDatasetProposalsForUser(userId)
+	proposals, err := s.store.GetDatasetProposalsForUser(ctx, userId)
 	if err != nil {
 		log.Error("store.GetDatasetProposalsForUser() failed: ", err)
 		return nil, err
 	}
 
+	// archived (expired) drafts are kept only until their TTL passes, and are not listed
+	proposals = slices.DeleteFunc(proposals, func(proposal models.DatasetProposal) bool {
+		return proposal.ProposalStatus == "ARCHIVED"
+	})
+
 	return proposalDTOsList(proposals), nil
 }
 
-func (s *publishingService) GetDatasetProposalsForWorkspace(orgNodeId string, status string) ([]dtos.DatasetProposalDTO, error) {
+func (s *publishingService) GetDatasetProposalsForWorkspace(ctx context.Context, orgNodeId string, status string) ([]dtos.DatasetProposalDTO, error) {
 	log.WithFields(log.Fields{"orgNodeId": orgNodeId, "status": status}).Info("service.GetDatasetProposalsForWorkspace()")
 
-	// TODO: verify that status is one of: SUBMITTED, ACCEPTED, REJECTED
+	if !isSubmissionStatus(status) {
+		return nil, fmt.Errorf("invalid status: %s", status)
+	}
 
-	proposals, err := s.store.GetDatasetProposalsForWorkspace(orgNodeId, status)
+	proposals, err := s.store.GetDatasetProposalsForWorkspace(ctx, orgNodeId, status)
 	if err != nil {
 		return nil, err
 	}
@@ -251,13 +460,77 @@ func (s *publishingService) GetDatasetProposalsForWorkspace(orgNodeId string, st
 	return proposalDTOsList(proposals), nil
 }
 
+func (s *publishingService) GetDatasetSubmissionsForWorkspace(ctx context.Context, orgNodeId string, statuses []string) (*dtos.DatasetSubmissionsDTO, error) {
+	log.WithFields(log.Fields{"orgNodeId": orgNodeId, "statuses": statuses}).Info("service.GetDatasetSubmissionsForWorkspace()")
+
+	submissions := &dtos.DatasetSubmissionsDTO{
+		Counts:    make(map[string]int),
+		Proposals: []dtos.DatasetProposalDTO{},
+	}
+
+	for _, status := range statuses {
+		proposals, err := s.GetDatasetProposalsForWorkspace(ctx, orgNodeId, status)
+		if err != nil {
+			log.WithFields(log.Fields{"status": status, "error": fmt.Sprintf("%+v", err)}).Error("service.GetDatasetSubmissionsForWorkspace()")
+			return nil, err
+		}
+		submissions.Counts[status] = len(proposals)
+		submissions.Proposals = append(submissions.Proposals, proposals...)
+	}
+	submissions.TotalCount = len(submissions.Proposals)
+
+	return submissions, nil
+}
+
+func (s *publishingService) SearchDatasetSubmissionsForWorkspace(ctx context.Context, orgNodeId string, query string) (*dtos.DatasetSubmissionsDTO, error) {
+	log.WithFields(log.Fields{"orgNodeId": orgNodeId, "query": query}).Info("service.SearchDatasetSubmissionsForWorkspace()")
+
+	proposals, err := s.store.SearchDatasetProposals(ctx, orgNodeId, query)
+	if err != nil {
+		return nil, err
+	}
+
+	submissions := &dtos.DatasetSubmissionsDTO{
+		Counts:    make(map[string]int),
+		Proposals: []dtos.DatasetProposalDTO{},
+	}
+
+	// publishers only see Dataset Proposals that have been submitted to the Repository
+	for i := range proposals {
+		if !isSubmissionStatus(proposals[i].ProposalStatus) {
+			continue
+		}
+		submissions.Counts[proposals[i].ProposalStatus]++
+		submissions.Proposals = append(submissions.Proposals, dtos.BuildDatasetProposalDTO(&proposals[i]))
+	}
+	submissions.TotalCount = len(submissions.Proposals)
+
+	return submissions, nil
+}
+
 // TODO: validate RepositoryId, ensure it is in Repositories table
 // TODO: move generating ProposalNodeId string elsewhere (pennsieve-core?)
 // TODO: refactor Create..() and Update..() to use common code
-func (s *publishingService) CreateDatasetProposal(userId int64, dto dtos.DatasetProposalDTO) (*dtos.DatasetProposalDTO, error) {
+// CreateDatasetProposal creates a draft Dataset Proposal for the user. A request with invalid fields is rejected with a *ValidationError.
+func (s *publishingService) CreateDatasetProposal(ctx context.Context, userId int64, dto dtos.DatasetProposalCreateRequest) (*dtos.DatasetProposalDTO, error) {
 	log.Println("service.CreateDatasetProposal()")
 
-	user, err := s.pennsieve.GetProposalUser(context.TODO(), userId)
+	v := &validator{}
+	v.validateName(dto.Name)
+	v.validateDescription(dto.Description)
+	if dto.OrganizationNodeId == "" {
+		v.add("organizationNodeId", "is required")
+	} else if repository, err := s.store.GetRepository(ctx, dto.OrganizationNodeId); err != nil {
+		v.add("organizationNodeId", "unknown repository %s", dto.OrganizationNodeId)
+	} else {
+		v.validateSurvey(dto.Survey, repository)
+	}
+	v.validateContributors(dto.Contributors)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	user, err := s.pennsieve.GetProposalUser(ctx, userId)
 	if err != nil {
 		log.WithFields(log.Fields{"failure": "pennsieve.GetProposalUser()", "error": fmt.Sprintf("%+v", err)}).Error("service.CreateDatasetProposal()")
 		return nil, err
@@ -273,7 +546,8 @@ func (s *publishingService) CreateDatasetProposal(userId int64, dto dtos.Dataset
 		contributors = append(contributors, dtos.BuildContributor(dto.Contributors[i]))
 	}
 
-	currentTime := time.Now().Unix()
+	now := time.Now()
+	currentTime := now.Unix()
 
 	proposal := &models.DatasetProposal{
 		UserId:             int(user.Id),
@@ -291,9 +565,14 @@ func (s *publishingService) CreateDatasetProposal(userId int64, dto dtos.Dataset
 	}
 	log.WithFields(log.Fields{"proposal": fmt.Sprintf("%+v", proposal)}).Debug("service.CreateDatasetProposal()")
 
-	_, err = s.store.CreateDatasetProposal(proposal)
+	created, err := s.eventMessage(proposal, events.ProposalCreated, now)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.updateWithOutbox(ctx, proposal, now, created)
 	if err != nil {
-		log.Fatalln("service.CreateDatasetProposal() - store.CreateDatasetProposal() failed: ", err)
+		log.WithFields(log.Fields{"failure": "store.UpdateDatasetProposalWithOutbox()", "error": fmt.Sprintf("%+v", err)}).Error("service.CreateDatasetProposal()")
 		return nil, err
 	}
 
@@ -301,46 +580,45 @@ func (s *publishingService) CreateDatasetProposal(userId int64, dto dtos.Dataset
 	return &dtoResult, nil
 }
 
-func (s *publishingService) UpdateDatasetProposal(userId int64, existing dtos.DatasetProposalDTO, update dtos.DatasetProposalDTO) (*dtos.DatasetProposalDTO, error) {
+// UpdateDatasetProposal changes the client-writable fields of the user's Dataset Proposal that are mutable in its
+// status (see MutableFields), preserving every other field. A request with invalid fields, or that would change a field
+// that is not mutable, is rejected with a *ValidationError.
+func (s *publishingService) UpdateDatasetProposal(ctx context.Context, userId int64, existing dtos.DatasetProposalDTO, update dtos.DatasetProposalUpdateRequest) (*dtos.DatasetProposalDTO, error) {
 	log.WithFields(log.Fields{"userId": userId, "existing": fmt.Sprintf("%+v", existing), "update": fmt.Sprintf("%+v", update)}).Info("service.UpdateDatasetProposal()")
 
-	user, err := s.pennsieve.GetProposalUser(context.TODO(), userId)
+	repository, err := s.store.GetRepository(ctx, existing.OrganizationNodeId)
 	if err != nil {
-		log.WithFields(log.Fields{"failure": "pennsieve.GetProposalUser()", "error": fmt.Sprintf("%+v", err)}).Error("service.UpdateDatasetProposal()")
+		log.WithFields(log.Fields{"failure": "store.GetRepository()", "error": fmt.Sprintf("%+v", err)}).Error("service.UpdateDatasetProposal()")
 		return nil, err
 	}
 
-	var survey []models.Survey
-	for i := 0; i < len(update.Survey); i++ {
-		survey = append(survey, dtos.BuildSurvey(update.Survey[i]))
-	}
+	v := &validator{}
+	v.validateName(update.Name)
+	v.validateDescription(update.Description)
+	v.validateSurvey(update.Survey, repository)
+	v.validateContributors(update.Contributors)
 
-	var contributors []models.Contributor
-	for i := 0; i < len(update.Contributors); i++ {
-		contributors = append(contributors, dtos.BuildContributor(update.Contributors[i]))
+	// apply the update to the stored Dataset Proposal, so fields the DTO does not carry are preserved
+	updated, err := s.store.GetDatasetProposal(ctx, existing.UserId, existing.NodeId)
+	if err != nil {
+		log.WithFields(log.Fields{"failure": "store.GetDatasetProposal()", "error": fmt.Sprintf("%+v", err)}).Error("service.UpdateDatasetProposal()")
+		return nil, err
 	}
-
-	currentTime := time.Now().Unix()
-
-	updated := &models.DatasetProposal{
-		UserId:             int(user.Id),
-		NodeId:             existing.NodeId,
-		OwnerName:          usersName(user),
-		EmailAddress:       user.Email,
-		Name:               update.Name,
-		Description:        update.Description,
-		OrganizationNodeId: existing.OrganizationNodeId,
-		ProposalStatus:     existing.ProposalStatus,
-		Survey:             survey,
-		Contributors:       contributors,
-		CreatedAt:          existing.CreatedAt,
-		UpdatedAt:          currentTime,
+	applyUpdate(v, updated, update)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	// write only if the Dataset Proposal is still the version the update was made from
+	updated.Version = existing.Version
+	if update.Version != nil {
+		updated.Version = *update.Version
 	}
+	updated.UpdatedAt = time.Now().Unix()
 	log.WithFields(log.Fields{"updated": fmt.Sprintf("%+v", updated)}).Debug("service.UpdateDatasetProposal()")
 
-	_, err = s.store.UpdateDatasetProposal(updated)
+	_, err = s.store.UpdateDatasetProposal(ctx, updated)
 	if err != nil {
-		log.Fatalln("store.UpdateDatasetProposal() failed: ", err)
+		log.Error("store.UpdateDatasetProposal() failed: ", err)
 		return nil, err
 	}
 
@@ -348,25 +626,25 @@ func (s *publishingService) UpdateDatasetProposal(userId int64, existing dtos.Da
 	return &dtoResult, nil
 }
 
-func (s *publishingService) DeleteDatasetProposal(proposalDTO dtos.DatasetProposalDTO) (bool, error) {
+func (s *publishingService) DeleteDatasetProposal(ctx context.Context, proposalDTO dtos.DatasetProposalDTO) (bool, error) {
 	log.WithFields(log.Fields{"proposalDTO": fmt.Sprintf("%+v", proposalDTO)}).Info("service.DeleteDatasetProposal()")
 
 	proposal := dtos.BuildDatasetProposal(proposalDTO)
 
-	err := s.store.DeleteDatasetProposal(proposal)
+	err := s.store.DeleteDatasetProposal(ctx, proposal)
 	if err != nil {
-		log.Fatalln("store.DeleteDatasetProposal() failed: ", err)
+		log.Error("store.DeleteDatasetProposal() failed: ", err)
 		return false, err
 	}
 
 	return true, nil
 }
 
-func (s *publishingService) SubmitDatasetProposal(userId int, nodeId string) (*dtos.DatasetProposalDTO, error) {
+func (s *publishingService) SubmitDatasetProposal(ctx context.Context, userId int, nodeId string) (*dtos.DatasetProposalDTO, error) {
 	log.WithFields(log.Fields{"userId": userId, "nodeId": nodeId}).Info("service.SubmitDatasetProposal()")
 
 	// get Dataset Proposal by User Id and Node Id
-	proposal, err := s.store.GetDatasetProposal(userId, nodeId)
+	proposal, err := s.store.GetDatasetProposal(ctx, userId, nodeId)
 	if err != nil {
 		return nil, err
 	}
@@ -378,7 +656,10 @@ func (s *publishingService) SubmitDatasetProposal(userId int, nodeId string) (*d
 	}
 
 	// get the Repository using the Organization Node Id on the Dataset Proposal
-	repository, err := s.store.GetRepository(proposal.OrganizationNodeId)
+	repository, err := s.store.GetRepository(ctx, proposal.OrganizationNodeId)
+	if err != nil {
+		return nil, err
+	}
 
 	// verify that Organization NodeId is the same on the Repository and the Dataset Proposal (extra check)
 	if proposal.OrganizationNodeId != repository.OrganizationNodeId {
@@ -404,33 +685,49 @@ func (s *publishingService) SubmitDatasetProposal(userId int, nodeId string) (*d
 	}
 
 	// update Dataset Proposal
-	currentTime := time.Now().Unix()
+	now := time.Now()
+	currentTime := now.Unix()
 	submitted := proposal
 	submitted.ProposalStatus = "SUBMITTED"
 	submitted.UpdatedAt = currentTime
 	submitted.SubmittedAt = currentTime
 
-	updated, err := s.store.UpdateDatasetProposal(submitted)
+	// send emails to Repository Publishers Team, the author, and whoever else the notification matrix names
+	messages, err := s.notificationMessages(ctx, submitted, config.TransitionSubmitted, repository, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build notifications: %w", err)
+	}
+
+	// send event to the Repository's Webhooks
+	webhookIds, err := s.subscribedWebhooks(ctx, repository, notification.Submitted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+	event, err := s.webhookMessage(submitted, notification.Submitted, repository, webhookIds, now)
 	if err != nil {
 		return nil, err
 	}
 
-	// send email to Repository Publishers Team
-	log.WithFields(log.Fields{"notify": "publishers"}).Info("service.SubmitDatasetProposal()")
-	err = s.notifyPublishingTeam(submitted, notification.Submitted, repository)
+	// publish the lifecycle event
+	published, err := s.eventMessage(submitted, events.ProposalSubmitted, now)
 	if err != nil {
-		log.WithFields(log.Fields{"notifyStatus": "error", "error": fmt.Sprintf("%+v", err)}).Error("service.SubmitDatasetProposal()")
+		return nil, err
+	}
+
+	updated, err := s.updateWithOutbox(ctx, submitted, now, append(messages, event, published)...)
+	if err != nil {
+		return nil, err
 	}
 
 	dtoResult := dtos.BuildDatasetProposalDTO(updated)
 	return &dtoResult, nil
 }
 
-func (s *publishingService) WithdrawDatasetProposal(userId int, nodeId string) (*dtos.DatasetProposalDTO, error) {
+func (s *publishingService) WithdrawDatasetProposal(ctx context.Context, userId int, nodeId string) (*dtos.DatasetProposalDTO, error) {
 	log.WithFields(log.Fields{"userId": userId, "nodeId": nodeId}).Info("service.WithdrawDatasetProposal()")
 
 	// get Dataset Proposal by User Id and Node Id
-	proposal, err := s.store.GetDatasetProposal(userId, nodeId)
+	proposal, err := s.store.GetDatasetProposal(ctx, userId, nodeId)
 	if err != nil {
 		return nil, err
 	}
@@ -442,36 +739,68 @@ func (s *publishingService) WithdrawDatasetProposal(userId int, nodeId string) (
 	}
 
 	// get the Repository using the Organization Node Id on the Dataset Proposal
-	repository, err := s.store.GetRepository(proposal.OrganizationNodeId)
+	repository, err := s.store.GetRepository(ctx, proposal.OrganizationNodeId)
+	if err != nil {
+		return nil, err
+	}
 
 	// update Dataset Proposal
-	currentTime := time.Now().Unix()
+	now := time.Now()
+	currentTime := now.Unix()
 	withdrawn := proposal
 	withdrawn.ProposalStatus = "WITHDRAWN"
 	withdrawn.UpdatedAt = currentTime
 	withdrawn.WithdrawnAt = currentTime
 
-	updated, err := s.store.UpdateDatasetProposal(withdrawn)
+	// send emails to Repository Publishers Team, the author, and whoever else the notification matrix names
+	messages, err := s.notificationMessages(ctx, withdrawn, config.TransitionWithdrawn, repository, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build notifications: %w", err)
+	}
+
+	// send event to the Repository's Webhooks
+	webhookIds, err := s.subscribedWebhooks(ctx, repository, notification.Withdrawn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+	event, err := s.webhookMessage(withdrawn, notification.Withdrawn, repository, webhookIds, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// publish the lifecycle event
+	published, err := s.eventMessage(withdrawn, events.ProposalWithdrawn, now)
 	if err != nil {
 		return nil, err
 	}
 
-	// send email to Repository Publishers Team
-	log.WithFields(log.Fields{"notify": "publishers"}).Info("service.WithdrawDatasetProposal()")
-	err = s.notifyPublishingTeam(withdrawn, notification.Withdrawn, repository)
+	updated, err := s.updateWithOutbox(ctx, withdrawn, now, append(messages, event, published)...)
 	if err != nil {
-		log.WithFields(log.Fields{"notifyStatus": "error", "error": fmt.Sprintf("%+v", err)}).Error("service.WithdrawDatasetProposal()")
+		return nil, err
 	}
 
 	dtoResult := dtos.BuildDatasetProposalDTO(updated)
 	return &dtoResult, nil
 }
 
-func (s *publishingService) AcceptDatasetProposal(orgNodeId string, nodeId string) (*dtos.DatasetProposalDTO, error) {
+// getRepositoryProposal gets a Dataset Proposal by its Node Id, provided it was submitted to the Repository.
+// Publishers see the proposals submitted to their Repository, but not drafts.
+func (s *publishingService) getRepositoryProposal(ctx context.Context, orgNodeId string, nodeId string) (*models.DatasetProposal, error) {
+	proposal, err := s.store.GetDatasetProposalByNodeId(ctx, nodeId)
+	if err != nil {
+		return nil, err
+	}
+	if proposal.OrganizationNodeId != orgNodeId || !isSubmissionStatus(proposal.ProposalStatus) {
+		return nil, fmt.Errorf("dataset proposal %s was not submitted to repository %s", nodeId, orgNodeId)
+	}
+	return proposal, nil
+}
+
+func (s *publishingService) AcceptDatasetProposal(ctx context.Context, orgNodeId string, nodeId string) (*dtos.DatasetProposalDTO, error) {
 	log.WithFields(log.Fields{"orgNodeId": orgNodeId, "nodeId": nodeId}).Info("service.AcceptDatasetProposal()")
 
-	// get Dataset Proposal by Repository Id and Node Id
-	proposal, err := s.store.GetDatasetProposalForRepository(orgNodeId, "SUBMITTED", nodeId)
+	// get Dataset Proposal by Node Id, verifying that it was submitted to the Repository
+	proposal, err := s.getRepositoryProposal(ctx, orgNodeId, nodeId)
 	if err != nil {
 		return nil, err
 	}
@@ -483,10 +812,26 @@ func (s *publishingService) AcceptDatasetProposal(orgNodeId string, nodeId strin
 	}
 
 	// get the Repository using the Organization Node Id on the Dataset Proposal
-	repository, err := s.store.GetRepository(proposal.OrganizationNodeId)
+	repository, err := s.store.GetRepository(ctx, proposal.OrganizationNodeId)
+	if err != nil {
+		return nil, err
+	}
+
+	// build the emails to Dataset Proposal author/originator and whoever else the notification matrix names, and
+	// find the Webhooks to send the event to, before the dataset is created, so that a proposal is not accepted
+	// without its author being told
+	now := time.Now()
+	messages, err := s.notificationMessages(ctx, proposal, config.TransitionAccepted, repository, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build notifications: %w", err)
+	}
+	webhookIds, err := s.subscribedWebhooks(ctx, repository, notification.Accepted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
 
 	// create dataset
-	result, err := s.pennsieve.CreateDatasetForAcceptedProposal(context.TODO(), proposal)
+	result, err := s.pennsieve.CreateDatasetForAcceptedProposal(ctx, proposal)
 	if err != nil {
 		log.WithFields(log.Fields{"failure": "CreateDatasetForAcceptedProposal", "err": fmt.Sprintf("%+v", err)}).Error("service.AcceptDatasetProposal()")
 		return nil, fmt.Errorf(fmt.Sprintf("failed to CreateDatasetForAcceptedProposal (error: %+v)", err))
@@ -496,7 +841,7 @@ func (s *publishingService) AcceptDatasetProposal(orgNodeId string, nodeId strin
 	// update Dataset Proposal
 	// - set Status = “ACCEPTED”
 	// - set AcceptedAt = current time
-	currentTime := time.Now().Unix()
+	currentTime := now.Unix()
 	accepted := proposal
 	accepted.ProposalStatus = "ACCEPTED"
 	accepted.DatasetNodeId = result.Dataset.NodeId.String
@@ -504,27 +849,32 @@ func (s *publishingService) AcceptDatasetProposal(orgNodeId string, nodeId strin
 	accepted.UpdatedAt = currentTime
 	accepted.AcceptedAt = currentTime
 
-	updated, err := s.store.UpdateDatasetProposal(accepted)
+	// send event, with the dataset that was created, to the Repository's Webhooks
+	event, err := s.webhookMessage(accepted, notification.Accepted, repository, webhookIds, now)
 	if err != nil {
 		return nil, err
 	}
 
-	// send email to Dataset Proposal author/originator
-	log.WithFields(log.Fields{"notify": "owner"}).Info("service.AcceptDatasetProposal()")
-	err = s.notifyProposalOwner(accepted, notification.Accepted, repository)
+	// publish the lifecycle event, with the dataset that was created
+	published, err := s.eventMessage(accepted, events.ProposalAccepted, now)
 	if err != nil {
-		log.WithFields(log.Fields{"notifyStatus": "error", "error": fmt.Sprintf("%+v", err)}).Error("service.AcceptDatasetProposal()")
+		return nil, err
+	}
+
+	updated, err := s.updateWithOutbox(ctx, accepted, now, append(messages, event, published)...)
+	if err != nil {
+		return nil, err
 	}
 
 	dtoResult := dtos.BuildDatasetProposalDTO(updated)
 	return &dtoResult, nil
 }
 
-func (s *publishingService) RejectDatasetProposal(orgNodeId string, nodeId string) (*dtos.DatasetProposalDTO, error) {
+func (s *publishingService) RejectDatasetProposal(ctx context.Context, orgNodeId string, nodeId string) (*dtos.DatasetProposalDTO, error) {
 	log.WithFields(log.Fields{"orgNodeId": orgNodeId, "nodeId": nodeId}).Info("service.RejectDatasetProposal()")
 
-	// get Dataset Proposal by Repository Id and Node Id
-	proposal, err := s.store.GetDatasetProposalForRepository(orgNodeId, "SUBMITTED", nodeId)
+	// get Dataset Proposal by Node Id, verifying that it was submitted to the Repository
+	proposal, err := s.getRepositoryProposal(ctx, orgNodeId, nodeId)
 	if err != nil {
 		return nil, err
 	}
@@ -536,27 +886,46 @@ func (s *publishingService) RejectDatasetProposal(orgNodeId string, nodeId strin
 	}
 
 	// get the Repository using the Organization Node Id on the Dataset Proposal
-	repository, err := s.store.GetRepository(proposal.OrganizationNodeId)
+	repository, err := s.store.GetRepository(ctx, proposal.OrganizationNodeId)
+	if err != nil {
+		return nil, err
+	}
 
 	// update Dataset Proposal
 	// - set Status = “REJECTED”
 	// - set AcceptedAt = current time
-	currentTime := time.Now().Unix()
+	now := time.Now()
+	currentTime := now.Unix()
 	rejected := proposal
 	rejected.ProposalStatus = "REJECTED"
 	rejected.UpdatedAt = currentTime
 	rejected.RejectedAt = currentTime
 
-	updated, err := s.store.UpdateDatasetProposal(rejected)
+	// send emails to Dataset Proposal author/originator and whoever else the notification matrix names
+	messages, err := s.notificationMessages(ctx, rejected, config.TransitionRejected, repository, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build notifications: %w", err)
+	}
+
+	// send event to the Repository's Webhooks
+	webhookIds, err := s.subscribedWebhooks(ctx, repository, notification.Rejected)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+	event, err := s.webhookMessage(rejected, notification.Rejected, repository, webhookIds, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// publish the lifecycle event
+	published, err := s.eventMessage(rejected, events.ProposalRejected, now)
 	if err != nil {
 		return nil, err
 	}
 
-	// send email to Dataset Proposal author/originator
-	log.WithFields(log.Fields{"notify": "owner"}).Info("service.RejectDatasetProposal()")
-	err = s.notifyProposalOwner(rejected, notification.Rejected, repository)
+	updated, err := s.updateWithOutbox(ctx, rejected, now, append(messages, event, published)...)
 	if err != nil {
-		log.WithFields(log.Fields{"notifyStatus": "error", "error": fmt.Sprintf("%+v", err)}).Error("service.RejectDatasetProposal()")
+		return nil, err
 	}
 
 	dtoResult := dtos.BuildDatasetProposalDTO(updated)
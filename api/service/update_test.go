@@ -0,0 +1,300 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/pennsieve/publishing-service/api/dtos"
+	"github.com/pennsieve/publishing-service/api/inmemory"
+	"github.com/pennsieve/publishing-service/api/models"
+	"github.com/pennsieve/publishing-service/api/store"
+	"reflect"
+	"testing"
+)
+
+const (
+	testRepositoryNodeId = "N:organization:repository"
+	testProposalNodeId   = "N:proposal:1"
+	testDatasetNodeId    = "N:dataset:1"
+)
+
+// newTestService creates a PublishingService backed by in-memory stores, holding one proposal in the given status
+func newTestService(t *testing.T, status string) (*publishingService, *inmemory.PublishingStore, *models.DatasetProposal) {
+	t.Helper()
+
+	pubStore := inmemory.NewPublishingStore()
+	pubStore.AddQuestion(models.Question{Id: 1, Question: "Question 1"})
+	pubStore.AddQuestion(models.Question{Id: 2, Question: "Question 2"})
+	pubStore.AddRepository(models.Repository{
+		OrganizationNodeId: testRepositoryNodeId,
+		Name:               "repository",
+		Questions:          []int{1, 2},
+	})
+
+	proposal := &models.DatasetProposal{
+		UserId:             1,
+		NodeId:             testProposalNodeId,
+		OwnerName:          "Owner Name",
+		EmailAddress:       "owner@example.com",
+		Name:               "Original name",
+		Description:        "Original description",
+		OrganizationNodeId: testRepositoryNodeId,
+		ProposalStatus:     status,
+		Survey:             []models.Survey{{QuestionId: 1, Response: "answer 1"}},
+		Contributors:       []models.Contributor{{FirstName: "Ada", LastName: "Lovelace", EmailAddress: "ada@example.com"}},
+		CreatedAt:          1000,
+		UpdatedAt:          2000,
+	}
+	switch status {
+	case "DRAFT":
+		proposal.ReminderSentAt = 1800
+	case "WITHDRAWN":
+		proposal.SubmittedAt = 1500
+		proposal.WithdrawnAt = 1600
+		proposal.ReviewRemindedAt = 1550
+		proposal.ReviewEscalatedAt = 1580
+	case "SUBMITTED":
+		proposal.SubmittedAt = 1500
+	case "ACCEPTED":
+		proposal.SubmittedAt = 1500
+		proposal.AcceptedAt = 1700
+		proposal.DatasetNodeId = testDatasetNodeId
+	case "REJECTED":
+		proposal.SubmittedAt = 1500
+		proposal.RejectedAt = 1700
+	}
+	if _, err := pubStore.CreateDatasetProposal(context.Background(), proposal); err != nil {
+		t.Fatalf("seeding proposal: %v", err)
+	}
+
+	service := NewPublishingService(pubStore, inmemory.NewPennsieveStore(1), inmemory.NewNotifier())
+	return service, pubStore, proposal
+}
+
+func getStored(t *testing.T, pubStore *inmemory.PublishingStore) *models.DatasetProposal {
+	t.Helper()
+	stored, err := pubStore.GetDatasetProposal(context.Background(), 1, testProposalNodeId)
+	if err != nil {
+		t.Fatalf("getting stored proposal: %v", err)
+	}
+	return stored
+}
+
+// assertPreserved checks that every field other than the client-writable fields, UpdatedAt and Version is unchanged
+func assertPreserved(t *testing.T, original *models.DatasetProposal, stored *models.DatasetProposal) {
+	t.Helper()
+
+	expected := *original
+	expected.Name = stored.Name
+	expected.Description = stored.Description
+	expected.Survey = stored.Survey
+	expected.Contributors = stored.Contributors
+	expected.UpdatedAt = stored.UpdatedAt
+	expected.Version = original.Version + 1
+	if !reflect.DeepEqual(&expected, stored) {
+		t.Errorf("service-owned fields changed:\n  got  %+v\n  want %+v", stored, &expected)
+	}
+}
+
+func updateRequest(proposal *models.DatasetProposal) dtos.DatasetProposalUpdateRequest {
+	dto := dtos.BuildDatasetProposalDTO(proposal)
+	return dtos.DatasetProposalUpdateRequest{
+		Name:         dto.Name,
+		Description:  dto.Description,
+		Survey:       dto.Survey,
+		Contributors: dto.Contributors,
+	}
+}
+
+func TestUpdateDatasetProposalPreservesServiceOwnedFields(t *testing.T) {
+	for _, status := range []string{"DRAFT", "WITHDRAWN"} {
+		t.Run(status, func(t *testing.T) {
+			service, pubStore, original := newTestService(t, status)
+
+			update := updateRequest(original)
+			update.Name = "Updated name"
+			update.Survey = append(update.Survey, dtos.SurveyDTO{QuestionId: 2, Response: "answer 2"})
+
+			result, err := service.UpdateDatasetProposal(context.Background(), 1, dtos.BuildDatasetProposalDTO(original), update)
+			if err != nil {
+				t.Fatalf("UpdateDatasetProposal() error: %v", err)
+			}
+
+			stored := getStored(t, pubStore)
+			if stored.Name != "Updated name" || len(stored.Survey) != 2 {
+				t.Errorf("update not applied: %+v", stored)
+			}
+			if stored.UpdatedAt <= original.UpdatedAt {
+				t.Errorf("UpdatedAt = %d, want later than %d", stored.UpdatedAt, original.UpdatedAt)
+			}
+			assertPreserved(t, original, stored)
+
+			if !reflect.DeepEqual(*result, dtos.BuildDatasetProposalDTO(stored)) {
+				t.Errorf("result does not match the stored proposal:\n  got  %+v\n  want %+v", *result, dtos.BuildDatasetProposalDTO(stored))
+			}
+		})
+	}
+}
+
+func TestUpdateDatasetProposalRejectsImmutableChanges(t *testing.T) {
+	for _, status := range []string{"SUBMITTED", "ACCEPTED", "REJECTED"} {
+		t.Run(status, func(t *testing.T) {
+			service, pubStore, original := newTestService(t, status)
+
+			update := updateRequest(original)
+			update.Name = "Updated name"
+			update.Contributors = nil
+
+			_, err := service.UpdateDatasetProposal(context.Background(), 1, dtos.BuildDatasetProposalDTO(original), update)
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("UpdateDatasetProposal() error = %v, want a *ValidationError", err)
+			}
+			var fields []string
+			for _, field := range validationErr.Fields {
+				fields = append(fields, field.Field)
+			}
+			if !reflect.DeepEqual(fields, []string{"name", "contributors"}) {
+				t.Errorf("invalid fields = %v, want [name contributors]", fields)
+			}
+
+			if stored := getStored(t, pubStore); !reflect.DeepEqual(stored, original) {
+				t.Errorf("stored proposal changed:\n  got  %+v\n  want %+v", stored, original)
+			}
+		})
+	}
+}
+
+func TestUpdateDatasetProposalAllowsUnchangedImmutableFields(t *testing.T) {
+	service, pubStore, original := newTestService(t, "ACCEPTED")
+
+	_, err := service.UpdateDatasetProposal(context.Background(), 1, dtos.BuildDatasetProposalDTO(original), updateRequest(original))
+	if err != nil {
+		t.Fatalf("UpdateDatasetProposal() error: %v", err)
+	}
+
+	stored := getStored(t, pubStore)
+	if stored.DatasetNodeId != testDatasetNodeId {
+		t.Errorf("DatasetNodeId = %q, want %q", stored.DatasetNodeId, testDatasetNodeId)
+	}
+	assertPreserved(t, original, stored)
+}
+
+func TestPatchDatasetProposalPreservesUnpatchedFields(t *testing.T) {
+	service, pubStore, original := newTestService(t, "DRAFT")
+
+	patch := dtos.DatasetProposalPatchRequest{
+		Name: dtos.Optional[string]{Set: true, Value: "Patched name"},
+	}
+	if _, err := service.PatchDatasetProposal(context.Background(), 1, dtos.BuildDatasetProposalDTO(original), patch); err != nil {
+		t.Fatalf("PatchDatasetProposal() error: %v", err)
+	}
+
+	stored := getStored(t, pubStore)
+	if stored.Name != "Patched name" {
+		t.Errorf("Name = %q, want %q", stored.Name, "Patched name")
+	}
+	if stored.Description != original.Description {
+		t.Errorf("Description = %q, want %q", stored.Description, original.Description)
+	}
+	if !reflect.DeepEqual(stored.Survey, original.Survey) {
+		t.Errorf("Survey = %+v, want %+v", stored.Survey, original.Survey)
+	}
+	if !reflect.DeepEqual(stored.Contributors, original.Contributors) {
+		t.Errorf("Contributors = %+v, want %+v", stored.Contributors, original.Contributors)
+	}
+	assertPreserved(t, original, stored)
+}
+
+func TestBuildDatasetProposalRoundTrip(t *testing.T) {
+	_, _, original := newTestService(t, "ACCEPTED")
+
+	if got := dtos.BuildDatasetProposal(dtos.BuildDatasetProposalDTO(original)); !reflect.DeepEqual(got, original) {
+		t.Errorf("BuildDatasetProposal(BuildDatasetProposalDTO()) =\n  %+v\nwant\n  %+v", got, original)
+	}
+}
+
+// racingStore changes the stored proposal, as a concurrent request would, after each time it is read
+type racingStore struct {
+	*inmemory.PublishingStore
+}
+
+func (s racingStore) GetDatasetProposal(ctx context.Context, userId int, nodeId string) (*models.DatasetProposal, error) {
+	proposal, err := s.PublishingStore.GetDatasetProposal(ctx, userId, nodeId)
+	if err != nil {
+		return nil, err
+	}
+	concurrent := *proposal
+	concurrent.Description = "Concurrent description"
+	if _, err := s.PublishingStore.UpdateDatasetProposal(ctx, &concurrent); err != nil {
+		return nil, err
+	}
+	return proposal, nil
+}
+
+func TestUpdateDatasetProposalConflictsWithConcurrentChange(t *testing.T) {
+	_, pubStore, original := newTestService(t, "DRAFT")
+	service := NewPublishingService(racingStore{pubStore}, inmemory.NewPennsieveStore(1), inmemory.NewNotifier())
+
+	update := updateRequest(original)
+	update.Name = "Updated name"
+	_, err := service.UpdateDatasetProposal(context.Background(), 1, dtos.BuildDatasetProposalDTO(original), update)
+	if !errors.Is(err, store.ErrConflict) {
+		t.Fatalf("UpdateDatasetProposal() error = %v, want ErrConflict", err)
+	}
+
+	stored := getStored(t, pubStore)
+	if stored.Name != original.Name || stored.Description != "Concurrent description" {
+		t.Errorf("stored proposal = %+v, want the concurrent change kept", stored)
+	}
+}
+
+// updateConcurrently changes the stored proposal's description, as a request made after the client's read would
+func updateConcurrently(t *testing.T, pubStore *inmemory.PublishingStore) {
+	t.Helper()
+	concurrent := getStored(t, pubStore)
+	concurrent.Description = "Concurrent description"
+	if _, err := pubStore.UpdateDatasetProposal(context.Background(), concurrent); err != nil {
+		t.Fatalf("updating stored proposal: %v", err)
+	}
+}
+
+func TestPatchDatasetProposalConflictsWithChangeSinceRead(t *testing.T) {
+	service, pubStore, original := newTestService(t, "DRAFT")
+
+	existing := dtos.BuildDatasetProposalDTO(getStored(t, pubStore))
+	updateConcurrently(t, pubStore)
+
+	patch := dtos.DatasetProposalPatchRequest{
+		Name: dtos.Optional[string]{Set: true, Value: "Patched name"},
+	}
+	_, err := service.PatchDatasetProposal(context.Background(), 1, existing, patch)
+	if !errors.Is(err, store.ErrConflict) {
+		t.Fatalf("PatchDatasetProposal() error = %v, want ErrConflict", err)
+	}
+
+	stored := getStored(t, pubStore)
+	if stored.Name != original.Name || stored.Description != "Concurrent description" {
+		t.Errorf("stored proposal = %+v, want the concurrent change kept", stored)
+	}
+}
+
+func TestUpdateDatasetProposalConflictsWithStaleVersion(t *testing.T) {
+	service, pubStore, original := newTestService(t, "DRAFT")
+
+	update := updateRequest(original)
+	update.Version = &original.Version
+	update.Name = "Updated name"
+	updateConcurrently(t, pubStore)
+
+	existing := dtos.BuildDatasetProposalDTO(getStored(t, pubStore))
+	_, err := service.UpdateDatasetProposal(context.Background(), 1, existing, update)
+	if !errors.Is(err, store.ErrConflict) {
+		t.Fatalf("UpdateDatasetProposal() error = %v, want ErrConflict", err)
+	}
+
+	stored := getStored(t, pubStore)
+	if stored.Name != original.Name || stored.Description != "Concurrent description" {
+		t.Errorf("stored proposal = %+v, want the concurrent change kept", stored)
+	}
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"fmt"
+	"github.com/pennsieve/publishing-service/api/dtos"
+	"github.com/pennsieve/publishing-service/api/models"
+	"net/mail"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	MaxNameLength        = 255
+	MaxDescriptionLength = 4000
+	MaxResponseLength    = 4000
+	MaxContributorField  = 255
+)
+
+// ValidationError reports every invalid field of a request
+type ValidationError struct {
+	Fields []dtos.FieldErrorDTO
+}
+
+func (e *ValidationError) Error() string {
+	var messages []string
+	for _, field := range e.Fields {
+		messages = append(messages, fmt.Sprintf("%s: %s", field.Field, field.Message))
+	}
+	return fmt.Sprintf("invalid request: %s", strings.Join(messages, "; "))
+}
+
+// DTO returns the response body describing the invalid fields
+func (e *ValidationError) DTO() dtos.ValidationErrorDTO {
+	return dtos.ValidationErrorDTO{
+		Message: "invalid request",
+		Errors:  e.Fields,
+	}
+}
+
+// NewValidationError creates a ValidationError for a single field
+func NewValidationError(field string, message string) *ValidationError {
+	return &ValidationError{Fields: []dtos.FieldErrorDTO{{Field: field, Message: message}}}
+}
+
+// validator collects the invalid fields of a request
+type validator struct {
+	fields []dtos.FieldErrorDTO
+}
+
+func (v *validator) add(field string, format string, args ...interface{}) {
+	v.fields = append(v.fields, dtos.FieldErrorDTO{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) maxLength(field string, value string, max int) {
+	if utf8.RuneCountInString(value) > max {
+		v.add(field, "must be at most %d characters", max)
+	}
+}
+
+func (v *validator) err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: v.fields}
+}
+
+// validateName checks the name of a Dataset Proposal, which is required
+func (v *validator) validateName(name string) {
+	if strings.TrimSpace(name) == "" {
+		v.add("name", "is required")
+	}
+	v.maxLength("name", name, MaxNameLength)
+}
+
+func (v *validator) validateDescription(description string) {
+	v.maxLength("description", description, MaxDescriptionLength)
+}
+
+// validateSurvey checks that each answer is to one of the Repository's questions, and that no question is answered twice
+func (v *validator) validateSurvey(survey []dtos.SurveyDTO, repository *models.Repository) {
+	known := make(map[int]bool)
+	if repository != nil {
+		for _, questionId := range repository.Questions {
+			known[questionId] = true
+		}
+	}
+
+	answered := make(map[int]bool)
+	for i, answer := range survey {
+		field := fmt.Sprintf("survey[%d]", i)
+		if !known[answer.QuestionId] {
+			v.add(field+".questionId", "unknown question id %d", answer.QuestionId)
+		} else if answered[answer.QuestionId] {
+			v.add(field+".questionId", "question %d is answered more than once", answer.QuestionId)
+		}
+		answered[answer.QuestionId] = true
+		v.maxLength(field+".response", answer.Response, MaxResponseLength)
+	}
+}
+
+// validateContributors checks that each contributor has a valid email address
+func (v *validator) validateContributors(contributors []dtos.ContributorDTO) {
+	for i, contributor := range contributors {
+		field := fmt.Sprintf("contributors[%d]", i)
+		v.maxLength(field+".firstName", contributor.FirstName, MaxContributorField)
+		v.maxLength(field+".lastName", contributor.LastName, MaxContributorField)
+		if !isEmailAddress(contributor.EmailAddress) {
+			v.add(field+".emailAddress", "must be a valid email address")
+		}
+	}
+}
+
+// isEmailAddress reports whether the value is a bare email address, e.g. "name@example.com"
+func isEmailAddress(value string) bool {
+	address, err := mail.ParseAddress(value)
+	return err == nil && address.Address == value && address.Name == ""
+}
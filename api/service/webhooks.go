@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/pennsieve/publishing-service/api/dtos"
+	"github.com/pennsieve/publishing-service/api/models"
+	"github.com/pennsieve/publishing-service/api/notification"
+	"github.com/pennsieve/publishing-service/api/outbox"
+	"github.com/pennsieve/publishing-service/api/store"
+	"github.com/pennsieve/publishing-service/api/webhook"
+	log "github.com/sirupsen/logrus"
+	"slices"
+	"strings"
+	"time"
+)
+
+// MaxWebhookDeliveries is the number of the most recent delivery attempts returned from a Webhook's delivery log
+const MaxWebhookDeliveries = 100
+
+// ErrWebhookNotFound is returned for a Webhook that does not exist in the Repository
+var ErrWebhookNotFound = errors.New("webhook not found")
+
+func buildWebhookDTO(webhook *models.Webhook) dtos.WebhookDTO {
+	return dtos.WebhookDTO{
+		Id:        webhook.Id,
+		URL:       webhook.URL,
+		Events:    append([]string{}, webhook.Events...),
+		Active:    webhook.Active,
+		CreatedAt: webhook.CreatedAt,
+		UpdatedAt: webhook.UpdatedAt,
+	}
+}
+
+func buildWebhookDeliveryDTO(delivery *models.WebhookDelivery) dtos.WebhookDeliveryDTO {
+	return dtos.WebhookDeliveryDTO{
+		Id:             delivery.Id,
+		EventId:        delivery.EventId,
+		EventType:      delivery.EventType,
+		ProposalNodeId: delivery.ProposalNodeId,
+		Attempt:        delivery.Attempt,
+		StatusCode:     delivery.StatusCode,
+		Error:          delivery.Error,
+		Succeeded:      delivery.Succeeded,
+		DurationMillis: delivery.DurationMillis,
+		AttemptedAt:    delivery.AttemptedAt,
+	}
+}
+
+// validateWebhook checks the URL and event filter of a Webhook
+func (s *publishingService) validateWebhook(request dtos.WebhookRequest) error {
+	v := &validator{}
+	if err := webhook.ValidateURL(request.URL, s.webhookPolicy.AllowPrivateAddresses); err != nil {
+		v.add("url", "%s", err.Error())
+	}
+	for i, event := range request.Events {
+		if !slices.Contains(webhook.EventTypes, event) {
+			v.add(fmt.Sprintf("events[%d]", i), "must be one of %s", strings.Join(webhook.EventTypes, ", "))
+		} else if slices.Index(request.Events, event) != i {
+			v.add(fmt.Sprintf("events[%d]", i), "%s is listed more than once", event)
+		}
+	}
+	return v.err()
+}
+
+func (s *publishingService) GetWebhooks(ctx context.Context, orgNodeId string) ([]dtos.WebhookDTO, error) {
+	log.WithFields(log.Fields{"orgNodeId": orgNodeId}).Info("service.GetWebhooks()")
+
+	webhooks, err := s.store.GetWebhooks(ctx, orgNodeId)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []dtos.WebhookDTO{}
+	for i := range webhooks {
+		results = append(results, buildWebhookDTO(&webhooks[i]))
+	}
+	return results, nil
+}
+
+// CreateWebhook registers a Webhook for the Repository. The result includes the secret that signs the
+// Webhook's payloads; it is not returned again.
+func (s *publishingService) CreateWebhook(ctx context.Context, orgNodeId string, userId int64, request dtos.WebhookRequest) (*dtos.WebhookDTO, error) {
+	log.WithFields(log.Fields{"orgNodeId": orgNodeId, "userId": userId, "url": request.URL, "events": request.Events}).Info("service.CreateWebhook()")
+
+	if err := s.validateWebhook(request); err != nil {
+		return nil, err
+	}
+
+	// only Repositories have proposals submitted to them
+	if _, err := s.store.GetRepository(ctx, orgNodeId); err != nil {
+		return nil, fmt.Errorf("workspace %s is not a repository: %w", orgNodeId, err)
+	}
+
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		return nil, fmt.Errorf("creating webhook secret: %w", err)
+	}
+
+	currentTime := time.Now().Unix()
+	created := &models.Webhook{
+		OrganizationNodeId: orgNodeId,
+		Id:                 uuid.NewString(),
+		URL:                request.URL,
+		Events:             request.Events,
+		Secret:             secret,
+		Active:             request.Active == nil || *request.Active,
+		CreatedBy:          userId,
+		CreatedAt:          currentTime,
+		UpdatedAt:          currentTime,
+	}
+	if err := s.store.PutWebhook(ctx, created); err != nil {
+		return nil, err
+	}
+
+	result := buildWebhookDTO(created)
+	result.Secret = created.Secret
+	return &result, nil
+}
+
+// getWebhook gets one of the Repository's Webhooks, or returns ErrWebhookNotFound
+func (s *publishingService) getWebhook(ctx context.Context, orgNodeId string, id string) (*models.Webhook, error) {
+	existing, err := s.store.GetWebhook(ctx, orgNodeId, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, ErrWebhookNotFound
+	}
+	return existing, err
+}
+
+// UpdateWebhook replaces the URL, event filter and active flag of one of the Repository's Webhooks; its secret is kept
+func (s *publishingService) UpdateWebhook(ctx context.Context, orgNodeId string, id string, request dtos.WebhookRequest) (*dtos.WebhookDTO, error) {
+	log.WithFields(log.Fields{"orgNodeId": orgNodeId, "id": id, "url": request.URL, "events": request.Events}).Info("service.UpdateWebhook()")
+
+	if err := s.validateWebhook(request); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.getWebhook(ctx, orgNodeId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	existing.URL = request.URL
+	existing.Events = request.Events
+	existing.Active = request.Active == nil || *request.Active
+	existing.UpdatedAt = time.Now().Unix()
+	if err := s.store.PutWebhook(ctx, existing); err != nil {
+		return nil, err
+	}
+
+	result := buildWebhookDTO(existing)
+	return &result, nil
+}
+
+// DeleteWebhook removes one of the Repository's Webhooks; events waiting to be delivered to it are skipped
+func (s *publishingService) DeleteWebhook(ctx context.Context, orgNodeId string, id string) error {
+	log.WithFields(log.Fields{"orgNodeId": orgNodeId, "id": id}).Info("service.DeleteWebhook()")
+
+	existing, err := s.getWebhook(ctx, orgNodeId, id)
+	if err != nil {
+		return err
+	}
+
+	return s.store.DeleteWebhook(ctx, existing)
+}
+
+// GetWebhookDeliveries returns the delivery log of one of the Repository's Webhooks, newest first
+func (s *publishingService) GetWebhookDeliveries(ctx context.Context, orgNodeId string, id string) ([]dtos.WebhookDeliveryDTO, error) {
+	log.WithFields(log.Fields{"orgNodeId": orgNodeId, "id": id}).Info("service.GetWebhookDeliveries()")
+
+	if _, err := s.getWebhook(ctx, orgNodeId, id); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.store.GetWebhookDeliveries(ctx, id, MaxWebhookDeliveries)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []dtos.WebhookDeliveryDTO{}
+	for i := range deliveries {
+		results = append(results, buildWebhookDeliveryDTO(&deliveries[i]))
+	}
+	return results, nil
+}
+
+// subscribedWebhooks returns the Ids of the Repository's Webhooks that subscribe to the event reporting the action
+func (s *publishingService) subscribedWebhooks(ctx context.Context, repository *models.Repository, action notification.Notification) ([]string, error) {
+	eventType, found := webhook.EventType(action)
+	if !found {
+		return nil, nil
+	}
+
+	webhooks, err := s.store.GetWebhooks(ctx, repository.OrganizationNodeId)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for i := range webhooks {
+		if webhook.Subscribes(&webhooks[i], eventType) {
+			ids = append(ids, webhooks[i].Id)
+		}
+	}
+	return ids, nil
+}
+
+// webhookMessage builds the outbox message that POSTs the event reporting the action to the subscribed Webhooks,
+// or returns nil if there are none
+func (s *publishingService) webhookMessage(proposal *models.DatasetProposal, action notification.Notification, repository *models.Repository, webhookIds []string, now time.Time) (*models.OutboxMessage, error) {
+	eventType, found := webhook.EventType(action)
+	if !found || len(webhookIds) == 0 {
+		return nil, nil
+	}
+
+	payload, err := json.Marshal(webhook.NewEvent(eventType, proposal, repository, now))
+	if err != nil {
+		return nil, fmt.Errorf("marshalling webhook event: %w", err)
+	}
+
+	message := outbox.NewWebhookMessage(action, proposal.NodeId, repository.OrganizationNodeId, payload, webhookIds, s.outbox, now)
+	return &message, nil
+}
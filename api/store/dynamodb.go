@@ -2,60 +2,95 @@ package store
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/pennsieve/publishing-service/api/config"
 	"github.com/pennsieve/publishing-service/api/models"
 	log "github.com/sirupsen/logrus"
-	"os"
+	"sort"
+	"time"
 )
 
 type PublishingStore interface {
-	GetInfo() ([]models.Info, error)
-	GetRepositories() ([]models.Repository, error)
-	GetRepository(organizationNodeId string) (*models.Repository, error)
-	GetQuestions() ([]models.Question, error)
-	GetDatasetProposal(userId int, nodeId string) (*models.DatasetProposal, error)
-	GetDatasetProposalsForUser(userId int64) ([]models.DatasetProposal, error)
-	GetDatasetProposalsForWorkspace(orgNodeId string, status string) ([]models.DatasetProposal, error)
-	GetDatasetProposalForRepository(orgNodeId string, status string, nodeId string) (*models.DatasetProposal, error)
-	CreateDatasetProposal(proposal *models.DatasetProposal) (*models.DatasetProposal, error)
-	UpdateDatasetProposal(proposal *models.DatasetProposal) (*models.DatasetProposal, error)
-	DeleteDatasetProposal(proposal *models.DatasetProposal) error
-}
-
-func getTableName(tableName string) string {
-	table := os.Getenv(tableName)
-	return table
-}
-
-func NewPublishingStore() *publishingStore {
-	// TODO: handle and/or propagate errors
-	cfg, err := config.LoadDefaultConfig(context.Background())
-	if err != nil {
-		// TODO: handle error
-	}
+	GetInfo(ctx context.Context) ([]models.Info, error)
+	GetRepositories(ctx context.Context) ([]models.Repository, error)
+	GetRepository(ctx context.Context, organizationNodeId string) (*models.Repository, error)
+	GetQuestions(ctx context.Context) ([]models.Question, error)
+	GetDatasetProposal(ctx context.Context, userId int, nodeId string) (*models.DatasetProposal, error)
+	GetDatasetProposalsForUser(ctx context.Context, userId int64) ([]models.DatasetProposal, error)
+	GetDatasetProposalsForWorkspace(ctx context.Context, orgNodeId string, status string) ([]models.DatasetProposal, error)
+	GetDatasetProposalByNodeId(ctx context.Context, nodeId string) (*models.DatasetProposal, error)
+	CreateDatasetProposal(ctx context.Context, proposal *models.DatasetProposal) (*models.DatasetProposal, error)
+	UpdateDatasetProposal(ctx context.Context, proposal *models.DatasetProposal) (*models.DatasetProposal, error)
+	DeleteDatasetProposal(ctx context.Context, proposal *models.DatasetProposal) error
+	RecordDraftReminder(ctx context.Context, draft *models.DatasetProposal, remindedAt int64) error
+	ArchiveDraft(ctx context.Context, draft *models.DatasetProposal, expiresAt int64) error
+	DeleteDraft(ctx context.Context, draft *models.DatasetProposal) error
+	RecordReviewReminder(ctx context.Context, proposal *models.DatasetProposal, remindedAt int64) error
+	RecordReviewEscalation(ctx context.Context, proposal *models.DatasetProposal, escalatedAt int64) error
+	SearchDatasetProposals(ctx context.Context, orgNodeId string, query string) ([]models.DatasetProposal, error)
+	GetNotificationPreference(ctx context.Context, userId int64) (*models.NotificationPreference, error)
+	PutNotificationPreference(ctx context.Context, preference *models.NotificationPreference) error
+	RecordDigest(ctx context.Context, userId int64, digestAt int64) error
+	UpdateDatasetProposalWithOutbox(ctx context.Context, proposal *models.DatasetProposal, messages []models.OutboxMessage) (*models.DatasetProposal, error)
+	GetDueOutboxMessages(ctx context.Context, now int64) ([]models.OutboxMessage, error)
+	PutOutboxMessage(ctx context.Context, message *models.OutboxMessage, expectedVersion int64) error
+	GetWebhooks(ctx context.Context, orgNodeId string) ([]models.Webhook, error)
+	GetWebhook(ctx context.Context, orgNodeId string, id string) (*models.Webhook, error)
+	PutWebhook(ctx context.Context, webhook *models.Webhook) error
+	DeleteWebhook(ctx context.Context, webhook *models.Webhook) error
+	GetWebhookDeliveries(ctx context.Context, webhookId string, limit int) ([]models.WebhookDelivery, error)
+	PutWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+}
+
+// ErrConflict is returned by a conditional write of an item that has been changed since it was read
+var ErrConflict = errors.New("item has been changed")
 
-	db := dynamodb.NewFromConfig(cfg)
+// ErrNotFound is returned by a get of a single item that does not exist
+var ErrNotFound = errors.New("item not found")
 
+// DynamoDBAPI is the subset of the DynamoDB client used by the store, so that tests may provide a fake
+type DynamoDBAPI interface {
+	dynamodb.ScanAPIClient
+	dynamodb.QueryAPIClient
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)
+	DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
+	TransactWriteItems(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error)
+}
+
+func NewPublishingStore(db DynamoDBAPI, search ProposalSearchIndex, tables config.Tables, timeout time.Duration) *publishingStore {
 	return &publishingStore{
 		db:                    db,
-		infoTable:             getTableName("PUBLISHING_INFO_TABLE"),
-		repositoriesTable:     getTableName("REPOSITORIES_TABLE"),
-		questionsTable:        getTableName("REPOSITORY_QUESTIONS_TABLE"),
-		datasetProposalsTable: getTableName("DATASET_PROPOSAL_TABLE"),
+		search:                search,
+		infoTable:             tables.Info,
+		repositoriesTable:     tables.Repositories,
+		questionsTable:        tables.Questions,
+		datasetProposalsTable: tables.DatasetProposals,
+		preferencesTable:      tables.NotificationPreferences,
+		outboxTable:           tables.NotificationOutbox,
+		webhooksTable:         tables.Webhooks,
+		deliveriesTable:       tables.WebhookDeliveries,
+		timeout:               timeout,
 	}
 }
 
 type publishingStore struct {
-	db                    *dynamodb.Client
+	db                    DynamoDBAPI
+	search                ProposalSearchIndex
 	infoTable             string
 	repositoriesTable     string
 	questionsTable        string
 	datasetProposalsTable string
+	preferencesTable      string
+	outboxTable           string
+	webhooksTable         string
+	deliveriesTable       string
+	timeout               time.Duration
 }
 
 func intToString(i int) string {
@@ -66,7 +101,8 @@ func int64ToString(i int64) string {
 	return fmt.Sprintf("%d", i)
 }
 
-func scan(client *dynamodb.Client, tableName string) (*dynamodb.ScanOutput, error) {
+// scan reads every page of the table, stopping early if the context is cancelled
+func scan(ctx context.Context, client DynamoDBAPI, tableName string) ([]map[string]types.AttributeValue, error) {
 	log.WithFields(log.Fields{"tableName": tableName}).Debug("scan()")
 
 	scanInput := dynamodb.ScanInput{
@@ -74,28 +110,41 @@ func scan(client *dynamodb.Client, tableName string) (*dynamodb.ScanOutput, erro
 	}
 	log.WithFields(log.Fields{"scanInput": fmt.Sprintf("%+v", scanInput)}).Debug("scan()")
 
-	result, err := client.Scan(context.TODO(), &scanInput)
-	if err != nil {
-		log.Error("scan() err: ", err)
-		return nil, err
+	var items []map[string]types.AttributeValue
+	paginator := dynamodb.NewScanPaginator(client, &scanInput)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Error("scan() err: ", err)
+			return nil, err
+		}
+		items = append(items, page.Items...)
 	}
 
-	return result, nil
+	return items, nil
 }
 
-func query(client *dynamodb.Client, queryInput *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+// query reads every page of the query results, stopping early if the context is cancelled
+func query(ctx context.Context, client DynamoDBAPI, queryInput *dynamodb.QueryInput) ([]map[string]types.AttributeValue, error) {
 	log.WithFields(log.Fields{"queryInput": fmt.Sprintf("%#v", queryInput)}).Debug("query()")
-	result, err := client.Query(context.TODO(), queryInput)
-	if err != nil {
-		log.Error("query() err: ", err)
-		return nil, err
+
+	var items []map[string]types.AttributeValue
+	paginator := dynamodb.NewQueryPaginator(client, queryInput)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Error("query() err: ", err)
+			return nil, err
+		}
+		items = append(items, page.Items...)
 	}
 
-	return result, nil
+	return items, nil
 }
 
 type PublishingTypes interface {
-	models.Info | models.Repository | models.Question | models.DatasetProposal
+	models.Info | models.Repository | models.Question | models.DatasetProposal | models.NotificationPreference | models.OutboxMessage |
+		models.Webhook | models.WebhookDelivery
 }
 
 // TODO: figure out struct embedding to simplify list of types allowed?
@@ -112,19 +161,19 @@ func transform[T PublishingTypes](items []map[string]types.AttributeValue) ([]T,
 	return results, nil
 }
 
-func fetch[T PublishingTypes](client *dynamodb.Client, tableName string) ([]T, error) {
+func fetch[T PublishingTypes](ctx context.Context, client DynamoDBAPI, tableName string) ([]T, error) {
 	log.WithFields(log.Fields{"tableName": tableName}).Debug("fetch()")
 	var err error
 
 	// get all Items from the table via Scan operation
-	output, err := scan(client, tableName)
+	items, err := scan(ctx, client, tableName)
 	if err != nil {
 		log.Error("fetch() - scan() err: ", err)
 		return nil, err
 	}
 
 	// transform each Item in output from DynamoDB to type T
-	results, err := transform[T](output.Items)
+	results, err := transform[T](items)
 	if err != nil {
 		log.Error("fetch() - transform() err: ", err)
 		return nil, err
@@ -133,18 +182,18 @@ func fetch[T PublishingTypes](client *dynamodb.Client, tableName string) ([]T, e
 	return results, nil
 }
 
-func find[T PublishingTypes](client *dynamodb.Client, queryInput *dynamodb.QueryInput) ([]T, error) {
+func find[T PublishingTypes](ctx context.Context, client DynamoDBAPI, queryInput *dynamodb.QueryInput) ([]T, error) {
 	log.WithFields(log.Fields{"queryInput": fmt.Sprintf("%#v", queryInput)}).Debug("find()")
 	var err error
 
-	output, err := query(client, queryInput)
+	items, err := query(ctx, client, queryInput)
 	if err != nil {
 		log.Error("find() - query() err: ", err)
 		return nil, err
 	}
 
 	// transform each Item in output from DynamoDB to type T
-	results, err := transform[T](output.Items)
+	results, err := transform[T](items)
 	if err != nil {
 		log.Error("find() - transform() err: ", err)
 		return nil, err
@@ -153,16 +202,16 @@ func find[T PublishingTypes](client *dynamodb.Client, queryInput *dynamodb.Query
 	return results, nil
 }
 
-func get[T PublishingTypes](client *dynamodb.Client, queryInput *dynamodb.QueryInput) (*T, error) {
+func get[T PublishingTypes](ctx context.Context, client DynamoDBAPI, queryInput *dynamodb.QueryInput) (*T, error) {
 	log.WithFields(log.Fields{"queryInput": fmt.Sprintf("%#v", queryInput)}).Debug("get()")
-	results, err := find[T](client, queryInput)
+	results, err := find[T](ctx, client, queryInput)
 	if err != nil {
 		log.Error("get() - find() err: ", err)
 		return nil, err
 	}
 
 	if len(results) == 0 {
-		return nil, fmt.Errorf("item not found")
+		return nil, ErrNotFound
 	}
 
 	if len(results) > 1 {
@@ -172,36 +221,65 @@ func get[T PublishingTypes](client *dynamodb.Client, queryInput *dynamodb.QueryI
 	return &results[0], nil
 }
 
-// TODO: make this function a generic ~> item T[]
-func store(client *dynamodb.Client, table string, item *models.DatasetProposal) (*dynamodb.PutItemOutput, error) {
+func store[T PublishingTypes](ctx context.Context, client DynamoDBAPI, table string, item *T) (*dynamodb.PutItemOutput, error) {
 	log.WithFields(log.Fields{"table": table, "item": fmt.Sprintf("%#v", item)}).Debug("store()")
 
 	var err error
 	data, err := attributevalue.MarshalMap(item)
 	if err != nil {
-		log.Fatalln("store.CreateDatasetProposal() - attributevalue.MarshalMap() failed: ", err)
+		log.Error("store() - attributevalue.MarshalMap() failed: ", err)
 		return nil, err
 	}
-	log.WithFields(log.Fields{"data": fmt.Sprintf("%+v", data)}).Debug("store.CreateDatasetProposal()")
+	log.WithFields(log.Fields{"data": fmt.Sprintf("%+v", data)}).Debug("store()")
 
-	return client.PutItem(context.TODO(), &dynamodb.PutItemInput{
+	return client.PutItem(ctx, &dynamodb.PutItemInput{
 		TableName: aws.String(table),
 		Item:      data,
 	})
 }
 
-func (s *publishingStore) GetInfo() ([]models.Info, error) {
+func (s *publishingStore) GetInfo(ctx context.Context) ([]models.Info, error) {
 	log.Info("store.GetInfo()")
-	return fetch[models.Info](s.db, s.infoTable)
+	ctx, cancel := config.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	info, err := fetch[models.Info](ctx, s.db, s.infoTable)
+	if err != nil {
+		return nil, err
+	}
+
+	// order by Tag, then Type
+	sort.SliceStable(info, func(i, j int) bool {
+		if info[i].Tag != info[j].Tag {
+			return info[i].Tag < info[j].Tag
+		}
+		return info[i].Type < info[j].Type
+	})
+	return info, nil
 }
 
-func (s *publishingStore) GetRepositories() ([]models.Repository, error) {
+func (s *publishingStore) GetRepositories(ctx context.Context) ([]models.Repository, error) {
 	log.Info("store.GetRepositories()")
-	return fetch[models.Repository](s.db, s.repositoriesTable)
+	ctx, cancel := config.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	repositories, err := fetch[models.Repository](ctx, s.db, s.repositoriesTable)
+	if err != nil {
+		return nil, err
+	}
+
+	// order by DisplayName, then OrganizationNodeId
+	sort.SliceStable(repositories, func(i, j int) bool {
+		if repositories[i].DisplayName != repositories[j].DisplayName {
+			return repositories[i].DisplayName < repositories[j].DisplayName
+		}
+		return repositories[i].OrganizationNodeId < repositories[j].OrganizationNodeId
+	})
+	return repositories, nil
 }
 
-func (s *publishingStore) GetRepository(organizationNodeId string) (*models.Repository, error) {
+func (s *publishingStore) GetRepository(ctx context.Context, organizationNodeId string) (*models.Repository, error) {
 	log.WithFields(log.Fields{"organizationNodeId": organizationNodeId}).Info("GetRepository()")
+	ctx, cancel := config.WithTimeout(ctx, s.timeout)
+	defer cancel()
 	queryInput := dynamodb.QueryInput{
 		TableName:              aws.String(s.repositoriesTable),
 		KeyConditionExpression: aws.String("OrganizationNodeId = :organizationNodeId"),
@@ -211,16 +289,29 @@ func (s *publishingStore) GetRepository(organizationNodeId string) (*models.Repo
 			},
 		},
 	}
-	return get[models.Repository](s.db, &queryInput)
+	return get[models.Repository](ctx, s.db, &queryInput)
 }
 
-func (s *publishingStore) GetQuestions() ([]models.Question, error) {
+func (s *publishingStore) GetQuestions(ctx context.Context) ([]models.Question, error) {
 	log.Info("store.GetQuestions()")
-	return fetch[models.Question](s.db, s.questionsTable)
+	ctx, cancel := config.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	questions, err := fetch[models.Question](ctx, s.db, s.questionsTable)
+	if err != nil {
+		return nil, err
+	}
+
+	// order by Id
+	sort.SliceStable(questions, func(i, j int) bool {
+		return questions[i].Id < questions[j].Id
+	})
+	return questions, nil
 }
 
-func (s *publishingStore) GetDatasetProposal(userId int, nodeId string) (*models.DatasetProposal, error) {
+func (s *publishingStore) GetDatasetProposal(ctx context.Context, userId int, nodeId string) (*models.DatasetProposal, error) {
 	log.WithFields(log.Fields{"userId": userId, "nodeId": nodeId}).Info("store.GetDatasetProposal()")
+	ctx, cancel := config.WithTimeout(ctx, s.timeout)
+	defer cancel()
 	queryInput := dynamodb.QueryInput{
 		TableName:              aws.String(s.datasetProposalsTable),
 		KeyConditionExpression: aws.String("UserId = :userId AND NodeId = :nodeId"),
@@ -233,11 +324,13 @@ func (s *publishingStore) GetDatasetProposal(userId int, nodeId string) (*models
 			},
 		},
 	}
-	return get[models.DatasetProposal](s.db, &queryInput)
+	return get[models.DatasetProposal](ctx, s.db, &queryInput)
 }
 
-func (s *publishingStore) GetDatasetProposalsForUser(userId int64) ([]models.DatasetProposal, error) {
+func (s *publishingStore) GetDatasetProposalsForUser(ctx context.Context, userId int64) ([]models.DatasetProposal, error) {
 	log.WithFields(log.Fields{"userId": userId}).Info("store.GetDatasetProposalsForUser()")
+	ctx, cancel := config.WithTimeout(ctx, s.timeout)
+	defer cancel()
 	queryInput := dynamodb.QueryInput{
 		TableName:              aws.String(s.datasetProposalsTable),
 		KeyConditionExpression: aws.String("UserId = :userId"),
@@ -247,11 +340,13 @@ func (s *publishingStore) GetDatasetProposalsForUser(userId int64) ([]models.Dat
 			},
 		},
 	}
-	return find[models.DatasetProposal](s.db, &queryInput)
+	return find[models.DatasetProposal](ctx, s.db, &queryInput)
 }
 
-func (s *publishingStore) GetDatasetProposalsForWorkspace(orgNodeId string, status string) ([]models.DatasetProposal, error) {
+func (s *publishingStore) GetDatasetProposalsForWorkspace(ctx context.Context, orgNodeId string, status string) ([]models.DatasetProposal, error) {
 	log.WithFields(log.Fields{"orgNodeId": orgNodeId}).Info("store.GetDatasetProposalsForWorkspace()")
+	ctx, cancel := config.WithTimeout(ctx, s.timeout)
+	defer cancel()
 	queryInput := dynamodb.QueryInput{
 		TableName:              aws.String(s.datasetProposalsTable),
 		IndexName:              aws.String("RepositoryProposalStatusIndex"),
@@ -266,37 +361,86 @@ func (s *publishingStore) GetDatasetProposalsForWorkspace(orgNodeId string, stat
 		},
 		Select: "ALL_PROJECTED_ATTRIBUTES",
 	}
-	return find[models.DatasetProposal](s.db, &queryInput)
+	return find[models.DatasetProposal](ctx, s.db, &queryInput)
 }
 
-func (s *publishingStore) CreateDatasetProposal(proposal *models.DatasetProposal) (*models.DatasetProposal, error) {
+func (s *publishingStore) CreateDatasetProposal(ctx context.Context, proposal *models.DatasetProposal) (*models.DatasetProposal, error) {
 	log.Info("store.CreateDatasetProposal()")
+	ctx, cancel := config.WithTimeout(ctx, s.timeout)
+	defer cancel()
 
-	result, err := store(s.db, s.datasetProposalsTable, proposal)
+	result, err := store(ctx, s.db, s.datasetProposalsTable, proposal)
 	if err != nil {
-		log.Fatalln("store.CreateDatasetProposal() - store() failed: ", err)
+		log.Error("store.CreateDatasetProposal() - store() failed: ", err)
 		return nil, err
 	}
 	log.WithFields(log.Fields{"result": fmt.Sprintf("%+v", result)}).Debug("store.CreateDatasetProposal()")
 
+	// the proposal has been written; an index that is not updated is repaired by the search-backfill command
+	if err := s.search.Index(ctx, proposal); err != nil {
+		log.WithFields(log.Fields{"failure": "search.Index()", "nodeId": proposal.NodeId, "error": fmt.Sprintf("%+v", err)}).Error("store.CreateDatasetProposal()")
+	}
+
 	return proposal, nil
 }
 
-func (s *publishingStore) UpdateDatasetProposal(proposal *models.DatasetProposal) (*models.DatasetProposal, error) {
-	log.Info("store.UpdateDatasetProposal()")
+// UpdateDatasetProposal writes the Dataset Proposal, provided that it exists and the stored proposal is still at
+// proposal.Version; otherwise ErrConflict is returned. The version is incremented by the write.
+func (s *publishingStore) UpdateDatasetProposal(ctx context.Context, proposal *models.DatasetProposal) (*models.DatasetProposal, error) {
+	log.WithFields(log.Fields{"nodeId": proposal.NodeId, "version": proposal.Version}).Info("store.UpdateDatasetProposal()")
+	ctx, cancel := config.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	expectedVersion := proposal.Version
+	proposal.Version++
+	item, err := attributevalue.MarshalMap(proposal)
+	if err != nil {
+		proposal.Version = expectedVersion
+		return nil, fmt.Errorf("marshalling dataset proposal: %w", err)
+	}
 
-	result, err := store(s.db, s.datasetProposalsTable, proposal)
+	result, err := s.db.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(s.datasetProposalsTable),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_exists(NodeId) AND " + versionCondition(expectedVersion)),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":version": &types.AttributeValueMemberN{
+				Value: int64ToString(expectedVersion),
+			},
+		},
+	})
 	if err != nil {
-		log.Fatalln("store.UpdateDatasetProposal() - store() failed: ", err)
+		proposal.Version = expectedVersion
+		var conditionFailed *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionFailed) {
+			return nil, ErrConflict
+		}
+		log.WithFields(log.Fields{"failure": "PutItem()", "error": fmt.Sprintf("%+v", err)}).Error("store.UpdateDatasetProposal()")
 		return nil, err
 	}
 	log.WithFields(log.Fields{"result": fmt.Sprintf("%+v", result)}).Debug("store.UpdateDatasetProposal()")
 
+	// the proposal has been written; an index that is not updated is repaired by the search-backfill command
+	if err := s.search.Index(ctx, proposal); err != nil {
+		log.WithFields(log.Fields{"failure": "search.Index()", "nodeId": proposal.NodeId, "error": fmt.Sprintf("%+v", err)}).Error("store.UpdateDatasetProposal()")
+	}
+
 	return proposal, nil
 }
 
-func (s *publishingStore) DeleteDatasetProposal(proposal *models.DatasetProposal) error {
+// versionCondition matches a stored Dataset Proposal at the expected version; proposals written before versioning
+// have no Version attribute, and are at version 0
+func versionCondition(expectedVersion int64) string {
+	if expectedVersion == 0 {
+		return "(attribute_not_exists(Version) OR Version = :version)"
+	}
+	return "Version = :version"
+}
+
+func (s *publishingStore) DeleteDatasetProposal(ctx context.Context, proposal *models.DatasetProposal) error {
 	log.WithFields(log.Fields{"proposal": fmt.Sprintf("%+v", proposal)}).Info("store.DeleteDatasetProposal()")
+	ctx, cancel := config.WithTimeout(ctx, s.timeout)
+	defer cancel()
 
 	var err error
 	proposalKey, err := attributevalue.MarshalMap(models.DatasetProposalKey{
@@ -304,44 +448,525 @@ func (s *publishingStore) DeleteDatasetProposal(proposal *models.DatasetProposal
 		NodeId: proposal.NodeId,
 	})
 	if err != nil {
-		log.Fatalln("store.DeleteDatasetProposal() - MarshalMap() failed: ", err)
+		log.Error("store.DeleteDatasetProposal() - MarshalMap() failed: ", err)
 		return err
 	}
 	log.WithFields(log.Fields{"proposalKey": fmt.Sprintf("%+v", proposalKey)}).Debug("store.DeleteDatasetProposal()")
 
-	_, err = s.db.DeleteItem(context.TODO(), &dynamodb.DeleteItemInput{
+	_, err = s.db.DeleteItem(ctx, &dynamodb.DeleteItemInput{
 		TableName: aws.String(s.datasetProposalsTable),
 		Key:       proposalKey,
 	})
 
 	if err != nil {
-		log.Fatalln("store.DeleteDatasetProposal() - DeleteItem() failed: ", err)
+		log.Error("store.DeleteDatasetProposal() - DeleteItem() failed: ", err)
+		return err
+	}
+
+	// the proposal has been deleted; an entry left in the index is skipped by SearchDatasetProposals
+	if err := s.search.Remove(ctx, proposal); err != nil {
+		log.WithFields(log.Fields{"failure": "search.Remove()", "nodeId": proposal.NodeId, "error": fmt.Sprintf("%+v", err)}).Error("store.DeleteDatasetProposal()")
+	}
+
+	return nil
+}
+
+// draftUnchanged matches a stored Dataset Proposal that is still a DRAFT, and has not been changed since it was read
+func draftUnchanged(draft *models.DatasetProposal) (string, map[string]types.AttributeValue) {
+	return "ProposalStatus = :draft AND UpdatedAt = :updatedAt", map[string]types.AttributeValue{
+		":draft": &types.AttributeValueMemberS{
+			Value: "DRAFT",
+		},
+		":updatedAt": &types.AttributeValueMemberN{
+			Value: int64ToString(draft.UpdatedAt),
+		},
+	}
+}
+
+// updateProposal sets attributes of the Dataset Proposal, provided that the condition holds; otherwise ErrConflict
+// is returned. The Version is incremented, so that a concurrent read-modify-write of the proposal conflicts.
+func (s *publishingStore) updateProposal(ctx context.Context, proposal *models.DatasetProposal, set string, condition string, values map[string]types.AttributeValue) error {
+	proposalKey, err := attributevalue.MarshalMap(models.DatasetProposalKey{
+		UserId: proposal.UserId,
+		NodeId: proposal.NodeId,
+	})
+	if err != nil {
+		return fmt.Errorf("marshalling dataset proposal key: %w", err)
+	}
+
+	values[":one"] = &types.AttributeValueMemberN{Value: "1"}
+	_, err = s.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(s.datasetProposalsTable),
+		Key:                       proposalKey,
+		UpdateExpression:          aws.String("SET " + set + " ADD Version :one"),
+		ConditionExpression:       aws.String(condition),
+		ExpressionAttributeValues: values,
+	})
+
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return ErrConflict
+	}
+	if err != nil {
+		return err
+	}
+
+	proposal.Version++
+	return nil
+}
+
+// RecordDraftReminder records that the author of the draft was reminded, provided that it is still a DRAFT and has
+// not been changed since it was read; otherwise ErrConflict is returned
+func (s *publishingStore) RecordDraftReminder(ctx context.Context, draft *models.DatasetProposal, remindedAt int64) error {
+	log.WithFields(log.Fields{"nodeId": draft.NodeId, "remindedAt": remindedAt}).Info("store.RecordDraftReminder()")
+	ctx, cancel := config.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	condition, values := draftUnchanged(draft)
+	values[":remindedAt"] = &types.AttributeValueMemberN{Value: int64ToString(remindedAt)}
+	if err := s.updateProposal(ctx, draft, "ReminderSentAt = :remindedAt", condition, values); err != nil {
+		return err
+	}
+
+	draft.ReminderSentAt = remindedAt
+	return nil
+}
+
+// ArchiveDraft sets the draft's status to ARCHIVED, to be removed by the table's TTL at expiresAt, provided that it
+// is still a DRAFT and has not been changed since it was read; otherwise ErrConflict is returned
+func (s *publishingStore) ArchiveDraft(ctx context.Context, draft *models.DatasetProposal, expiresAt int64) error {
+	log.WithFields(log.Fields{"nodeId": draft.NodeId, "expiresAt": expiresAt}).Info("store.ArchiveDraft()")
+	ctx, cancel := config.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	condition, values := draftUnchanged(draft)
+	values[":archived"] = &types.AttributeValueMemberS{Value: "ARCHIVED"}
+	values[":expiresAt"] = &types.AttributeValueMemberN{Value: int64ToString(expiresAt)}
+	if err := s.updateProposal(ctx, draft, "ProposalStatus = :archived, ExpiresAt = :expiresAt", condition, values); err != nil {
+		return err
+	}
+
+	draft.ProposalStatus = "ARCHIVED"
+	draft.ExpiresAt = expiresAt
+	// the proposal has been written; an index that is not updated is repaired by the search-backfill command
+	if err := s.search.Index(ctx, draft); err != nil {
+		log.WithFields(log.Fields{"failure": "search.Index()", "nodeId": draft.NodeId, "error": fmt.Sprintf("%+v", err)}).Error("store.ArchiveDraft()")
+	}
+	return nil
+}
+
+// DeleteDraft deletes the draft, provided that it is still a DRAFT and has not been changed since it was read;
+// otherwise ErrConflict is returned
+func (s *publishingStore) DeleteDraft(ctx context.Context, draft *models.DatasetProposal) error {
+	log.WithFields(log.Fields{"nodeId": draft.NodeId}).Info("store.DeleteDraft()")
+	ctx, cancel := config.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	proposalKey, err := attributevalue.MarshalMap(models.DatasetProposalKey{
+		UserId: draft.UserId,
+		NodeId: draft.NodeId,
+	})
+	if err != nil {
+		return fmt.Errorf("marshalling dataset proposal key: %w", err)
+	}
+
+	condition, values := draftUnchanged(draft)
+	_, err = s.db.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName:                 aws.String(s.datasetProposalsTable),
+		Key:                       proposalKey,
+		ConditionExpression:       aws.String(condition),
+		ExpressionAttributeValues: values,
+	})
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return ErrConflict
+	}
+	if err != nil {
+		return err
+	}
+
+	// the proposal has been deleted; an entry left in the index is skipped by SearchDatasetProposals
+	if err := s.search.Remove(ctx, draft); err != nil {
+		log.WithFields(log.Fields{"failure": "search.Remove()", "nodeId": draft.NodeId, "error": fmt.Sprintf("%+v", err)}).Error("store.DeleteDraft()")
+	}
+	return nil
+}
+
+// stillSubmitted matches a stored Dataset Proposal that is still SUBMITTED, and has not been withdrawn and submitted
+// again since it was read
+func stillSubmitted(proposal *models.DatasetProposal) (string, map[string]types.AttributeValue) {
+	return "ProposalStatus = :submitted AND SubmittedAt = :submittedAt", map[string]types.AttributeValue{
+		":submitted": &types.AttributeValueMemberS{
+			Value: "SUBMITTED",
+		},
+		":submittedAt": &types.AttributeValueMemberN{
+			Value: int64ToString(proposal.SubmittedAt),
+		},
+	}
+}
+
+// RecordReviewReminder records that the publishers were reminded to review the proposal, provided that it is still
+// awaiting the review it was read for; otherwise ErrConflict is returned
+func (s *publishingStore) RecordReviewReminder(ctx context.Context, proposal *models.DatasetProposal, remindedAt int64) error {
+	log.WithFields(log.Fields{"nodeId": proposal.NodeId, "remindedAt": remindedAt}).Info("store.RecordReviewReminder()")
+	ctx, cancel := config.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	condition, values := stillSubmitted(proposal)
+	values[":remindedAt"] = &types.AttributeValueMemberN{Value: int64ToString(remindedAt)}
+	if err := s.updateProposal(ctx, proposal, "ReviewRemindedAt = :remindedAt", condition, values); err != nil {
+		return err
+	}
+
+	proposal.ReviewRemindedAt = remindedAt
+	return nil
+}
+
+// RecordReviewEscalation records that the review of the proposal was escalated, provided that it is still awaiting
+// the review it was read for; otherwise ErrConflict is returned
+func (s *publishingStore) RecordReviewEscalation(ctx context.Context, proposal *models.DatasetProposal, escalatedAt int64) error {
+	log.WithFields(log.Fields{"nodeId": proposal.NodeId, "escalatedAt": escalatedAt}).Info("store.RecordReviewEscalation()")
+	ctx, cancel := config.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	condition, values := stillSubmitted(proposal)
+	values[":escalatedAt"] = &types.AttributeValueMemberN{Value: int64ToString(escalatedAt)}
+	if err := s.updateProposal(ctx, proposal, "ReviewEscalatedAt = :escalatedAt", condition, values); err != nil {
 		return err
 	}
 
+	proposal.ReviewEscalatedAt = escalatedAt
 	return nil
 }
 
-func (s *publishingStore) GetDatasetProposalForRepository(orgNodeId string, status string, nodeId string) (*models.DatasetProposal, error) {
-	log.WithFields(log.Fields{"orgNodeId": orgNodeId, "status": status, "nodeId": nodeId}).Info("store.GetDatasetProposalForRepositoryWithStatus()")
+// GetDatasetProposalByNodeId gets a Dataset Proposal without knowing its owner, using the ProposalNodeIdIndex GSI.
+// Reads from the GSI are eventually consistent, so a Dataset Proposal may not be found immediately after it is created.
+func (s *publishingStore) GetDatasetProposalByNodeId(ctx context.Context, nodeId string) (*models.DatasetProposal, error) {
+	log.WithFields(log.Fields{"nodeId": nodeId}).Info("store.GetDatasetProposalByNodeId()")
+	ctx, cancel := config.WithTimeout(ctx, s.timeout)
+	defer cancel()
 
 	queryInput := dynamodb.QueryInput{
 		TableName:              aws.String(s.datasetProposalsTable),
-		IndexName:              aws.String("RepositoryProposalStatusIndex"),
-		KeyConditionExpression: aws.String("OrganizationNodeId = :orgNodeId AND ProposalStatus = :status"),
-		FilterExpression:       aws.String("NodeId = :nodeId"),
+		IndexName:              aws.String("ProposalNodeIdIndex"),
+		KeyConditionExpression: aws.String("NodeId = :nodeId"),
 		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":orgNodeId": &types.AttributeValueMemberS{
-				Value: orgNodeId,
+			":nodeId": &types.AttributeValueMemberS{
+				Value: nodeId,
 			},
+		},
+		Select: "ALL_PROJECTED_ATTRIBUTES",
+	}
+	return get[models.DatasetProposal](ctx, s.db, &queryInput)
+}
+
+func (s *publishingStore) SearchDatasetProposals(ctx context.Context, orgNodeId string, query string) ([]models.DatasetProposal, error) {
+	log.WithFields(log.Fields{"orgNodeId": orgNodeId, "query": query}).Info("store.SearchDatasetProposals()")
+	ctx, cancel := config.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	keys, err := s.search.Search(ctx, orgNodeId, query)
+	if err != nil {
+		log.WithFields(log.Fields{"failure": "search.Search()", "error": fmt.Sprintf("%+v", err)}).Error("store.SearchDatasetProposals()")
+		return nil, err
+	}
+
+	var proposals []models.DatasetProposal
+	for _, key := range keys {
+		proposal, err := s.GetDatasetProposal(ctx, key.UserId, key.NodeId)
+		if err != nil {
+			// the index may briefly refer to a Dataset Proposal that has since been deleted
+			log.WithFields(log.Fields{"key": fmt.Sprintf("%+v", key), "error": fmt.Sprintf("%+v", err)}).Warn("store.SearchDatasetProposals()")
+			continue
+		}
+		proposals = append(proposals, *proposal)
+	}
+
+	return proposals, nil
+}
+
+// GetNotificationPreference gets the user's notification preference; a user who has not set one is notified immediately
+func (s *publishingStore) GetNotificationPreference(ctx context.Context, userId int64) (*models.NotificationPreference, error) {
+	log.WithFields(log.Fields{"userId": userId}).Info("store.GetNotificationPreference()")
+	ctx, cancel := config.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	queryInput := dynamodb.QueryInput{
+		TableName:              aws.String(s.preferencesTable),
+		KeyConditionExpression: aws.String("UserId = :userId"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":userId": &types.AttributeValueMemberN{
+				Value: int64ToString(userId),
+			},
+		},
+	}
+	preferences, err := find[models.NotificationPreference](ctx, s.db, &queryInput)
+	if err != nil {
+		return nil, err
+	}
+	if len(preferences) == 0 {
+		return &models.NotificationPreference{UserId: userId, Frequency: models.NotifyImmediately}, nil
+	}
+
+	return &preferences[0], nil
+}
+
+func (s *publishingStore) PutNotificationPreference(ctx context.Context, preference *models.NotificationPreference) error {
+	log.WithFields(log.Fields{"preference": fmt.Sprintf("%+v", preference)}).Info("store.PutNotificationPreference()")
+	ctx, cancel := config.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	_, err := store(ctx, s.db, s.preferencesTable, preference)
+	return err
+}
+
+// RecordDigest records when the user was last sent a digest, leaving the rest of their stored preference as it is,
+// so that a change of frequency made while the digest was sent is kept. ErrConflict is returned if the user has no
+// stored preference.
+func (s *publishingStore) RecordDigest(ctx context.Context, userId int64, digestAt int64) error {
+	log.WithFields(log.Fields{"userId": userId, "digestAt": digestAt}).Info("store.RecordDigest()")
+	ctx, cancel := config.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	_, err := s.db.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(s.preferencesTable),
+		Key: map[string]types.AttributeValue{
+			"UserId": &types.AttributeValueMemberN{
+				Value: int64ToString(userId),
+			},
+		},
+		UpdateExpression:    aws.String("SET LastDigestAt = :digestAt"),
+		ConditionExpression: aws.String("attribute_exists(UserId)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":digestAt": &types.AttributeValueMemberN{
+				Value: int64ToString(digestAt),
+			},
+		},
+	})
+
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return ErrConflict
+	}
+	return err
+}
+
+// UpdateDatasetProposalWithOutbox updates the Dataset Proposal and creates the outbox messages that report
+// the change in a single transaction, so that either both are written or neither is. As for UpdateDatasetProposal,
+// the proposal is only written if the stored proposal, if any, is still at proposal.Version; otherwise ErrConflict is
+// returned. The version is incremented by the write.
+func (s *publishingStore) UpdateDatasetProposalWithOutbox(ctx context.Context, proposal *models.DatasetProposal, messages []models.OutboxMessage) (*models.DatasetProposal, error) {
+	log.WithFields(log.Fields{"nodeId": proposal.NodeId, "messages": len(messages)}).Info("store.UpdateDatasetProposalWithOutbox()")
+	ctx, cancel := config.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	expectedVersion := proposal.Version
+	proposal.Version++
+	proposalItem, err := attributevalue.MarshalMap(proposal)
+	if err != nil {
+		proposal.Version = expectedVersion
+		return nil, fmt.Errorf("marshalling dataset proposal: %w", err)
+	}
+	items := []types.TransactWriteItem{{
+		Put: &types.Put{
+			TableName:           aws.String(s.datasetProposalsTable),
+			Item:                proposalItem,
+			ConditionExpression: aws.String(versionCondition(expectedVersion)),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":version": &types.AttributeValueMemberN{
+					Value: int64ToString(expectedVersion),
+				},
+			},
+		},
+	}}
+	for i := range messages {
+		messageItem, err := attributevalue.MarshalMap(&messages[i])
+		if err != nil {
+			proposal.Version = expectedVersion
+			return nil, fmt.Errorf("marshalling outbox message: %w", err)
+		}
+		items = append(items, types.TransactWriteItem{
+			Put: &types.Put{
+				TableName:           aws.String(s.outboxTable),
+				Item:                messageItem,
+				ConditionExpression: aws.String("attribute_not_exists(Id)"),
+			},
+		})
+	}
+
+	_, err = s.db.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: items})
+	if err != nil {
+		proposal.Version = expectedVersion
+		// the proposal is the first item of the transaction
+		var canceled *types.TransactionCanceledException
+		if errors.As(err, &canceled) && len(canceled.CancellationReasons) > 0 && aws.ToString(canceled.CancellationReasons[0].Code) == "ConditionalCheckFailed" {
+			return nil, ErrConflict
+		}
+		log.WithFields(log.Fields{"failure": "TransactWriteItems()", "error": fmt.Sprintf("%+v", err)}).Error("store.UpdateDatasetProposalWithOutbox()")
+		return nil, err
+	}
+
+	// the proposal has been written; an index that is not updated is repaired by the search-backfill command
+	if err := s.search.Index(ctx, proposal); err != nil {
+		log.WithFields(log.Fields{"failure": "search.Index()", "nodeId": proposal.NodeId, "error": fmt.Sprintf("%+v", err)}).Error("store.UpdateDatasetProposalWithOutbox()")
+	}
+
+	return proposal, nil
+}
+
+// GetDueOutboxMessages gets the pending outbox messages whose next delivery attempt is due, using the OutboxStatusIndex GSI
+func (s *publishingStore) GetDueOutboxMessages(ctx context.Context, now int64) ([]models.OutboxMessage, error) {
+	log.WithFields(log.Fields{"now": now}).Info("store.GetDueOutboxMessages()")
+	ctx, cancel := config.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	queryInput := dynamodb.QueryInput{
+		TableName:              aws.String(s.outboxTable),
+		IndexName:              aws.String("OutboxStatusIndex"),
+		KeyConditionExpression: aws.String("#status = :status AND NextAttemptAt <= :now"),
+		ExpressionAttributeNames: map[string]string{
+			"#status": "Status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
 			":status": &types.AttributeValueMemberS{
-				Value: status,
+				Value: models.OutboxPending,
 			},
-			":nodeId": &types.AttributeValueMemberS{
-				Value: nodeId,
+			":now": &types.AttributeValueMemberN{
+				Value: int64ToString(now),
 			},
 		},
 		Select: "ALL_PROJECTED_ATTRIBUTES",
 	}
-	return get[models.DatasetProposal](s.db, &queryInput)
+	return find[models.OutboxMessage](ctx, s.db, &queryInput)
+}
+
+// PutOutboxMessage writes the outbox message, provided that the stored message is still at the expected version;
+// otherwise ErrConflict is returned
+func (s *publishingStore) PutOutboxMessage(ctx context.Context, message *models.OutboxMessage, expectedVersion int64) error {
+	log.WithFields(log.Fields{"id": message.Id, "status": message.Status, "version": message.Version}).Info("store.PutOutboxMessage()")
+	ctx, cancel := config.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	item, err := attributevalue.MarshalMap(message)
+	if err != nil {
+		return fmt.Errorf("marshalling outbox message: %w", err)
+	}
+
+	_, err = s.db.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(s.outboxTable),
+		Item:                item,
+		ConditionExpression: aws.String("Version = :version"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":version": &types.AttributeValueMemberN{
+				Value: int64ToString(expectedVersion),
+			},
+		},
+	})
+
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return ErrConflict
+	}
+	return err
+}
+
+func (s *publishingStore) GetWebhooks(ctx context.Context, orgNodeId string) ([]models.Webhook, error) {
+	log.WithFields(log.Fields{"orgNodeId": orgNodeId}).Info("store.GetWebhooks()")
+	ctx, cancel := config.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	queryInput := dynamodb.QueryInput{
+		TableName:              aws.String(s.webhooksTable),
+		KeyConditionExpression: aws.String("OrganizationNodeId = :orgNodeId"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":orgNodeId": &types.AttributeValueMemberS{
+				Value: orgNodeId,
+			},
+		},
+	}
+	return find[models.Webhook](ctx, s.db, &queryInput)
+}
+
+// GetWebhook gets one of the Repository's Webhooks, or returns ErrNotFound
+func (s *publishingStore) GetWebhook(ctx context.Context, orgNodeId string, id string) (*models.Webhook, error) {
+	log.WithFields(log.Fields{"orgNodeId": orgNodeId, "id": id}).Info("store.GetWebhook()")
+	ctx, cancel := config.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	queryInput := dynamodb.QueryInput{
+		TableName:              aws.String(s.webhooksTable),
+		KeyConditionExpression: aws.String("OrganizationNodeId = :orgNodeId AND Id = :id"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":orgNodeId": &types.AttributeValueMemberS{
+				Value: orgNodeId,
+			},
+			":id": &types.AttributeValueMemberS{
+				Value: id,
+			},
+		},
+	}
+	return get[models.Webhook](ctx, s.db, &queryInput)
+}
+
+func (s *publishingStore) PutWebhook(ctx context.Context, webhook *models.Webhook) error {
+	log.WithFields(log.Fields{"orgNodeId": webhook.OrganizationNodeId, "id": webhook.Id}).Info("store.PutWebhook()")
+	ctx, cancel := config.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	_, err := store(ctx, s.db, s.webhooksTable, webhook)
+	return err
+}
+
+func (s *publishingStore) DeleteWebhook(ctx context.Context, webhook *models.Webhook) error {
+	log.WithFields(log.Fields{"orgNodeId": webhook.OrganizationNodeId, "id": webhook.Id}).Info("store.DeleteWebhook()")
+	ctx, cancel := config.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	_, err := s.db.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(s.webhooksTable),
+		Key: map[string]types.AttributeValue{
+			"OrganizationNodeId": &types.AttributeValueMemberS{
+				Value: webhook.OrganizationNodeId,
+			},
+			"Id": &types.AttributeValueMemberS{
+				Value: webhook.Id,
+			},
+		},
+	})
+	return err
+}
+
+// GetWebhookDeliveries gets the most recent attempts to deliver to the Webhook, newest first
+func (s *publishingStore) GetWebhookDeliveries(ctx context.Context, webhookId string, limit int) ([]models.WebhookDelivery, error) {
+	log.WithFields(log.Fields{"webhookId": webhookId, "limit": limit}).Info("store.GetWebhookDeliveries()")
+	ctx, cancel := config.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	// a single page, rather than every page, of the newest attempts
+	output, err := s.db.Query(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(s.deliveriesTable),
+		KeyConditionExpression: aws.String("WebhookId = :webhookId"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":webhookId": &types.AttributeValueMemberS{
+				Value: webhookId,
+			},
+		},
+		ScanIndexForward: aws.Bool(false),
+		Limit:            aws.Int32(int32(limit)),
+	})
+	if err != nil {
+		log.WithFields(log.Fields{"failure": "Query()", "error": fmt.Sprintf("%+v", err)}).Error("store.GetWebhookDeliveries()")
+		return nil, err
+	}
+
+	return transform[models.WebhookDelivery](output.Items)
+}
+
+func (s *publishingStore) PutWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	log.WithFields(log.Fields{"webhookId": delivery.WebhookId, "eventId": delivery.EventId, "succeeded": delivery.Succeeded}).Info("store.PutWebhookDelivery()")
+	ctx, cancel := config.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	_, err := store(ctx, s.db, s.deliveriesTable, delivery)
+	return err
 }
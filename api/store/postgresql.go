@@ -8,9 +8,11 @@ import (
 	"github.com/pennsieve/pennsieve-go-core/pkg/models/role"
 	pgdbModels "github.com/pennsieve/pennsieve-go-core/pkg/models/pgdb"
 	pgdbQueries "github.com/pennsieve/pennsieve-go-core/pkg/queries/pgdb"
+	"github.com/pennsieve/publishing-service/api/config"
 	"github.com/pennsieve/publishing-service/api/models"
 	log "github.com/sirupsen/logrus"
 	"strings"
+	"time"
 )
 
 const SystemTeamTypePublishers = "publishers"
@@ -21,27 +23,30 @@ type PennsievePublishingStore interface {
 	GetPublishingTeam(ctx context.Context, workspaceId int64) (*models.PublishingTeam, error)
 	AddPublishingTeamToDataset(ctx context.Context, publishingTeam *models.PublishingTeam, dataset *pgdbModels.Dataset) error
 	GetPublishingTeamMembers(ctx context.Context, repository *models.Repository) ([]models.Publisher, error)
+	GetWorkspaceAdmins(ctx context.Context, repository *models.Repository) ([]models.WorkspaceAdmin, error)
 	CreateDatasetForAcceptedProposal(ctx context.Context, proposal *models.DatasetProposal) (*CreatedDataset, error)
 	GetWelcomeWorkspace(ctx context.Context) (*pgdbModels.Organization, error)
 }
 
-func NewPennsieveStore(db *sql.DB, orgId int64) *pennsieveStore {
-	dbTx, err := db.BeginTx(context.TODO(), nil)
+func NewPennsieveStore(ctx context.Context, db *sql.DB, orgId int64, timeout time.Duration) *pennsieveStore {
+	dbTx, err := db.BeginTx(ctx, nil)
 	if err != nil {
 		panic(err)
 	}
 
 	return &pennsieveStore{
-		orgId: orgId,
-		db:    db,
-		q:     pgdbQueries.New(dbTx),
+		orgId:   orgId,
+		db:      db,
+		q:       pgdbQueries.New(dbTx),
+		timeout: timeout,
 	}
 }
 
 type pennsieveStore struct {
-	orgId int64
-	db    *sql.DB
-	q     *pgdbQueries.Queries
+	orgId   int64
+	db      *sql.DB
+	q       *pgdbQueries.Queries
+	timeout time.Duration
 }
 
 type CreatedDataset struct {
@@ -50,9 +55,8 @@ type CreatedDataset struct {
 	Dataset      *pgdbModels.Dataset
 }
 
-func setOrgSearchPath(db *sql.DB, orgId int64) error {
+func setOrgSearchPath(ctx context.Context, db *sql.DB, orgId int64) error {
 	// Set Search Path to organization
-	ctx := context.Background()
 	_, err := db.ExecContext(ctx, fmt.Sprintf("SET search_path = \"%d\";", orgId))
 	if err != nil {
 		log.Error(fmt.Sprintf("Unable to set search_path to %d.", orgId))
@@ -71,7 +75,7 @@ func (p *pennsieveStore) ExecStoreTx(ctx context.Context, orgId int64, fn func(s
 
 	// if organization id was provided, then set search path
 	if orgId > 0 {
-		if err = setOrgSearchPath(p.db, orgId); err != nil {
+		if err = setOrgSearchPath(ctx, p.db, orgId); err != nil {
 			return err
 		}
 	}
@@ -98,7 +102,7 @@ func (p *pennsieveStore) ExecPennsieveStoreTx(ctx context.Context, orgId int64,
 
 	// if organization id was provided, then set search path
 	if orgId > 0 {
-		if err = setOrgSearchPath(p.db, orgId); err != nil {
+		if err = setOrgSearchPath(ctx, p.db, orgId); err != nil {
 			return err
 		}
 	}
@@ -120,18 +124,29 @@ func (p *pennsieveStore) ExecPennsieveStoreTx(ctx context.Context, orgId int64,
 }
 
 func (p *pennsieveStore) GetProposalUser(ctx context.Context, userId int64) (*pgdbModels.User, error) {
+	ctx, cancel := config.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
 	return p.q.GetUserById(ctx, userId)
 }
 
 func (p *pennsieveStore) GetRepositoryWorkspace(ctx context.Context, repository *models.Repository) (*pgdbModels.Organization, error) {
+	ctx, cancel := config.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
 	return p.q.GetOrganizationByNodeId(ctx, repository.OrganizationNodeId)
 }
 
 func (p *pennsieveStore) GetWelcomeWorkspace(ctx context.Context) (*pgdbModels.Organization, error) {
+	ctx, cancel := config.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
 	return p.q.GetOrganizationBySlug(ctx, "welcome_to_pennsieve")
 }
 
 func (p *pennsieveStore) GetPublishingTeam(ctx context.Context, workspaceId int64) (*models.PublishingTeam, error) {
+	ctx, cancel := config.WithTimeout(ctx, p.timeout)
+	defer cancel()
 
 	queryStr := `select o.id as org_id,
 		o.name as org_name,
@@ -169,6 +184,9 @@ func (p *pennsieveStore) GetPublishingTeam(ctx context.Context, workspaceId int6
 }
 
 func (p *pennsieveStore) AddPublishingTeamToDataset(ctx context.Context, publishingTeam *models.PublishingTeam, dataset *pgdbModels.Dataset) error {
+	ctx, cancel := config.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
 	statement := `INSERT INTO "%d".dataset_team
 					(dataset_id, team_id, permission_bit, role)
 					VALUES ($1, $2, $3, $4);`
@@ -188,6 +206,9 @@ func (p *pennsieveStore) AddPublishingTeamToDataset(ctx context.Context, publish
 }
 
 func (p *pennsieveStore) GetPublishingTeamMembers(ctx context.Context, repository *models.Repository) ([]models.Publisher, error) {
+	ctx, cancel := config.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
 	queryStr := "select " +
 		"  o.id as Workspace_Id, " +
 		"  o.name as Workspace_Name, " +
@@ -240,6 +261,9 @@ func (p *pennsieveStore) GetPublishingTeamMembers(ctx context.Context, repositor
 }
 
 func (p *pennsieveStore) CreateDatasetForAcceptedProposal(ctx context.Context, proposal *models.DatasetProposal) (*CreatedDataset, error) {
+	ctx, cancel := config.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
 	var err error
 
 	// Get the Pennsieve User
@@ -388,3 +412,48 @@ func (p *pennsieveStore) CreateDatasetForAcceptedProposal(ctx context.Context, p
 		Dataset:      ds,
 	}, nil
 }
+
+func (p *pennsieveStore) GetWorkspaceAdmins(ctx context.Context, repository *models.Repository) ([]models.WorkspaceAdmin, error) {
+	ctx, cancel := config.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	queryStr := "select " +
+		"  o.id as workspace_id, " +
+		"  o.name as workspace_name, " +
+		"  u.id as user_id, " +
+		"  u.first_name || ' ' || u.last_name as user_name, " +
+		"  u.email as user_email_address, " +
+		"  ou.permission_bit as user_workspace_permission_bit " +
+		"from pennsieve.organizations o " +
+		"join pennsieve.organization_user ou on o.id=ou.organization_id " +
+		"join pennsieve.users u on ou.user_id=u.id " +
+		"where o.node_id=$1 " +
+		"and ou.permission_bit>=$2;"
+
+	rows, err := p.db.QueryContext(ctx, queryStr, repository.OrganizationNodeId, pgdbModels.Administer)
+	if err != nil {
+		log.WithFields(log.Fields{"QueryContext": "failed", "error": fmt.Sprintf("%+v", err)}).Error("GetWorkspaceAdmins()")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var admins []models.WorkspaceAdmin
+	for rows.Next() {
+		var admin models.WorkspaceAdmin
+		err := rows.Scan(
+			&admin.WorkspaceId,
+			&admin.WorkspaceName,
+			&admin.UserId,
+			&admin.UserName,
+			&admin.UserEmailAddress,
+			&admin.PermissionBit,
+		)
+		if err != nil {
+			log.WithFields(log.Fields{"status": "error", "error": fmt.Sprintf("%+v", err)}).Error("rows.Scan()")
+		} else {
+			admins = append(admins, admin)
+		}
+	}
+
+	return admins, rows.Err()
+}
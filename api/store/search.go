@@ -0,0 +1,230 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/pennsieve/publishing-service/api/models"
+	log "github.com/sirupsen/logrus"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// ProposalSearchIndex maintains a free-text index over Dataset Proposals, partitioned by Repository.
+// A search matches a Dataset Proposal when every term in the query appears in its searchable text.
+type ProposalSearchIndex interface {
+	Index(ctx context.Context, proposal *models.DatasetProposal) error
+	Remove(ctx context.Context, proposal *models.DatasetProposal) error
+	Search(ctx context.Context, orgNodeId string, query string) ([]models.DatasetProposalKey, error)
+}
+
+// searchText builds the normalized text that is indexed for a Dataset Proposal: the name, description,
+// owner name, owner email address, and the names of the contributors.
+func searchText(proposal *models.DatasetProposal) string {
+	fields := []string{
+		proposal.Name,
+		proposal.Description,
+		proposal.OwnerName,
+		proposal.EmailAddress,
+	}
+	for _, contributor := range proposal.Contributors {
+		fields = append(fields, contributor.FirstName, contributor.LastName)
+	}
+	return strings.Join(searchTerms(strings.Join(fields, " ")), " ")
+}
+
+// searchTerms lowercases the text and splits it into terms. Characters commonly found in
+// email addresses are kept so that an address can be searched for as a single term.
+func searchTerms(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && !strings.ContainsRune("@.-_+", r)
+	})
+}
+
+func matchesAllTerms(text string, terms []string) bool {
+	for _, term := range terms {
+		if !strings.Contains(text, term) {
+			return false
+		}
+	}
+	return true
+}
+
+// ProposalSearchEntry is an item in the proposal search index table
+type ProposalSearchEntry struct {
+	OrganizationNodeId string `dynamodbav:"OrganizationNodeId"`
+	NodeId             string `dynamodbav:"NodeId"`
+	UserId             int    `dynamodbav:"UserId"`
+	SearchText         string `dynamodbav:"SearchText"`
+}
+
+// BackfillSearchIndex indexes every Dataset Proposal in the table, e.g. those created before the index existed,
+// or whose index entry was not updated when they were written. A proposal that fails to be indexed is logged and
+// counted, and does not stop the backfill.
+func BackfillSearchIndex(ctx context.Context, db DynamoDBAPI, proposalsTable string, search ProposalSearchIndex) (indexed int, failed int, err error) {
+	log.WithFields(log.Fields{"proposalsTable": proposalsTable}).Info("store.BackfillSearchIndex()")
+
+	proposals, err := fetch[models.DatasetProposal](ctx, db, proposalsTable)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for i := range proposals {
+		if err := search.Index(ctx, &proposals[i]); err != nil {
+			log.WithFields(log.Fields{"nodeId": proposals[i].NodeId, "error": fmt.Sprintf("%+v", err)}).Error("store.BackfillSearchIndex()")
+			failed++
+			continue
+		}
+		indexed++
+	}
+
+	return indexed, failed, nil
+}
+
+func NewDynamoDBSearchIndex(db DynamoDBAPI, table string) *dynamoDBSearchIndex {
+	return &dynamoDBSearchIndex{
+		db:    db,
+		table: table,
+	}
+}
+
+// dynamoDBSearchIndex stores the searchable text for each Dataset Proposal in a table keyed
+// by (OrganizationNodeId, NodeId), and searches by querying the Repository's partition.
+type dynamoDBSearchIndex struct {
+	db    DynamoDBAPI
+	table string
+}
+
+func (i *dynamoDBSearchIndex) Index(ctx context.Context, proposal *models.DatasetProposal) error {
+	log.WithFields(log.Fields{"nodeId": proposal.NodeId}).Debug("dynamoDBSearchIndex.Index()")
+
+	data, err := attributevalue.MarshalMap(ProposalSearchEntry{
+		OrganizationNodeId: proposal.OrganizationNodeId,
+		NodeId:             proposal.NodeId,
+		UserId:             proposal.UserId,
+		SearchText:         searchText(proposal),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = i.db.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(i.table),
+		Item:      data,
+	})
+	return err
+}
+
+func (i *dynamoDBSearchIndex) Remove(ctx context.Context, proposal *models.DatasetProposal) error {
+	log.WithFields(log.Fields{"nodeId": proposal.NodeId}).Debug("dynamoDBSearchIndex.Remove()")
+
+	_, err := i.db.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(i.table),
+		Key: map[string]types.AttributeValue{
+			"OrganizationNodeId": &types.AttributeValueMemberS{Value: proposal.OrganizationNodeId},
+			"NodeId":             &types.AttributeValueMemberS{Value: proposal.NodeId},
+		},
+	})
+	return err
+}
+
+func (i *dynamoDBSearchIndex) Search(ctx context.Context, orgNodeId string, query string) ([]models.DatasetProposalKey, error) {
+	log.WithFields(log.Fields{"orgNodeId": orgNodeId, "query": query}).Debug("dynamoDBSearchIndex.Search()")
+
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	values := map[string]types.AttributeValue{
+		":orgNodeId": &types.AttributeValueMemberS{Value: orgNodeId},
+	}
+	var conditions []string
+	for n, term := range terms {
+		placeholder := fmt.Sprintf(":term%d", n)
+		values[placeholder] = &types.AttributeValueMemberS{Value: term}
+		conditions = append(conditions, fmt.Sprintf("contains(SearchText, %s)", placeholder))
+	}
+
+	paginator := dynamodb.NewQueryPaginator(i.db, &dynamodb.QueryInput{
+		TableName:                 aws.String(i.table),
+		KeyConditionExpression:    aws.String("OrganizationNodeId = :orgNodeId"),
+		FilterExpression:          aws.String(strings.Join(conditions, " AND ")),
+		ExpressionAttributeValues: values,
+	})
+
+	var keys []models.DatasetProposalKey
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range page.Items {
+			var entry ProposalSearchEntry
+			if err := attributevalue.UnmarshalMap(item, &entry); err != nil {
+				return nil, err
+			}
+			keys = append(keys, models.DatasetProposalKey{UserId: entry.UserId, NodeId: entry.NodeId})
+		}
+	}
+
+	return keys, nil
+}
+
+func NewLocalSearchIndex() *localSearchIndex {
+	return &localSearchIndex{
+		entries: make(map[string]ProposalSearchEntry),
+	}
+}
+
+// localSearchIndex is an in-process ProposalSearchIndex, intended for tests and local development
+type localSearchIndex struct {
+	mu      sync.RWMutex
+	entries map[string]ProposalSearchEntry
+}
+
+func (i *localSearchIndex) Index(ctx context.Context, proposal *models.DatasetProposal) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.entries[proposal.NodeId] = ProposalSearchEntry{
+		OrganizationNodeId: proposal.OrganizationNodeId,
+		NodeId:             proposal.NodeId,
+		UserId:             proposal.UserId,
+		SearchText:         searchText(proposal),
+	}
+	return nil
+}
+
+func (i *localSearchIndex) Remove(ctx context.Context, proposal *models.DatasetProposal) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	delete(i.entries, proposal.NodeId)
+	return nil
+}
+
+func (i *localSearchIndex) Search(ctx context.Context, orgNodeId string, query string) ([]models.DatasetProposalKey, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	var keys []models.DatasetProposalKey
+	for _, entry := range i.entries {
+		if entry.OrganizationNodeId == orgNodeId && matchesAllTerms(entry.SearchText, terms) {
+			keys = append(keys, models.DatasetProposalKey{UserId: entry.UserId, NodeId: entry.NodeId})
+		}
+	}
+	sort.Slice(keys, func(a, b int) bool { return keys[a].NodeId < keys[b].NodeId })
+
+	return keys, nil
+}
@@ -0,0 +1,111 @@
+// Package webhook delivers Dataset Proposal lifecycle events to the HTTPS endpoints registered by Repositories,
+// so that their own systems (ticketing, chat bridges) can react to proposals. Events are written to the outbox
+// with the proposal change they report, and delivered, signed, by a Notifier.
+package webhook
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/pennsieve/publishing-service/api/models"
+	"github.com/pennsieve/publishing-service/api/notification"
+	"net"
+	"net/url"
+	"slices"
+	"time"
+)
+
+// Event types, one for each lifecycle transition of a Dataset Proposal submitted to a Repository
+const (
+	EventSubmitted = "proposal.submitted"
+	EventWithdrawn = "proposal.withdrawn"
+	EventAccepted  = "proposal.accepted"
+	EventRejected  = "proposal.rejected"
+)
+
+// EventTypes are the event types a Webhook may subscribe to
+var EventTypes = []string{EventSubmitted, EventWithdrawn, EventAccepted, EventRejected}
+
+var eventTypes = map[notification.Notification]string{
+	notification.Submitted: EventSubmitted,
+	notification.Withdrawn: EventWithdrawn,
+	notification.Accepted:  EventAccepted,
+	notification.Rejected:  EventRejected,
+}
+
+// EventType returns the event type reporting the notification, if there is one
+func EventType(n notification.Notification) (string, bool) {
+	eventType, found := eventTypes[n]
+	return eventType, found
+}
+
+// Event is the JSON payload POSTed to a Webhook. Its Id is the same on every attempt to deliver it,
+// so that a receiver can ignore a repeated delivery.
+type Event struct {
+	Id         string          `json:"id"`
+	Type       string          `json:"type"`
+	OccurredAt time.Time       `json:"occurredAt"`
+	Repository EventRepository `json:"repository"`
+	Proposal   EventProposal   `json:"proposal"`
+}
+
+type EventRepository struct {
+	NodeId string `json:"nodeId"`
+	Name   string `json:"name"`
+}
+
+type EventProposal struct {
+	NodeId        string `json:"nodeId"`
+	Name          string `json:"name"`
+	Status        string `json:"status"`
+	OwnerName     string `json:"ownerName"`
+	EmailAddress  string `json:"emailAddress"`
+	DatasetNodeId string `json:"datasetNodeId,omitempty"`
+}
+
+// NewEvent creates the event of the given type for the proposal, as it is after the transition
+func NewEvent(eventType string, proposal *models.DatasetProposal, repository *models.Repository, now time.Time) Event {
+	return Event{
+		Id:         uuid.NewString(),
+		Type:       eventType,
+		OccurredAt: now.UTC(),
+		Repository: EventRepository{
+			NodeId: repository.OrganizationNodeId,
+			Name:   repository.DisplayName,
+		},
+		Proposal: EventProposal{
+			NodeId:        proposal.NodeId,
+			Name:          proposal.Name,
+			Status:        proposal.ProposalStatus,
+			OwnerName:     proposal.OwnerName,
+			EmailAddress:  proposal.EmailAddress,
+			DatasetNodeId: proposal.DatasetNodeId,
+		},
+	}
+}
+
+// Subscribes reports whether the Webhook is to be sent events of the given type
+func Subscribes(webhook *models.Webhook, eventType string) bool {
+	return webhook.Active && (len(webhook.Events) == 0 || slices.Contains(webhook.Events, eventType))
+}
+
+// ValidateURL checks that the URL of a Webhook is an absolute HTTPS URL. Plain HTTP, and hosts on
+// private or loopback addresses, are only allowed when private addresses are (i.e. when running locally).
+func ValidateURL(rawURL string, allowPrivateAddresses bool) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" || u.User != nil {
+		return fmt.Errorf("must be an absolute URL without credentials")
+	}
+	if u.Scheme != "https" && !(allowPrivateAddresses && u.Scheme == "http") {
+		return fmt.Errorf("must be an https URL")
+	}
+	if ip := net.ParseIP(u.Hostname()); ip != nil && !allowPrivateAddresses && !isPublic(ip) {
+		return fmt.Errorf("must not be a private address")
+	}
+	return nil
+}
+
+// isPublic reports whether the address is routable on the internet, rather than within the VPC or host
+func isPublic(ip net.IP) bool {
+	return !(ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified())
+}
@@ -0,0 +1,167 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/pennsieve/publishing-service/api/config"
+	"github.com/pennsieve/publishing-service/api/models"
+	"github.com/pennsieve/publishing-service/api/store"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// ErrInactive is returned for a delivery to a Webhook that has been removed, deactivated, or unsubscribed
+// from the event since the event was written; the delivery is skipped rather than retried
+var ErrInactive = errors.New("webhook is no longer active")
+
+// Delivery is an event to be delivered to one of a Repository's Webhooks; Attempt counts from 1
+type Delivery struct {
+	OrganizationNodeId string
+	WebhookId          string
+	Payload            []byte
+	Attempt            int
+}
+
+// Notifier delivers Dataset Proposal lifecycle events to the Webhooks registered by Repositories,
+// as a notification.Notifier delivers them by email
+type Notifier interface {
+	Deliver(ctx context.Context, delivery Delivery) error
+}
+
+// maxResponseBody is how much of a response is read, so that the connection may be reused
+const maxResponseBody = 64 * 1024
+
+// NewHTTPNotifier creates a Notifier that POSTs each event, signed with the Webhook's secret, to its URL,
+// and records each attempt in the Webhook's delivery log
+func NewHTTPNotifier(store store.PublishingStore, policy config.Webhooks) *HTTPNotifier {
+	dialer := &net.Dialer{Timeout: policy.Timeout}
+	if !policy.AllowPrivateAddresses {
+		// checked on the resolved address of every connection, so that a public host name
+		// cannot be pointed at an address inside the VPC
+		dialer.Control = refusePrivateAddresses
+	}
+
+	return &HTTPNotifier{
+		store:  store,
+		policy: policy,
+		client: &http.Client{
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: policy.Timeout,
+				MaxIdleConns:        10,
+				IdleConnTimeout:     90 * time.Second,
+			},
+			// a redirect is treated as a failed delivery, rather than followed to where it leads
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+type HTTPNotifier struct {
+	store  store.PublishingStore
+	policy config.Webhooks
+	client *http.Client
+}
+
+func refusePrivateAddresses(network string, address string, conn syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !isPublic(ip) {
+		return fmt.Errorf("refusing to connect to private address %s", host)
+	}
+	return nil
+}
+
+// Deliver POSTs the event to the Webhook. An error is returned unless the endpoint responds with a 2xx status.
+func (n *HTTPNotifier) Deliver(ctx context.Context, delivery Delivery) error {
+	log.WithFields(log.Fields{"orgNodeId": delivery.OrganizationNodeId, "webhookId": delivery.WebhookId, "attempt": delivery.Attempt}).Info("HTTPNotifier.Deliver()")
+
+	var event Event
+	if err := json.Unmarshal(delivery.Payload, &event); err != nil {
+		return fmt.Errorf("invalid event payload: %w", err)
+	}
+
+	webhook, err := n.store.GetWebhook(ctx, delivery.OrganizationNodeId, delivery.WebhookId)
+	if errors.Is(err, store.ErrNotFound) {
+		return ErrInactive
+	}
+	if err != nil {
+		return fmt.Errorf("getting webhook: %w", err)
+	}
+	if !Subscribes(webhook, event.Type) {
+		return ErrInactive
+	}
+
+	start := time.Now()
+	statusCode, err := n.post(ctx, webhook, &event, delivery.Payload, start)
+	n.record(ctx, &models.WebhookDelivery{
+		WebhookId:      webhook.Id,
+		Id:             fmt.Sprintf("%019d-%s", start.UnixNano(), uuid.NewString()),
+		EventId:        event.Id,
+		EventType:      event.Type,
+		ProposalNodeId: event.Proposal.NodeId,
+		Attempt:        delivery.Attempt,
+		StatusCode:     statusCode,
+		Error:          errorString(err),
+		Succeeded:      err == nil,
+		DurationMillis: time.Since(start).Milliseconds(),
+		AttemptedAt:    start.Unix(),
+		ExpiresAt:      start.Add(n.policy.DeliveryRetention).Unix(),
+	})
+
+	return err
+}
+
+// post sends the signed payload, and returns the status code of the response, if there was one
+func (n *HTTPNotifier) post(ctx context.Context, webhook *models.Webhook, event *Event, payload []byte, now time.Time) (int, error) {
+	ctx, cancel := config.WithTimeout(ctx, n.policy.Timeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, fmt.Errorf("creating request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "Pennsieve-Publishing-Service-Webhook/1.0")
+	request.Header.Set(EventHeader, event.Type)
+	request.Header.Set(DeliveryHeader, event.Id)
+	request.Header.Set(SignatureHeader, Sign(webhook.Secret, now, payload))
+
+	response, err := n.client.Do(request)
+	if err != nil {
+		return 0, fmt.Errorf("posting event: %w", err)
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, maxResponseBody))
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return response.StatusCode, fmt.Errorf("endpoint responded %s", response.Status)
+	}
+	return response.StatusCode, nil
+}
+
+// record adds the attempt to the delivery log; failing to record it does not fail the delivery
+func (n *HTTPNotifier) record(ctx context.Context, delivery *models.WebhookDelivery) {
+	if err := n.store.PutWebhookDelivery(ctx, delivery); err != nil {
+		log.WithFields(log.Fields{"webhookId": delivery.WebhookId, "eventId": delivery.EventId, "error": fmt.Sprintf("%+v", err)}).Error("HTTPNotifier.record()")
+	}
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
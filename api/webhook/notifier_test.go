@@ -0,0 +1,225 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/pennsieve/publishing-service/api/config"
+	"github.com/pennsieve/publishing-service/api/inmemory"
+	"github.com/pennsieve/publishing-service/api/models"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	testOrgNodeId  = "N:organization:repository"
+	testWebhookId  = "webhook-1"
+	testSecret     = "whsec_test"
+	testProposalId = "N:proposal:1"
+)
+
+// receiver is a local stand-in for a Repository's webhook endpoint
+type receiver struct {
+	mu       sync.Mutex
+	status   int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, body)
+	w.WriteHeader(r.status)
+}
+
+func (r *receiver) received() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.requests)
+}
+
+// newTestNotifier starts a receiver responding with the status, and registers it as a Webhook subscribed to the events
+func newTestNotifier(t *testing.T, status int, allowPrivate bool, events ...string) (*HTTPNotifier, *inmemory.PublishingStore, *receiver) {
+	t.Helper()
+
+	recv := &receiver{status: status}
+	server := httptest.NewServer(recv)
+	t.Cleanup(server.Close)
+
+	pubStore := inmemory.NewPublishingStore()
+	err := pubStore.PutWebhook(context.Background(), &models.Webhook{
+		OrganizationNodeId: testOrgNodeId,
+		Id:                 testWebhookId,
+		URL:                server.URL,
+		Events:             events,
+		Secret:             testSecret,
+		Active:             true,
+	})
+	if err != nil {
+		t.Fatalf("registering webhook: %v", err)
+	}
+
+	policy := config.DefaultWebhooks()
+	policy.Timeout = 5 * time.Second
+	policy.AllowPrivateAddresses = allowPrivate
+	return NewHTTPNotifier(pubStore, policy), pubStore, recv
+}
+
+func testDelivery(t *testing.T, eventType string) Delivery {
+	t.Helper()
+
+	proposal := &models.DatasetProposal{
+		NodeId:             testProposalId,
+		Name:               "Proposal",
+		OrganizationNodeId: testOrgNodeId,
+		ProposalStatus:     "SUBMITTED",
+	}
+	repository := &models.Repository{OrganizationNodeId: testOrgNodeId, Name: "repository"}
+	payload, err := json.Marshal(NewEvent(eventType, proposal, repository, time.Now()))
+	if err != nil {
+		t.Fatalf("marshalling event: %v", err)
+	}
+	return Delivery{
+		OrganizationNodeId: testOrgNodeId,
+		WebhookId:          testWebhookId,
+		Payload:            payload,
+		Attempt:            1,
+	}
+}
+
+func getDeliveries(t *testing.T, pubStore *inmemory.PublishingStore) []models.WebhookDelivery {
+	t.Helper()
+	deliveries, err := pubStore.GetWebhookDeliveries(context.Background(), testWebhookId, 10)
+	if err != nil {
+		t.Fatalf("getting deliveries: %v", err)
+	}
+	return deliveries
+}
+
+func TestHTTPNotifierDeliversSignedEvent(t *testing.T) {
+	notifier, pubStore, recv := newTestNotifier(t, http.StatusNoContent, true, EventSubmitted)
+	delivery := testDelivery(t, EventSubmitted)
+
+	if err := notifier.Deliver(context.Background(), delivery); err != nil {
+		t.Fatalf("Deliver() error = %v", err)
+	}
+
+	if recv.received() != 1 {
+		t.Fatalf("received %d requests, want 1", recv.received())
+	}
+	request, body := recv.requests[0], recv.bodies[0]
+	if string(body) != string(delivery.Payload) {
+		t.Errorf("body = %s, want %s", body, delivery.Payload)
+	}
+	if got := request.Header.Get(EventHeader); got != EventSubmitted {
+		t.Errorf("%s = %q, want %q", EventHeader, got, EventSubmitted)
+	}
+	if request.Header.Get(DeliveryHeader) == "" {
+		t.Errorf("%s is not set", DeliveryHeader)
+	}
+	if err := Verify(testSecret, request.Header.Get(SignatureHeader), body, time.Minute, time.Now()); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+	if err := Verify("whsec_other", request.Header.Get(SignatureHeader), body, time.Minute, time.Now()); err == nil {
+		t.Error("Verify() with another secret succeeded")
+	}
+
+	deliveries := getDeliveries(t, pubStore)
+	if len(deliveries) != 1 {
+		t.Fatalf("recorded %d deliveries, want 1", len(deliveries))
+	}
+	if d := deliveries[0]; !d.Succeeded || d.StatusCode != http.StatusNoContent || d.EventType != EventSubmitted || d.ProposalNodeId != testProposalId {
+		t.Errorf("recorded delivery = %+v", d)
+	}
+}
+
+func TestHTTPNotifierReportsFailedResponse(t *testing.T) {
+	notifier, pubStore, _ := newTestNotifier(t, http.StatusInternalServerError, true, EventSubmitted)
+
+	if err := notifier.Deliver(context.Background(), testDelivery(t, EventSubmitted)); err == nil {
+		t.Fatal("Deliver() succeeded for a 500 response")
+	}
+
+	deliveries := getDeliveries(t, pubStore)
+	if len(deliveries) != 1 {
+		t.Fatalf("recorded %d deliveries, want 1", len(deliveries))
+	}
+	if d := deliveries[0]; d.Succeeded || d.StatusCode != http.StatusInternalServerError || d.Error == "" {
+		t.Errorf("recorded delivery = %+v", d)
+	}
+}
+
+func TestHTTPNotifierSkipsInactiveWebhooks(t *testing.T) {
+	notifier, pubStore, recv := newTestNotifier(t, http.StatusOK, true, EventAccepted)
+
+	if err := notifier.Deliver(context.Background(), testDelivery(t, EventSubmitted)); !errors.Is(err, ErrInactive) {
+		t.Errorf("Deliver() of an unsubscribed event error = %v, want ErrInactive", err)
+	}
+
+	webhook, err := pubStore.GetWebhook(context.Background(), testOrgNodeId, testWebhookId)
+	if err != nil {
+		t.Fatalf("getting webhook: %v", err)
+	}
+	webhook.Active = false
+	if err := pubStore.PutWebhook(context.Background(), webhook); err != nil {
+		t.Fatalf("deactivating webhook: %v", err)
+	}
+	if err := notifier.Deliver(context.Background(), testDelivery(t, EventAccepted)); !errors.Is(err, ErrInactive) {
+		t.Errorf("Deliver() to an inactive webhook error = %v, want ErrInactive", err)
+	}
+
+	if err := pubStore.DeleteWebhook(context.Background(), webhook); err != nil {
+		t.Fatalf("deleting webhook: %v", err)
+	}
+	if err := notifier.Deliver(context.Background(), testDelivery(t, EventAccepted)); !errors.Is(err, ErrInactive) {
+		t.Errorf("Deliver() to a deleted webhook error = %v, want ErrInactive", err)
+	}
+
+	if recv.received() != 0 {
+		t.Errorf("received %d requests, want 0", recv.received())
+	}
+}
+
+func TestHTTPNotifierRefusesPrivateAddresses(t *testing.T) {
+	notifier, pubStore, recv := newTestNotifier(t, http.StatusOK, false, EventSubmitted)
+
+	if err := notifier.Deliver(context.Background(), testDelivery(t, EventSubmitted)); err == nil {
+		t.Fatal("Deliver() to a loopback address succeeded")
+	}
+	if recv.received() != 0 {
+		t.Errorf("received %d requests, want 0", recv.received())
+	}
+	if deliveries := getDeliveries(t, pubStore); len(deliveries) != 1 || deliveries[0].Succeeded {
+		t.Errorf("recorded deliveries = %+v", deliveries)
+	}
+}
+
+func TestValidateURL(t *testing.T) {
+	tests := []struct {
+		url          string
+		allowPrivate bool
+		valid        bool
+	}{
+		{"https://hooks.example.com/pennsieve", false, true},
+		{"http://hooks.example.com/pennsieve", false, false},
+		{"https://127.0.0.1/hook", false, false},
+		{"https://10.0.0.5/hook", false, false},
+		{"https://169.254.169.254/latest", false, false},
+		{"http://127.0.0.1:8080/hook", true, true},
+		{"ftp://hooks.example.com", true, false},
+		{"not a url", false, false},
+	}
+	for _, test := range tests {
+		err := ValidateURL(test.url, test.allowPrivate)
+		if (err == nil) != test.valid {
+			t.Errorf("ValidateURL(%q, %t) error = %v, want valid = %t", test.url, test.allowPrivate, err, test.valid)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers sent with each event
+const (
+	SignatureHeader = "X-Pennsieve-Signature"
+	EventHeader     = "X-Pennsieve-Event"
+	DeliveryHeader  = "X-Pennsieve-Delivery"
+)
+
+// NewSecret creates a random secret with which a Webhook's payloads are signed
+func NewSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(secret), nil
+}
+
+// Sign returns the value of the SignatureHeader for the payload sent at the given time:
+// "t=<unix seconds>,v1=<hex HMAC-SHA256 of "<unix seconds>.<payload>" keyed by the secret>".
+// Signing the timestamp with the payload lets a receiver reject replayed deliveries.
+func Sign(secret string, timestamp time.Time, payload []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", t, signature(secret, t, payload))
+}
+
+// Verify checks a SignatureHeader value against the payload, as a receiver would. Signatures
+// older (or newer) than the tolerance are rejected; a zero tolerance does not check the time.
+func Verify(secret string, header string, payload []byte, tolerance time.Duration, now time.Time) error {
+	var t, v1 string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "t":
+			t = value
+		case "v1":
+			v1 = value
+		}
+	}
+
+	seconds, err := strconv.ParseInt(t, 10, 64)
+	if err != nil || v1 == "" {
+		return fmt.Errorf("malformed signature header")
+	}
+	if !hmac.Equal([]byte(v1), []byte(signature(secret, t, payload))) {
+		return fmt.Errorf("signature does not match")
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); tolerance > 0 && (age > tolerance || age < -tolerance) {
+		return fmt.Errorf("signature timestamp is outside the tolerance")
+	}
+	return nil
+}
+
+func signature(secret string, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
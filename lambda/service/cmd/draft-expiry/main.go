@@ -0,0 +1,25 @@
+// Command draft-expiry is the scheduled Lambda that reminds authors of abandoned draft proposals,
+// and archives or deletes the drafts once the grace period after the reminder has passed.
+package main
+
+import (
+	"context"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/pennsieve/publishing-service/api/config"
+	"github.com/pennsieve/publishing-service/service/handler"
+	log "github.com/sirupsen/logrus"
+)
+
+func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("invalid publishing-service configuration: %v", err)
+	}
+
+	clients, err := config.NewClients(context.Background(), cfg)
+	if err != nil {
+		log.Fatalf("unable to create AWS clients: %v", err)
+	}
+
+	lambda.Start(handler.DraftExpiryHandler(handler.NewDraftExpiry(cfg, clients)))
+}
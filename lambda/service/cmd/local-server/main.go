@@ -0,0 +1,53 @@
+// Command local-server runs the Publishing Service API as a standalone HTTP server for local development.
+//
+// Requests are translated into API Gateway v2 events, with fake authorizer claims, and handled by the
+// same handler as the Lambda function. By default the service is backed by in-memory stores seeded with
+// a repository, a user and a workspace; with -backend=aws it uses the regular configuration, which can
+// point at DynamoDB Local (DYNAMODB_URL) and a local Postgres (ENV=DOCKER, POSTGRES_HOST, ...).
+//
+//	go run ./cmd/local-server -addr :8080 -user-id 1 -org-id 1 -publisher
+//
+// The claims can be overridden per request with the X-Fake-User-Id, X-Fake-Org-Id, X-Fake-Org-Node-Id
+// and X-Fake-Publisher headers.
+package main
+
+import (
+	"context"
+	"flag"
+	"github.com/pennsieve/publishing-service/api/config"
+	"github.com/pennsieve/publishing-service/service/handler"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	backend := flag.String("backend", "memory", "backing stores: memory or aws")
+	claims := FakeClaims{}
+	flag.Int64Var(&claims.UserId, "user-id", 1, "fake authorizer claim: user id")
+	flag.StringVar(&claims.UserNodeId, "user-node-id", "N:user:local", "fake authorizer claim: user node id")
+	flag.Int64Var(&claims.OrgId, "org-id", 1, "fake authorizer claim: workspace id")
+	flag.StringVar(&claims.OrgNodeId, "org-node-id", "N:organization:local", "fake authorizer claim: workspace node id")
+	flag.BoolVar(&claims.Publisher, "publisher", false, "fake authorizer claim: user is on the workspace publishers team")
+	flag.Parse()
+
+	switch *backend {
+	case "memory":
+		handler.ConfigureServiceProvider(NewMemoryServiceProvider(claims))
+	case "aws":
+		cfg, err := config.Load()
+		if err != nil {
+			log.Fatalf("invalid publishing-service configuration: %v", err)
+		}
+		clients, err := config.NewClients(context.Background(), cfg)
+		if err != nil {
+			log.Fatalf("unable to create AWS clients: %v", err)
+		}
+		handler.Configure(cfg, clients)
+	default:
+		log.Fatalf("unknown backend: %s (must be memory or aws)", *backend)
+	}
+
+	log.WithFields(log.Fields{"addr": *addr, "backend": *backend, "claims": claims}).Info("local publishing-service listening")
+	log.Fatal(http.ListenAndServe(*addr, NewServer(claims)))
+}
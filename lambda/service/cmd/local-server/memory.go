@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/pennsieve/pennsieve-go-core/pkg/authorizer"
+	pgdbModels "github.com/pennsieve/pennsieve-go-core/pkg/models/pgdb"
+	"github.com/pennsieve/publishing-service/api/config"
+	"github.com/pennsieve/publishing-service/api/inmemory"
+	"github.com/pennsieve/publishing-service/api/models"
+	"github.com/pennsieve/publishing-service/api/service"
+	"github.com/pennsieve/publishing-service/api/webhook"
+	"github.com/pennsieve/publishing-service/service/handler"
+	log "github.com/sirupsen/logrus"
+	"sync"
+)
+
+const welcomeWorkspaceId int64 = 1000
+
+// NewMemoryServiceProvider creates a handler.ServiceProvider backed by in-memory stores that persist for
+// the lifetime of the process. The stores are seeded with a repository in the workspace of the claims, and
+// with the user of the claims as a member of that workspace's publishing team.
+func NewMemoryServiceProvider(claims FakeClaims) handler.ServiceProvider {
+	pubStore := inmemory.NewPublishingStore()
+	seedPublishingStore(pubStore, claims)
+
+	notifier := inmemory.NewNotifier()
+	publisher := inmemory.NewEventPublisher()
+
+	// webhooks are POSTed for real, so that they can be received by an endpoint on this machine
+	webhookPolicy := config.DefaultWebhooks()
+	webhookPolicy.AllowPrivateAddresses = true
+	webhooks := webhook.NewHTTPNotifier(pubStore, webhookPolicy)
+
+	var mu sync.Mutex
+	pennsieveStores := make(map[int64]*inmemory.PennsieveStore)
+	pennsieveStore := func(orgId int64) *inmemory.PennsieveStore {
+		mu.Lock()
+		defer mu.Unlock()
+		if _, ok := pennsieveStores[orgId]; !ok {
+			pennsieveStores[orgId] = inmemory.NewPennsieveStore(orgId)
+			seedPennsieveStore(pennsieveStores[orgId], claims)
+		}
+		return pennsieveStores[orgId]
+	}
+
+	return func(ctx context.Context, requestClaims *authorizer.Claims) (service.PublishingService, func(), error) {
+		if requestClaims == nil {
+			return service.NewPublishingService(pubStore, nil, nil), func() {}, nil
+		}
+
+		pennsieve := pennsieveStore(requestClaims.OrgClaim.IntId)
+		// log the notifications sent, and the events published, while handling the request in place of delivering them
+		release := func() {
+			for _, sent := range notifier.Sent() {
+				log.WithFields(log.Fields{
+					"notification": sent.Notification,
+					"recipients":   sent.Recipients,
+					"attributes":   sent.MessageAttributes,
+				}).Info("local-server notification")
+			}
+			notifier.Reset()
+			for _, event := range publisher.Published() {
+				log.WithFields(log.Fields{
+					"id":       event.Id,
+					"type":     event.Type,
+					"proposal": event.Proposal,
+				}).Info("local-server event")
+			}
+			publisher.Reset()
+		}
+		return service.NewPublishingService(pubStore, pennsieve, notifier, service.WithWebhooks(webhooks, webhookPolicy), service.WithEvents(publisher)), release, nil
+	}
+}
+
+func seedPublishingStore(pubStore *inmemory.PublishingStore, claims FakeClaims) {
+	pubStore.AddQuestion(models.Question{Id: 1, Question: "What is the title of the dataset?", Type: "string"})
+	pubStore.AddQuestion(models.Question{Id: 2, Question: "Who funded the research?", Type: "string"})
+	pubStore.AddRepository(models.Repository{
+		OrganizationNodeId: claims.OrgNodeId,
+		Name:               "local",
+		DisplayName:        "Local Repository",
+		Type:               "PUBLISHING",
+		Description:        "A repository for local development",
+		URL:                "http://localhost",
+		Questions:          []int{1, 2},
+	})
+}
+
+func seedPennsieveStore(pennsieve *inmemory.PennsieveStore, claims FakeClaims) {
+	userName := fmt.Sprintf("User %d", claims.UserId)
+	emailAddress := fmt.Sprintf("user%d@localhost", claims.UserId)
+
+	pennsieve.AddUser(pgdbModels.User{
+		Id:        claims.UserId,
+		NodeId:    claims.UserNodeId,
+		Email:     emailAddress,
+		FirstName: "User",
+		LastName:  fmt.Sprintf("%d", claims.UserId),
+	})
+	pennsieve.AddOrganization(pgdbModels.Organization{
+		Id:     claims.OrgId,
+		Name:   "Local Repository",
+		Slug:   "local",
+		NodeId: claims.OrgNodeId,
+	})
+	pennsieve.AddOrganization(pgdbModels.Organization{
+		Id:     welcomeWorkspaceId,
+		Name:   "Welcome to Pennsieve",
+		Slug:   "welcome_to_pennsieve",
+		NodeId: "N:organization:welcome",
+	})
+	pennsieve.AddPublishingTeam(models.PublishingTeam{
+		WorkspaceId:    claims.OrgId,
+		WorkspaceName:  "Local Repository",
+		TeamId:         1,
+		TeamName:       "Publishers",
+		PermissionBit:  16,
+		SystemTeamType: "publishers",
+		TeamNodeId:     "N:team:publishers",
+	})
+	pennsieve.AddPublisher(claims.OrgNodeId, models.Publisher{
+		WorkspaceId:       claims.OrgId,
+		WorkspaceName:     "Local Repository",
+		TeamName:          "Publishers",
+		TeamId:            1,
+		TeamPermissionBit: 16,
+		UserId:            claims.UserId,
+		UserName:          userName,
+		UserEmailAddress:  emailAddress,
+	})
+}
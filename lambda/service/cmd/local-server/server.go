@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/pennsieve/pennsieve-go-core/pkg/authorizer"
+	"github.com/pennsieve/publishing-service/service/handler"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// FakeClaims are the authorizer claims attached to every request
+type FakeClaims struct {
+	UserId     int64
+	UserNodeId string
+	OrgId      int64
+	OrgNodeId  string
+	Publisher  bool
+}
+
+// withOverrides applies the X-Fake-* request headers to the claims
+func (c FakeClaims) withOverrides(header http.Header) FakeClaims {
+	if value, err := strconv.ParseInt(header.Get("X-Fake-User-Id"), 10, 64); err == nil {
+		c.UserId = value
+		c.UserNodeId = fmt.Sprintf("N:user:%d", value)
+	}
+	if value, err := strconv.ParseInt(header.Get("X-Fake-Org-Id"), 10, 64); err == nil {
+		c.OrgId = value
+	}
+	if value := header.Get("X-Fake-Org-Node-Id"); value != "" {
+		c.OrgNodeId = value
+	}
+	if value, err := strconv.ParseBool(header.Get("X-Fake-Publisher")); err == nil {
+		c.Publisher = value
+	}
+	return c
+}
+
+// lambda returns the claims in the form produced by the Pennsieve authorizer, as parsed by authorizer.ParseClaims
+func (c FakeClaims) lambda() map[string]interface{} {
+	var teamClaims []interface{}
+	if c.Publisher {
+		teamClaims = append(teamClaims, map[string]interface{}{
+			"IntId":      float64(1),
+			"Name":       "Publishers",
+			"NodeId":     "N:team:publishers",
+			"Permission": float64(16),
+			"TeamType":   "publishers",
+		})
+	}
+
+	return map[string]interface{}{
+		authorizer.LabelUserClaim: map[string]interface{}{
+			"Id":           float64(c.UserId),
+			"NodeId":       c.UserNodeId,
+			"IsSuperAdmin": false,
+		},
+		authorizer.LabelOrganizationClaim: map[string]interface{}{
+			"Role":   float64(16),
+			"IntId":  float64(c.OrgId),
+			"NodeId": c.OrgNodeId,
+		},
+		authorizer.LabelTeamClaims: teamClaims,
+	}
+}
+
+// NewServer creates an http.Handler that serves every request with handler.PublishingServiceHandler
+func NewServer(claims FakeClaims) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// allow a frontend served from another origin to call the local server
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "*")
+		w.Header().Set("Access-Control-Allow-Headers", "*")
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		request, err := toAPIGatewayRequest(r, claims.withOverrides(r.Header))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		response, err := handler.PublishingServiceHandler(r.Context(), request)
+		if err != nil {
+			log.WithFields(log.Fields{"error": fmt.Sprintf("%+v", err)}).Error("local-server handler failed")
+		}
+		if response == nil {
+			http.Error(w, "no response from handler", http.StatusInternalServerError)
+			return
+		}
+
+		for key, value := range response.Headers {
+			w.Header().Set(key, value)
+		}
+		statusCode := response.StatusCode
+		if statusCode == 0 {
+			statusCode = http.StatusInternalServerError
+		}
+		w.WriteHeader(statusCode)
+		io.WriteString(w, response.Body)
+	})
+}
+
+// toAPIGatewayRequest translates the HTTP request into the event API Gateway sends to the Lambda function
+func toAPIGatewayRequest(r *http.Request, claims FakeClaims) (events.APIGatewayV2HTTPRequest, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return events.APIGatewayV2HTTPRequest{}, err
+	}
+
+	headers := make(map[string]string)
+	for key, values := range r.Header {
+		headers[strings.ToLower(key)] = strings.Join(values, ",")
+	}
+
+	// API Gateway joins repeated query parameters with commas
+	queryParameters := make(map[string]string)
+	for key, values := range r.URL.Query() {
+		queryParameters[key] = strings.Join(values, ",")
+	}
+
+	return events.APIGatewayV2HTTPRequest{
+		Version:               "2.0",
+		RouteKey:              fmt.Sprintf("%s %s", r.Method, r.URL.Path),
+		RawPath:               r.URL.Path,
+		RawQueryString:        r.URL.RawQuery,
+		Headers:               headers,
+		QueryStringParameters: queryParameters,
+		Body:                  string(body),
+		RequestContext: events.APIGatewayV2HTTPRequestContext{
+			RouteKey: fmt.Sprintf("%s %s", r.Method, r.URL.Path),
+			HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{
+				Method:    r.Method,
+				Path:      r.URL.Path,
+				Protocol:  r.Proto,
+				SourceIP:  r.RemoteAddr,
+				UserAgent: r.UserAgent(),
+			},
+			Authorizer: &events.APIGatewayV2HTTPRequestContextAuthorizerDescription{
+				Lambda: claims.lambda(),
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,30 @@
+// Command outbox-dispatcher is the scheduled Lambda that delivers the notifications waiting in the outbox:
+// those whose first delivery failed, retried with backoff until they are delivered or dead.
+package main
+
+import (
+	"context"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/pennsieve/publishing-service/api/config"
+	"github.com/pennsieve/publishing-service/service/handler"
+	log "github.com/sirupsen/logrus"
+)
+
+func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("invalid publishing-service configuration: %v", err)
+	}
+
+	clients, err := config.NewClients(context.Background(), cfg)
+	if err != nil {
+		log.Fatalf("unable to create AWS clients: %v", err)
+	}
+
+	dispatcher, err := handler.NewOutboxDispatcher(cfg, clients)
+	if err != nil {
+		log.Fatalf("unable to create outbox dispatcher: %v", err)
+	}
+
+	lambda.Start(handler.OutboxDispatcherHandler(dispatcher))
+}
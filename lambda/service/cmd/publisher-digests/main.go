@@ -0,0 +1,25 @@
+// Command publisher-digests is the scheduled Lambda that sends publishers who prefer a daily or weekly digest
+// one email of the proposals submitted to, or withdrawn from, their Repositories since their last digest.
+package main
+
+import (
+	"context"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/pennsieve/publishing-service/api/config"
+	"github.com/pennsieve/publishing-service/service/handler"
+	log "github.com/sirupsen/logrus"
+)
+
+func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("invalid publishing-service configuration: %v", err)
+	}
+
+	clients, err := config.NewClients(context.Background(), cfg)
+	if err != nil {
+		log.Fatalf("unable to create AWS clients: %v", err)
+	}
+
+	lambda.Start(handler.DigestsHandler(cfg, clients))
+}
@@ -0,0 +1,25 @@
+// Command review-reminders is the scheduled Lambda that reminds publishers teams of proposals awaiting
+// review, and escalates to the workspace admins those that have waited too long.
+package main
+
+import (
+	"context"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/pennsieve/publishing-service/api/config"
+	"github.com/pennsieve/publishing-service/service/handler"
+	log "github.com/sirupsen/logrus"
+)
+
+func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("invalid publishing-service configuration: %v", err)
+	}
+
+	clients, err := config.NewClients(context.Background(), cfg)
+	if err != nil {
+		log.Fatalf("unable to create AWS clients: %v", err)
+	}
+
+	lambda.Start(handler.ReviewRemindersHandler(cfg, clients))
+}
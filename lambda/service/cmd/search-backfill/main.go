@@ -0,0 +1,37 @@
+// Command search-backfill indexes every Dataset Proposal in the proposal search index. It is run once, with
+// the regular configuration, to index the proposals created before the index existed, and may be run again
+// to repair entries that were not updated when a proposal was written.
+//
+//	go run ./cmd/search-backfill
+package main
+
+import (
+	"context"
+	"github.com/pennsieve/publishing-service/api/config"
+	"github.com/pennsieve/publishing-service/api/store"
+	log "github.com/sirupsen/logrus"
+)
+
+func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("invalid publishing-service configuration: %v", err)
+	}
+
+	ctx := context.Background()
+	clients, err := config.NewClients(ctx, cfg)
+	if err != nil {
+		log.Fatalf("unable to create AWS clients: %v", err)
+	}
+
+	search := store.NewDynamoDBSearchIndex(clients.DynamoDB, cfg.Tables.ProposalSearch)
+	indexed, failed, err := store.BackfillSearchIndex(ctx, clients.DynamoDB, cfg.Tables.DatasetProposals, search)
+	if err != nil {
+		log.Fatalf("unable to read dataset proposals: %v", err)
+	}
+
+	log.WithFields(log.Fields{"indexed": indexed, "failed": failed}).Info("search-backfill complete")
+	if failed > 0 {
+		log.Fatalf("%d dataset proposals were not indexed", failed)
+	}
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/pennsieve/publishing-service/api/service"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"strings"
+)
+
+// decodeBody strictly decodes the JSON request body into v: unknown fields, values of the wrong
+// type and trailing data are rejected with a *service.ValidationError naming the offending field
+func decodeBody(body string, v interface{}) error {
+	decoder := json.NewDecoder(strings.NewReader(body))
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(v)
+	if err == nil {
+		if _, err = decoder.Token(); err != io.EOF {
+			return service.NewValidationError("", "request body must contain a single JSON object")
+		}
+		return nil
+	}
+
+	var typeError *json.UnmarshalTypeError
+	var syntaxError *json.SyntaxError
+	switch {
+	case errors.As(err, &typeError):
+		return service.NewValidationError(typeError.Field, fmt.Sprintf("must be a %s", typeError.Type.String()))
+	case errors.As(err, &syntaxError), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return service.NewValidationError("", "request body must be valid JSON")
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		return service.NewValidationError(field, "unknown or read-only field")
+	default:
+		return service.NewValidationError("", err.Error())
+	}
+}
+
+// validationErrorResponse returns the 400 response for an invalid request
+func validationErrorResponse(err *service.ValidationError) ([]byte, int) {
+	log.WithFields(log.Fields{"errors": fmt.Sprintf("%+v", err.Fields)}).Warn("request validation failed")
+
+	jsonBody, jsonErr := json.Marshal(err.DTO())
+	if jsonErr != nil {
+		log.Error("json.Marshal() failed: ", jsonErr)
+		return nil, 400
+	}
+	return jsonBody, 400
+}
@@ -7,15 +7,13 @@ import (
 	"fmt"
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/pennsieve/pennsieve-go-core/pkg/authorizer"
-	"github.com/pennsieve/pennsieve-go-core/pkg/queries/pgdb"
+	"github.com/pennsieve/pennsieve-go-core/pkg/models/role"
 	"github.com/pennsieve/publishing-service/api/dtos"
-	"github.com/pennsieve/publishing-service/api/notification"
 	"github.com/pennsieve/publishing-service/api/service"
 	"github.com/pennsieve/publishing-service/api/store"
 	log "github.com/sirupsen/logrus"
-	"github.com/valyala/fastjson"
 	"os"
-	"regexp"
+	"strings"
 )
 
 func init() {
@@ -28,122 +26,116 @@ func init() {
 	}
 }
 
-func PublishingServiceHandler(request events.APIGatewayV2HTTPRequest) (*events.APIGatewayV2HTTPResponse, error) {
+func PublishingServiceHandler(ctx context.Context, request events.APIGatewayV2HTTPRequest) (*events.APIGatewayV2HTTPResponse, error) {
 	var err error
 	var response *events.APIGatewayV2HTTPResponse
 
 	log.Println("PublishingServiceHandler() ")
 
-	response, err = handleRequest(request)
+	response, err = handleRequest(ctx, request)
 
 	return response, err
 }
 
-func handleRequest(request events.APIGatewayV2HTTPRequest) (*events.APIGatewayV2HTTPResponse, error) {
-	log.Info("handleRequest()")
+// router is the route table of the Publishing Service API
+var router = newRoutes()
+
+func newRoutes() *Router {
+	r := NewRouter()
+	r.Handle("GET", "/info", authorizedAuthor, handleGetPublishingInfo)
+	r.Handle("GET", "/repositories", nil, handleGetPublishingRepositories)
+	r.Handle("GET", "/questions", authorizedAuthor, handleGetProposalQuestions)
+
+	r.Handle("GET", "/proposals", authorizedAuthor, handleGetUserDatasetProposals)
+	r.Handle("POST", "/proposals", authorizedAuthor, handleCreateDatasetProposal)
+	r.Handle("GET", "/proposals/{nodeId}", authorizedAuthor, handleGetDatasetProposal)
+	r.Handle("PUT", "/proposals/{nodeId}", authorizedAuthor, handleUpdateDatasetProposal)
+	r.Handle("PATCH", "/proposals/{nodeId}", authorizedAuthor, handlePatchDatasetProposal)
+	r.Handle("DELETE", "/proposals/{nodeId}", authorizedAuthor, handleDeleteDatasetProposal)
+	r.Handle("POST", "/proposals/{nodeId}/submit", authorizedAuthor, handleSubmitDatasetProposal)
+	r.Handle("POST", "/proposals/{nodeId}/withdraw", authorizedAuthor, handleWithdrawDatasetProposal)
+
+	r.Handle("GET", "/submissions", authorizedPublisher, handleGetWorkspaceDatasetProposals)
+	r.Handle("GET", "/submissions/search", authorizedPublisher, handleSearchWorkspaceDatasetProposals)
+	r.Handle("POST", "/submissions/{nodeId}/accept", authorizedPublisher, handleAcceptDatasetProposal)
+	r.Handle("POST", "/submissions/{nodeId}/reject", authorizedPublisher, handleRejectDatasetProposal)
+	r.Handle("GET", "/submissions/{nodeId}/email-preview", authorizedPublisherOrAdmin, handlePreviewNotification)
+
+	r.Handle("GET", "/notification-preferences", authorizedAuthor, handleGetNotificationPreference)
+	r.Handle("PUT", "/notification-preferences", authorizedAuthor, handleUpdateNotificationPreference)
+
+	r.Handle("GET", "/webhooks", authorizedPublisher, handleGetWebhooks)
+	r.Handle("POST", "/webhooks", authorizedPublisher, handleCreateWebhook)
+	r.Handle("PUT", "/webhooks/{webhookId}", authorizedPublisher, handleUpdateWebhook)
+	r.Handle("DELETE", "/webhooks/{webhookId}", authorizedPublisher, handleDeleteWebhook)
+	r.Handle("GET", "/webhooks/{webhookId}/deliveries", authorizedPublisher, handleGetWebhookDeliveries)
+
+	// legacy routes, which identify the proposal with a query parameter or the request body
+	r.HandleDeprecated("GET", "/proposal", "/proposals", authorizedAuthor, handleGetUserDatasetProposals)
+	r.HandleDeprecated("POST", "/proposal", "/proposals", authorizedAuthor, handleCreateDatasetProposal)
+	r.HandleDeprecated("PUT", "/proposal", "/proposals/{nodeId}", authorizedAuthor, handleUpdateDatasetProposal)
+	r.HandleDeprecated("DELETE", "/proposal", "/proposals/{nodeId}", authorizedAuthor, handleDeleteDatasetProposal)
+	r.HandleDeprecated("POST", "/proposal/submit", "/proposals/{nodeId}/submit", authorizedAuthor, handleSubmitDatasetProposal)
+	r.HandleDeprecated("POST", "/proposal/withdraw", "/proposals/{nodeId}/withdraw", authorizedAuthor, handleWithdrawDatasetProposal)
+	r.HandleDeprecated("GET", "/submission", "/submissions", authorizedPublisher, handleGetWorkspaceDatasetProposals)
+	r.HandleDeprecated("GET", "/submission/search", "/submissions/search", authorizedPublisher, handleSearchWorkspaceDatasetProposals)
+	r.HandleDeprecated("POST", "/submission/accept", "/submissions/{nodeId}/accept", authorizedPublisher, handleAcceptDatasetProposal)
+	r.HandleDeprecated("POST", "/submission/reject", "/submissions/{nodeId}/reject", authorizedPublisher, handleRejectDatasetProposal)
+	return r
+}
 
-	var err error
-	var statusCode int
-	var jsonBody []byte
+func handleRequest(ctx context.Context, request events.APIGatewayV2HTTPRequest) (*events.APIGatewayV2HTTPResponse, error) {
+	log.Info("handleRequest()")
 
-	r := regexp.MustCompile(`(?P<method>) (?P<pathKey>.*)`)
-	routeKeyParts := r.FindStringSubmatch(request.RouteKey)
-	routeKey := routeKeyParts[r.SubexpIndex("pathKey")]
+	path := routePath(request)
 	httpMethod := request.RequestContext.HTTP.Method
+	log.WithFields(log.Fields{"method": httpMethod, "route": path}).Info("handleRequest()")
 
-	var serviceImpl service.PublishingService
-	var claims *authorizer.Claims
-	switch routeKey {
-	case "/repositories":
-		pubStore := store.NewPublishingStore()
-		serviceImpl = service.NewPublishingService(pubStore, nil, nil)
+	route, pathParameters, allowed := router.Match(httpMethod, path, request.PathParameters)
+	if route == nil {
+		if len(allowed) == 0 {
+			log.WithFields(log.Fields{"method": httpMethod, "route": path}).Warn("handleRequest() unknown route")
+			return response(nil, 404, nil), nil
+		}
+		log.WithFields(log.Fields{"method": httpMethod, "route": path, "allowed": allowed}).Warn("handleRequest() method not allowed")
+		return response(nil, 405, map[string]string{"allow": strings.Join(allowed, ", ")}), nil
+	}
 
-	default:
+	var claims *authorizer.Claims
+	if route.Authorize != nil {
 		claims = authorizer.ParseClaims(request.RequestContext.Authorizer.Lambda)
-		orgId := claims.OrgClaim.IntId
-
-		db, err := pgdb.ConnectRDSWithOrg(int(orgId))
-		if err != nil {
-			panic(fmt.Sprintf("unable to connect to RDS database: %s", err))
+		if !route.Authorize(claims) {
+			return response(nil, 401, nil), nil
 		}
-		log.WithFields(log.Fields{"orgId": orgId, "resource": "database", "action": "connect"}).Info("connected to RDS database")
-		defer db.Close()
-
-		pubStore := store.NewPublishingStore()
-		pennsieve := store.NewPennsieveStore(db, orgId)
-		// Emails are sent via the Pennsieve email-service (enqueue -> consumer
-		// renders + delivers), replacing the previous direct-SES EmailNotifier.
-		notifier, err := notification.NewQueueNotifier(context.TODO())
-		if err != nil {
-			log.WithFields(log.Fields{"error": fmt.Sprintf("%+v", err)}).Error("failed to create email notifier")
-			return &events.APIGatewayV2HTTPResponse{StatusCode: 500}, nil
-		}
-		serviceImpl = service.NewPublishingService(pubStore, pennsieve, notifier)
 	}
 
-	log.WithFields(log.Fields{"method": httpMethod, "route": routeKey}).Info("handleRequest()")
-
-	switch routeKey {
-	case "/info":
-		switch httpMethod {
-		case "GET":
-			jsonBody, statusCode = handleGetPublishingInfo(serviceImpl)
-		}
-	case "/repositories":
-		switch httpMethod {
-		case "GET":
-			jsonBody, statusCode = handleGetPublishingRepositories(serviceImpl)
-		}
-	case "/questions":
-		switch httpMethod {
-		case "GET":
-			jsonBody, statusCode = handleGetProposalQuestions(serviceImpl)
-		}
-	case "/proposal":
-		switch httpMethod {
-		case "GET":
-			if ok := authorizedAuthor(claims); ok {
-				jsonBody, statusCode = handleGetUserDatasetProposals(claims, serviceImpl)
-			} else {
-				jsonBody = nil
-				statusCode = 401
-			}
-		case "POST":
-			jsonBody, statusCode = handleCreateDatasetProposal(request, claims, serviceImpl)
-		case "PUT":
-			jsonBody, statusCode = handleUpdateDatasetProposal(request, claims, serviceImpl)
-		case "DELETE":
-			jsonBody, statusCode = handleDeleteDatasetProposal(request, claims, serviceImpl)
-		}
-	case "/proposal/submit":
-		switch httpMethod {
-		case "POST":
-			jsonBody, statusCode = handleSubmitDatasetProposal(request, claims, serviceImpl)
-		}
-	case "/proposal/withdraw":
-		switch httpMethod {
-		case "POST":
-			jsonBody, statusCode = handleWithdrawDatasetProposal(request, claims, serviceImpl)
-		}
-	case "/submission":
-		switch httpMethod {
-		case "GET":
-			jsonBody, statusCode = handleGetWorkspaceDatasetProposals(authorizedPublisher, claims, serviceImpl, request)
-		}
-	case "/submission/accept":
-		switch httpMethod {
-		case "POST":
-			jsonBody, statusCode = handleAcceptDatasetProposal(authorizedPublisher, claims, serviceImpl, request)
-		}
-	case "/submission/reject":
-		switch httpMethod {
-		case "POST":
-			jsonBody, statusCode = handleRejectDatasetProposal(authorizedPublisher, claims, serviceImpl, request)
+	serviceImpl, release, err := provideService(ctx, claims)
+	if err != nil {
+		log.WithFields(log.Fields{"error": fmt.Sprintf("%+v", err)}).Error("handleRequest() failed to create service")
+		return response(nil, 500, nil), nil
+	}
+	defer release()
+
+	request.PathParameters = pathParameters
+	jsonBody, statusCode := route.Handler(ctx, &Request{
+		APIGatewayV2HTTPRequest: request,
+		Claims:                  claims,
+		Service:                 serviceImpl,
+	})
+
+	var headers map[string]string
+	if route.Successor != "" {
+		log.WithFields(log.Fields{"method": httpMethod, "route": path, "successor": route.Successor}).Warn("handleRequest() deprecated route")
+		headers = map[string]string{
+			"deprecation": "true",
+			"link":        fmt.Sprintf("<%s>; rel=\"successor-version\"", route.Successor),
 		}
-	default:
-		err = errors.New("unknown route")
 	}
 
+	return response(jsonBody, statusCode, headers), nil
+}
+
+func response(jsonBody []byte, statusCode int, headers map[string]string) *events.APIGatewayV2HTTPResponse {
 	jsonString := string(jsonBody)
 	log.Println("handleRequest() jsonString: ", jsonString)
 
@@ -154,9 +146,12 @@ func handleRequest(request events.APIGatewayV2HTTPRequest) (*events.APIGatewayV2
 			"content-type": "application/json",
 		},
 	}
+	for key, value := range headers {
+		response.Headers[key] = value
+	}
 	log.Println("handleRequest() response: ", response)
 
-	return &response, err
+	return &response
 }
 
 type Authorizer func(claims *authorizer.Claims) bool
@@ -168,9 +163,12 @@ func authorizedAuthor(claims *authorizer.Claims) bool {
 func authorizedPublisher(claims *authorizer.Claims) bool {
 	return authorizer.IsPublisher(claims)
 }
+func authorizedPublisherOrAdmin(claims *authorizer.Claims) bool {
+	return authorizer.IsPublisher(claims) || authorizer.HasOrgRole(claims, role.Manager)
+}
 
-func handleGetPublishingInfo(service service.PublishingService) ([]byte, int) {
-	result, err := service.GetPublishingInfo()
+func handleGetPublishingInfo(ctx context.Context, request *Request) ([]byte, int) {
+	result, err := request.Service.GetPublishingInfo(ctx)
 	if err != nil {
 		// TODO: provide a better response than nil on a 500
 		return nil, 500
@@ -185,8 +183,8 @@ func handleGetPublishingInfo(service service.PublishingService) ([]byte, int) {
 	return jsonBody, 200
 }
 
-func handleGetPublishingRepositories(service service.PublishingService) ([]byte, int) {
-	result, err := service.GetPublishingRepositories()
+func handleGetPublishingRepositories(ctx context.Context, request *Request) ([]byte, int) {
+	result, err := request.Service.GetPublishingRepositories(ctx)
 	if err != nil {
 		// TODO: provide a better response than nil on a 500
 		return nil, 500
@@ -201,8 +199,8 @@ func handleGetPublishingRepositories(service service.PublishingService) ([]byte,
 	return jsonBody, 200
 }
 
-func handleGetProposalQuestions(service service.PublishingService) ([]byte, int) {
-	result, err := service.GetProposalQuestions()
+func handleGetProposalQuestions(ctx context.Context, request *Request) ([]byte, int) {
+	result, err := request.Service.GetProposalQuestions(ctx)
 	if err != nil {
 		// TODO: provide a better response than nil on a 500
 		return nil, 500
@@ -217,13 +215,13 @@ func handleGetProposalQuestions(service service.PublishingService) ([]byte, int)
 	return jsonBody, 200
 }
 
-func handleGetUserDatasetProposals(claims *authorizer.Claims, service service.PublishingService) ([]byte, int) {
+func handleGetUserDatasetProposals(ctx context.Context, request *Request) ([]byte, int) {
 	log.Info("handleGetUserDatasetProposals()")
 	// get user id from User Claim
-	userId := claims.UserClaim.Id
+	userId := request.Claims.UserClaim.Id
 	log.WithFields(log.Fields{"userId": userId}).Debug("handleGetUserDatasetProposals()")
 
-	result, err := service.GetDatasetProposalsForUser(userId)
+	result, err := request.Service.GetDatasetProposalsForUser(ctx, userId)
 	if err != nil {
 		log.Error("service.GetDatasetProposalsForUser() failed: ", err)
 		return nil, 500
@@ -238,34 +236,47 @@ func handleGetUserDatasetProposals(claims *authorizer.Claims, service service.Pu
 	return jsonBody, 200
 }
 
-func handleGetWorkspaceDatasetProposals(authorized Authorizer, claims *authorizer.Claims, service service.PublishingService, request events.APIGatewayV2HTTPRequest) ([]byte, int) {
-	log.WithFields(log.Fields{}).Info("handleGetWorkspaceDatasetProposals")
-	if !authorized(claims) {
-		return nil, 401
+func handleGetDatasetProposal(ctx context.Context, request *Request) ([]byte, int) {
+	nodeId := request.PathParameters["nodeId"]
+	viewer := service.ProposalViewer{UserId: int(request.Claims.UserClaim.Id)}
+	if authorizedPublisher(request.Claims) {
+		viewer.PublisherOf = request.Claims.OrgClaim.NodeId
 	}
+	log.WithFields(log.Fields{"viewer": fmt.Sprintf("%+v", viewer), "nodeId": nodeId}).Debug("handleGetDatasetProposal()")
 
-	// get workspace NodeId from Organization Claim
-	orgNodeId := claims.OrgClaim.NodeId
+	proposalDTO, err := request.Service.GetDatasetProposalForViewer(ctx, viewer, nodeId)
+	if err != nil {
+		// the proposal does not exist, or is not visible to the user
+		log.WithFields(log.Fields{"nodeId": nodeId, "err": fmt.Sprintf("%+v", err)}).Warn("handleGetDatasetProposal()")
+		return nil, 404
+	}
 
-	// get proposal status from request query parameters (default = 'SUBMITTED')
-	var status string
-	var found bool
-	queryParams := request.QueryStringParameters
-	if status, found = queryParams["status"]; !found {
-		status = "SUBMITTED"
+	jsonBody, err := json.Marshal(proposalDTO)
+	if err != nil {
+		log.Error("json.Marshal() failed: ", err)
+		return nil, 500
 	}
 
-	// TODO: only permit query where status is SUBMITTED, ACCEPTED or REJECTED; else return a 400?
+	return jsonBody, 200
+}
+
+func handleGetWorkspaceDatasetProposals(ctx context.Context, request *Request) ([]byte, int) {
+	log.WithFields(log.Fields{}).Info("handleGetWorkspaceDatasetProposals")
+	// get workspace NodeId from Organization Claim
+	orgNodeId := request.Claims.OrgClaim.NodeId
 
-	result, err := service.GetDatasetProposalsForWorkspace(orgNodeId, status)
+	// get proposal status(es) from request query parameters (default = 'SUBMITTED')
+	// status may be a single status, a comma-separated list of statuses, or ALL
+	statuses, err := service.ParseSubmissionStatuses(request.QueryStringParameters["status"])
 	if err != nil {
-		// TODO: provide a better response than nil on a 500
-		return nil, 500
+		log.WithFields(log.Fields{"status": request.QueryStringParameters["status"], "error": fmt.Sprintf("%+v", err)}).Error("handleGetWorkspaceDatasetProposals()")
+		return nil, 400
 	}
 
-	response := &dtos.DatasetSubmissionsDTO{
-		TotalCount: len(result),
-		Proposals:  result,
+	response, err := request.Service.GetDatasetSubmissionsForWorkspace(ctx, orgNodeId, statuses)
+	if err != nil {
+		// TODO: provide a better response than nil on a 500
+		return nil, 500
 	}
 
 	jsonBody, err := json.Marshal(response)
@@ -277,23 +288,49 @@ func handleGetWorkspaceDatasetProposals(authorized Authorizer, claims *authorize
 	return jsonBody, 200
 }
 
-func handleCreateDatasetProposal(request events.APIGatewayV2HTTPRequest, claims *authorizer.Claims, service service.PublishingService) ([]byte, int) {
-	log.Println("handleCreateDatasetProposal()")
-	err := fastjson.Validate(request.Body)
+func handleSearchWorkspaceDatasetProposals(ctx context.Context, request *Request) ([]byte, int) {
+	log.WithFields(log.Fields{}).Info("handleSearchWorkspaceDatasetProposals")
+	// get search text from request query parameters
+	query := strings.TrimSpace(request.QueryStringParameters["q"])
+	if query == "" {
+		return nil, 400
+	}
+
+	orgNodeId := request.Claims.OrgClaim.NodeId
+	log.WithFields(log.Fields{"orgNodeId": orgNodeId, "query": query}).Debug("handleSearchWorkspaceDatasetProposals()")
+
+	response, err := request.Service.SearchDatasetSubmissionsForWorkspace(ctx, orgNodeId, query)
 	if err != nil {
-		log.Fatalln("handleCreateDatasetProposal() request body validation failed: ", err)
+		log.WithFields(log.Fields{"failure": "SearchDatasetSubmissionsForWorkspace", "err": fmt.Sprintf("%+v", err)}).Error("handleSearchWorkspaceDatasetProposals()")
 		return nil, 500
 	}
 
-	// Unmarshal JSON into Dataset Proposal DTO
-	bytes := []byte(request.Body)
-	var requestDTO dtos.DatasetProposalDTO
-	json.Unmarshal(bytes, &requestDTO)
+	jsonBody, err := json.Marshal(response)
+	if err != nil {
+		log.Error("json.Marshal() failed: ", err)
+		return nil, 500
+	}
+
+	return jsonBody, 200
+}
+
+func handleCreateDatasetProposal(ctx context.Context, request *Request) ([]byte, int) {
+	log.Println("handleCreateDatasetProposal()")
+
+	// strictly decode JSON into the Dataset Proposal create request
+	var requestDTO dtos.DatasetProposalCreateRequest
+	var validationErr *service.ValidationError
+	if err := decodeBody(request.Body, &requestDTO); errors.As(err, &validationErr) {
+		return validationErrorResponse(validationErr)
+	}
 	log.WithFields(log.Fields{"requestDTO": fmt.Sprintf("%+v", requestDTO)}).Debug("handleCreateDatasetProposal()")
 
-	resultDTO, err := service.CreateDatasetProposal(claims.UserClaim.Id, requestDTO)
+	resultDTO, err := request.Service.CreateDatasetProposal(ctx, request.Claims.UserClaim.Id, requestDTO)
+	if errors.As(err, &validationErr) {
+		return validationErrorResponse(validationErr)
+	}
 	if err != nil {
-		log.Fatalln("handleCreateDatasetProposal() - service.CreateDatasetProposal() failed: ", err)
+		log.Error("handleCreateDatasetProposal() - service.CreateDatasetProposal() failed: ", err)
 		return nil, 500
 	}
 	log.WithFields(log.Fields{"resultDTO": fmt.Sprintf("%+v", resultDTO)}).Debug("handleCreateDatasetProposal()")
@@ -308,39 +345,44 @@ func handleCreateDatasetProposal(request events.APIGatewayV2HTTPRequest, claims
 	return jsonBody, 201
 }
 
-func handleUpdateDatasetProposal(request events.APIGatewayV2HTTPRequest, claims *authorizer.Claims, service service.PublishingService) ([]byte, int) {
+func handleUpdateDatasetProposal(ctx context.Context, request *Request) ([]byte, int) {
 	log.WithFields(log.Fields{"request.body": request.Body}).Debug("handleUpdateDatasetProposal()")
 
-	var err error
-
-	// validate JSON
-	err = fastjson.Validate(request.Body)
-	if err != nil {
-		log.WithFields(log.Fields{"request.Body": request.Body}).Error("request body validation failed: ", err)
-		return nil, 400
+	// strictly decode JSON into the Dataset Proposal update request
+	var requestDTO dtos.DatasetProposalUpdateRequest
+	var validationErr *service.ValidationError
+	if err := decodeBody(request.Body, &requestDTO); errors.As(err, &validationErr) {
+		return validationErrorResponse(validationErr)
 	}
-
-	// Unmarshal JSON into Dataset Proposal DTO
-	bytes := []byte(request.Body)
-	var requestDTO dtos.DatasetProposalDTO
-	json.Unmarshal(bytes, &requestDTO)
 	log.WithFields(log.Fields{"requestDTO": fmt.Sprintf("%+v", requestDTO)}).Debug("handleUpdateDatasetProposal()")
 
+	// the ProposalNodeId in the path takes precedence over the (legacy) request body
+	if nodeId, found := request.PathParameters["nodeId"]; found {
+		requestDTO.NodeId = nodeId
+	}
+
 	// check that ProposalNodeId was provided
 	if requestDTO.NodeId == "" {
-		log.WithFields(log.Fields{}).Error("missing required field(s): ProposalNodeId")
-		return nil, 400
+		return validationErrorResponse(service.NewValidationError("nodeId", "is required"))
 	}
+	userId := int(request.Claims.UserClaim.Id)
 
 	// get Proposal by UserId and ProposalNodeId
-	proposal, err := service.GetDatasetProposal(requestDTO.UserId, requestDTO.NodeId)
+	proposal, err := request.Service.GetDatasetProposal(ctx, userId, requestDTO.NodeId)
 	if err != nil {
-		log.WithFields(log.Fields{"UserId": requestDTO.UserId, "NodeId": requestDTO.NodeId}).Error("Dataset Proposal does not exist")
+		log.WithFields(log.Fields{"UserId": userId, "NodeId": requestDTO.NodeId}).Error("Dataset Proposal does not exist")
 		return nil, 404
 	}
 
 	// if it exists, then invoke update
-	resultDTO, err := service.UpdateDatasetProposal(claims.UserClaim.Id, proposal, requestDTO)
+	resultDTO, err := request.Service.UpdateDatasetProposal(ctx, request.Claims.UserClaim.Id, proposal, requestDTO)
+	if errors.As(err, &validationErr) {
+		return validationErrorResponse(validationErr)
+	}
+	if errors.Is(err, store.ErrConflict) {
+		// the proposal was changed by another request since the version the update was made from
+		return nil, 409
+	}
 	if err != nil {
 		log.Error("service.UpdateDatasetProposal() failed: ", err)
 		return nil, 500
@@ -356,29 +398,71 @@ func handleUpdateDatasetProposal(request events.APIGatewayV2HTTPRequest, claims
 	return jsonBody, 200
 }
 
-func handleDeleteDatasetProposal(request events.APIGatewayV2HTTPRequest, claims *authorizer.Claims, service service.PublishingService) ([]byte, int) {
+func handlePatchDatasetProposal(ctx context.Context, request *Request) ([]byte, int) {
+	log.WithFields(log.Fields{"request.body": request.Body}).Debug("handlePatchDatasetProposal()")
+
+	// strictly decode the JSON Merge Patch of the Dataset Proposal
+	var patch dtos.DatasetProposalPatchRequest
+	var validationErr *service.ValidationError
+	if err := decodeBody(request.Body, &patch); errors.As(err, &validationErr) {
+		return validationErrorResponse(validationErr)
+	}
+
+	// get Proposal by UserId and ProposalNodeId
+	nodeId := request.PathParameters["nodeId"]
+	userId := int(request.Claims.UserClaim.Id)
+	proposal, err := request.Service.GetDatasetProposal(ctx, userId, nodeId)
+	if err != nil {
+		log.WithFields(log.Fields{"UserId": userId, "NodeId": nodeId}).Error("Dataset Proposal does not exist")
+		return nil, 404
+	}
+
+	// merge the patch onto the stored Dataset Proposal
+	resultDTO, err := request.Service.PatchDatasetProposal(ctx, request.Claims.UserClaim.Id, proposal, patch)
+	if errors.As(err, &validationErr) {
+		return validationErrorResponse(validationErr)
+	}
+	if errors.Is(err, store.ErrConflict) {
+		// the proposal was changed by another request since it was read
+		return nil, 409
+	}
+	if err != nil {
+		log.Error("service.PatchDatasetProposal() failed: ", err)
+		return nil, 500
+	}
+	log.WithFields(log.Fields{"resultDTO": fmt.Sprintf("%+v", resultDTO)}).Debug("handlePatchDatasetProposal()")
+
+	jsonBody, err := json.Marshal(resultDTO)
+	if err != nil {
+		log.Error("json.Marshal() failed: ", err)
+		return nil, 500
+	}
+
+	return jsonBody, 200
+}
+
+func handleDeleteDatasetProposal(ctx context.Context, request *Request) ([]byte, int) {
 	log.WithFields(log.Fields{}).Debug("handleDeleteDatasetProposal()")
 
 	var err error
 	var nodeId string
 	var found bool
 
-	// get ProposalNodeId from request query parameters
-	queryParams := request.QueryStringParameters
-	if nodeId, found = queryParams["proposal_node_id"]; !found {
+	// get ProposalNodeId from the path, or from the (legacy) query parameters
+	if nodeId, found = proposalNodeId(request, "proposal_node_id"); !found {
 		return nil, 400
 	}
 
-	userId := int(claims.UserClaim.Id)
+	userId := int(request.Claims.UserClaim.Id)
 
-	proposal, err := service.GetDatasetProposal(userId, nodeId)
+	proposal, err := request.Service.GetDatasetProposal(ctx, userId, nodeId)
 	if err != nil {
 		// probably not found
 		return nil, 404
 	}
 	log.WithFields(log.Fields{"proposal": fmt.Sprintf("%+v", proposal)}).Debug("handleDeleteDatasetProposal() found proposal")
 
-	_, err = service.DeleteDatasetProposal(proposal)
+	_, err = request.Service.DeleteDatasetProposal(ctx, proposal)
 	if err != nil {
 		// TODO: log an error message
 		return nil, 500
@@ -387,22 +471,21 @@ func handleDeleteDatasetProposal(request events.APIGatewayV2HTTPRequest, claims
 	return nil, 200
 }
 
-func handleSubmitDatasetProposal(request events.APIGatewayV2HTTPRequest, claims *authorizer.Claims, service service.PublishingService) ([]byte, int) {
+func handleSubmitDatasetProposal(ctx context.Context, request *Request) ([]byte, int) {
 	log.WithFields(log.Fields{}).Debug("handleSubmitDatasetProposal()")
 
 	var err error
 	var nodeId string
 	var found bool
 
-	// get ProposalNodeId from request query parameters
-	queryParams := request.QueryStringParameters
-	if nodeId, found = queryParams["node_id"]; !found {
+	// get ProposalNodeId from the path, or from the (legacy) query parameters
+	if nodeId, found = proposalNodeId(request, "node_id"); !found {
 		return nil, 400
 	}
 
-	userId := int(claims.UserClaim.Id)
+	userId := int(request.Claims.UserClaim.Id)
 
-	proposalDTO, err := service.SubmitDatasetProposal(userId, nodeId)
+	proposalDTO, err := request.Service.SubmitDatasetProposal(ctx, userId, nodeId)
 	if err != nil {
 		return nil, 400
 	}
@@ -417,22 +500,21 @@ func handleSubmitDatasetProposal(request events.APIGatewayV2HTTPRequest, claims
 	return jsonBody, 200
 }
 
-func handleWithdrawDatasetProposal(request events.APIGatewayV2HTTPRequest, claims *authorizer.Claims, service service.PublishingService) ([]byte, int) {
+func handleWithdrawDatasetProposal(ctx context.Context, request *Request) ([]byte, int) {
 	log.WithFields(log.Fields{}).Debug("handleWithdrawDatasetProposal()")
 
 	var err error
 	var nodeId string
 	var found bool
 
-	// get ProposalNodeId from request query parameters
-	queryParams := request.QueryStringParameters
-	if nodeId, found = queryParams["node_id"]; !found {
+	// get ProposalNodeId from the path, or from the (legacy) query parameters
+	if nodeId, found = proposalNodeId(request, "node_id"); !found {
 		return nil, 400
 	}
 
-	userId := int(claims.UserClaim.Id)
+	userId := int(request.Claims.UserClaim.Id)
 
-	proposalDTO, err := service.WithdrawDatasetProposal(userId, nodeId)
+	proposalDTO, err := request.Service.WithdrawDatasetProposal(ctx, userId, nodeId)
 	if err != nil {
 		return nil, 400
 	}
@@ -448,26 +530,21 @@ func handleWithdrawDatasetProposal(request events.APIGatewayV2HTTPRequest, claim
 
 }
 
-func handleAcceptDatasetProposal(authorized Authorizer, claims *authorizer.Claims, service service.PublishingService, request events.APIGatewayV2HTTPRequest) ([]byte, int) {
+func handleAcceptDatasetProposal(ctx context.Context, request *Request) ([]byte, int) {
 	log.WithFields(log.Fields{}).Info("handleAcceptDatasetProposal")
-	if !authorized(claims) {
-		return nil, 401
-	}
-
 	var err error
 	var nodeId string
 	var found bool
 
-	// get ProposalNodeId from request query parameters
-	queryParams := request.QueryStringParameters
-	if nodeId, found = queryParams["node_id"]; !found {
+	// get ProposalNodeId from the path, or from the (legacy) query parameters
+	if nodeId, found = proposalNodeId(request, "node_id"); !found {
 		return nil, 400
 	}
 
-	orgNodeId := claims.OrgClaim.NodeId
+	orgNodeId := request.Claims.OrgClaim.NodeId
 	log.WithFields(log.Fields{"orgNodeId": orgNodeId, "nodeId": nodeId}).Debug("handleAcceptDatasetProposal()")
 
-	proposalDTO, err := service.AcceptDatasetProposal(orgNodeId, nodeId)
+	proposalDTO, err := request.Service.AcceptDatasetProposal(ctx, orgNodeId, nodeId)
 	if err != nil {
 		log.WithFields(log.Fields{"failure": "AcceptDatasetProposal", "err": fmt.Sprintf("%+v", err)}).Error("handleAcceptDatasetProposal()")
 		return nil, 400
@@ -483,26 +560,21 @@ func handleAcceptDatasetProposal(authorized Authorizer, claims *authorizer.Claim
 	return jsonBody, 200
 }
 
-func handleRejectDatasetProposal(authorized Authorizer, claims *authorizer.Claims, service service.PublishingService, request events.APIGatewayV2HTTPRequest) ([]byte, int) {
+func handleRejectDatasetProposal(ctx context.Context, request *Request) ([]byte, int) {
 	log.WithFields(log.Fields{}).Info("handleRejectDatasetProposal")
-	if !authorized(claims) {
-		return nil, 401
-	}
-
 	var err error
 	var nodeId string
 	var found bool
 
-	// get ProposalNodeId from request query parameters
-	queryParams := request.QueryStringParameters
-	if nodeId, found = queryParams["node_id"]; !found {
+	// get ProposalNodeId from the path, or from the (legacy) query parameters
+	if nodeId, found = proposalNodeId(request, "node_id"); !found {
 		return nil, 400
 	}
 
-	orgNodeId := claims.OrgClaim.NodeId
+	orgNodeId := request.Claims.OrgClaim.NodeId
 	log.WithFields(log.Fields{"orgNodeId": orgNodeId, "nodeId": nodeId}).Debug("handleRejectDatasetProposal()")
 
-	proposalDTO, err := service.RejectDatasetProposal(orgNodeId, nodeId)
+	proposalDTO, err := request.Service.RejectDatasetProposal(ctx, orgNodeId, nodeId)
 	if err != nil {
 		return nil, 400
 	}
@@ -516,3 +588,59 @@ func handleRejectDatasetProposal(authorized Authorizer, claims *authorizer.Claim
 
 	return jsonBody, 200
 }
+
+// proposalNodeId returns the ProposalNodeId from the path, or from the query parameter of the legacy routes
+func proposalNodeId(request *Request, queryParameter string) (string, bool) {
+	if nodeId, found := request.PathParameters["nodeId"]; found {
+		return nodeId, true
+	}
+	nodeId, found := request.QueryStringParameters[queryParameter]
+	return nodeId, found
+}
+
+func handleGetNotificationPreference(ctx context.Context, request *Request) ([]byte, int) {
+	userId := request.Claims.UserClaim.Id
+	log.WithFields(log.Fields{"userId": userId}).Info("handleGetNotificationPreference()")
+
+	result, err := request.Service.GetNotificationPreference(ctx, userId)
+	if err != nil {
+		log.Error("service.GetNotificationPreference() failed: ", err)
+		return nil, 500
+	}
+
+	jsonBody, err := json.Marshal(result)
+	if err != nil {
+		log.Error("json.Marshal() failed: ", err)
+		return nil, 500
+	}
+
+	return jsonBody, 200
+}
+
+func handleUpdateNotificationPreference(ctx context.Context, request *Request) ([]byte, int) {
+	userId := request.Claims.UserClaim.Id
+	log.WithFields(log.Fields{"userId": userId, "request.body": request.Body}).Info("handleUpdateNotificationPreference()")
+
+	var requestDTO dtos.NotificationPreferenceRequest
+	var validationErr *service.ValidationError
+	if err := decodeBody(request.Body, &requestDTO); errors.As(err, &validationErr) {
+		return validationErrorResponse(validationErr)
+	}
+
+	result, err := request.Service.UpdateNotificationPreference(ctx, userId, requestDTO)
+	if errors.As(err, &validationErr) {
+		return validationErrorResponse(validationErr)
+	}
+	if err != nil {
+		log.Error("service.UpdateNotificationPreference() failed: ", err)
+		return nil, 500
+	}
+
+	jsonBody, err := json.Marshal(result)
+	if err != nil {
+		log.Error("json.Marshal() failed: ", err)
+		return nil, 500
+	}
+
+	return jsonBody, 200
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/pennsieve/publishing-service/api/service"
+	log "github.com/sirupsen/logrus"
+	"strings"
+)
+
+// handlePreviewNotification renders the notification named by the "notification" query parameter, e.g.
+// ProposalAccepted, for a Dataset Proposal submitted to the Repository of the claims, without sending it
+func handlePreviewNotification(ctx context.Context, request *Request) ([]byte, int) {
+	orgNodeId := request.Claims.OrgClaim.NodeId
+	nodeId := request.PathParameters["nodeId"]
+	name := strings.TrimSpace(request.QueryStringParameters["notification"])
+	log.WithFields(log.Fields{"orgNodeId": orgNodeId, "nodeId": nodeId, "notification": name}).Info("handlePreviewNotification()")
+
+	result, err := request.Service.PreviewNotification(ctx, orgNodeId, nodeId, name)
+	var validationErr *service.ValidationError
+	if errors.As(err, &validationErr) {
+		return validationErrorResponse(validationErr)
+	}
+	if errors.Is(err, service.ErrSubmissionNotFound) {
+		return nil, 404
+	}
+	if errors.Is(err, service.ErrPreviewUnavailable) {
+		return nil, 501
+	}
+	if err != nil {
+		log.Error("service.PreviewNotification() failed: ", err)
+		return nil, 500
+	}
+
+	jsonBody, err := json.Marshal(result)
+	if err != nil {
+		log.Error("json.Marshal() failed: ", err)
+		return nil, 500
+	}
+
+	return jsonBody, 200
+}
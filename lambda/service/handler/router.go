@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"context"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/pennsieve/pennsieve-go-core/pkg/authorizer"
+	"github.com/pennsieve/publishing-service/api/service"
+	"sort"
+	"strings"
+)
+
+// Request is the API Gateway request being handled, along with the caller's claims and the service
+// that handles it. The PathParameters of the embedded request hold the values matched by the route.
+type Request struct {
+	events.APIGatewayV2HTTPRequest
+	Claims  *authorizer.Claims
+	Service service.PublishingService
+}
+
+// HandlerFunc handles a routed request, returning the JSON response body and status code
+type HandlerFunc func(ctx context.Context, request *Request) ([]byte, int)
+
+// Route maps a method and path to a handler. The path may contain parameters, e.g. /proposal/{nodeId}.
+// Requests for a route with an Authorizer carry the caller's claims and are rejected with a 401 when
+// the Authorizer denies them; a route without one is public and its requests carry no claims.
+// A deprecated route names the Successor that replaces it.
+type Route struct {
+	Method    string
+	Path      string
+	Authorize Authorizer
+	Handler   HandlerFunc
+	Successor string
+	segments  []string
+}
+
+// Router matches requests against its route table
+type Router struct {
+	routes []*Route
+}
+
+func NewRouter() *Router {
+	return &Router{}
+}
+
+// Handle registers a route
+func (r *Router) Handle(method string, path string, authorize Authorizer, handler HandlerFunc) {
+	r.routes = append(r.routes, &Route{
+		Method:    method,
+		Path:      path,
+		Authorize: authorize,
+		Handler:   handler,
+		segments:  pathSegments(path),
+	})
+}
+
+// HandleDeprecated registers a deprecated alias of the successor route
+func (r *Router) HandleDeprecated(method string, path string, successor string, authorize Authorizer, handler HandlerFunc) {
+	r.Handle(method, path, authorize, handler)
+	r.routes[len(r.routes)-1].Successor = successor
+}
+
+// Match finds the route for the method and path, along with the path parameters it matched.
+// When no route matches the path, Match returns nil and no methods; when routes match the path
+// but not the method, it returns nil and the methods they allow.
+func (r *Router) Match(method string, path string, pathParameters map[string]string) (*Route, map[string]string, []string) {
+	segments := pathSegments(path)
+
+	var best *Route
+	var bestParameters map[string]string
+	bestLiterals := -1
+	allowed := make(map[string]bool)
+	for _, route := range r.routes {
+		parameters, literals, ok := route.match(segments, pathParameters)
+		if !ok {
+			continue
+		}
+		allowed[route.Method] = true
+		// prefer the most specific route, e.g. /proposal/submit over /proposal/{nodeId}
+		if route.Method == method && literals > bestLiterals {
+			best, bestParameters, bestLiterals = route, parameters, literals
+		}
+	}
+
+	if best != nil {
+		return best, bestParameters, nil
+	}
+
+	var methods []string
+	for m := range allowed {
+		methods = append(methods, m)
+	}
+	sort.Strings(methods)
+	return nil, nil, methods
+}
+
+// match compares the route's path with the request path. The request path is either the concrete path,
+// or the route template API Gateway matched (e.g. /proposal/{nodeId}), whose parameter values are then
+// taken from the request's path parameters.
+func (route *Route) match(segments []string, pathParameters map[string]string) (map[string]string, int, bool) {
+	if len(segments) != len(route.segments) {
+		return nil, 0, false
+	}
+
+	parameters := make(map[string]string)
+	literals := 0
+	for i, segment := range route.segments {
+		name, isParameter := parameterName(segment)
+		templateName, isTemplate := parameterName(segments[i])
+		switch {
+		case isParameter && isTemplate:
+			value, found := pathParameters[templateName]
+			if !found || value == "" {
+				return nil, 0, false
+			}
+			parameters[name] = value
+		case isParameter:
+			parameters[name] = segments[i]
+		case segment == segments[i]:
+			literals++
+		default:
+			return nil, 0, false
+		}
+	}
+
+	return parameters, literals, true
+}
+
+func pathSegments(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+func parameterName(segment string) (string, bool) {
+	if len(segment) > 2 && strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+		return segment[1 : len(segment)-1], true
+	}
+	return "", false
+}
+
+// routePath returns the path of the request, preferring the route key API Gateway matched (e.g. "GET /proposal")
+func routePath(request events.APIGatewayV2HTTPRequest) string {
+	if _, path, found := strings.Cut(request.RouteKey, " "); found {
+		return path
+	}
+	return request.RawPath
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"context"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/pennsieve/pennsieve-go-core/pkg/authorizer"
+	"github.com/pennsieve/publishing-service/api/service"
+	"testing"
+)
+
+// named returns a handler that responds with its name and the nodeId path parameter
+func named(name string) HandlerFunc {
+	return func(ctx context.Context, request *Request) ([]byte, int) {
+		return []byte(name + ":" + request.PathParameters["nodeId"]), 200
+	}
+}
+
+func testRouter() *Router {
+	r := NewRouter()
+	r.Handle("GET", "/proposal/{nodeId}", nil, named("get"))
+	r.Handle("PUT", "/proposal/{nodeId}", nil, named("update"))
+	r.Handle("POST", "/proposal/submit", nil, named("submit"))
+	r.Handle("POST", "/proposals/{nodeId}/submit", nil, named("submit"))
+	r.HandleDeprecated("POST", "/submit", "/proposals/{nodeId}/submit", nil, named("deprecated"))
+	return r
+}
+
+func TestMatchPrefersLiteralsOverParameters(t *testing.T) {
+	r := testRouter()
+
+	route, parameters, _ := r.Match("POST", "/proposal/submit", nil)
+	if route == nil || route.Path != "/proposal/submit" {
+		t.Fatalf("Match(POST /proposal/submit) = %+v, want the literal route", route)
+	}
+	if len(parameters) != 0 {
+		t.Errorf("parameters = %v, want none", parameters)
+	}
+
+	route, parameters, _ = r.Match("GET", "/proposal/submit", nil)
+	if route == nil || route.Path != "/proposal/{nodeId}" || parameters["nodeId"] != "submit" {
+		t.Errorf("Match(GET /proposal/submit) = %+v, %v, want /proposal/{nodeId} with nodeId=submit", route, parameters)
+	}
+}
+
+func TestMatchRouteKeyTemplate(t *testing.T) {
+	r := testRouter()
+
+	// the route key API Gateway matched, with the parameter values in the request's path parameters
+	route, parameters, _ := r.Match("POST", "/proposals/{nodeId}/submit", map[string]string{"nodeId": "N:proposal:1"})
+	if route == nil || route.Path != "/proposals/{nodeId}/submit" || parameters["nodeId"] != "N:proposal:1" {
+		t.Errorf("Match() = %+v, %v, want /proposals/{nodeId}/submit with nodeId=N:proposal:1", route, parameters)
+	}
+
+	// a template whose parameter has no value does not match
+	if route, _, _ := r.Match("POST", "/proposals/{nodeId}/submit", nil); route != nil {
+		t.Errorf("Match() = %+v, want no route without the nodeId path parameter", route)
+	}
+}
+
+func TestMatchUnknownRouteAndMethod(t *testing.T) {
+	r := testRouter()
+
+	route, _, allowed := r.Match("GET", "/proposals", nil)
+	if route != nil || len(allowed) != 0 {
+		t.Errorf("Match(GET /proposals) = %+v, %v, want no route and no allowed methods", route, allowed)
+	}
+
+	route, _, allowed = r.Match("DELETE", "/proposal/N:proposal:1", nil)
+	if route != nil || len(allowed) != 2 || allowed[0] != "GET" || allowed[1] != "PUT" {
+		t.Errorf("Match(DELETE /proposal/N:proposal:1) = %+v, %v, want no route and GET, PUT allowed", route, allowed)
+	}
+}
+
+// serveTestRoutes routes requests with the test router, to services that are not used by its handlers
+func serveTestRoutes(t *testing.T) {
+	t.Helper()
+	previousRouter, previousProvider := router, provideService
+	t.Cleanup(func() {
+		router, provideService = previousRouter, previousProvider
+	})
+
+	router = testRouter()
+	provideService = func(ctx context.Context, claims *authorizer.Claims) (service.PublishingService, func(), error) {
+		return nil, func() {}, nil
+	}
+}
+
+func testRequest(method string, path string) events.APIGatewayV2HTTPRequest {
+	request := events.APIGatewayV2HTTPRequest{RawPath: path}
+	request.RequestContext.HTTP.Method = method
+	return request
+}
+
+func TestHandleRequestStatuses(t *testing.T) {
+	serveTestRoutes(t)
+
+	tests := []struct {
+		method string
+		path   string
+		status int
+		allow  string
+		body   string
+	}{
+		{"GET", "/proposal/N:proposal:1", 200, "", "get:N:proposal:1"},
+		{"GET", "/proposals", 404, "", ""},
+		{"DELETE", "/proposal/N:proposal:1", 405, "GET, PUT", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.method+" "+test.path, func(t *testing.T) {
+			response, err := handleRequest(context.Background(), testRequest(test.method, test.path))
+			if err != nil {
+				t.Fatalf("handleRequest() error: %v", err)
+			}
+			if response.StatusCode != test.status || response.Headers["allow"] != test.allow || response.Body != test.body {
+				t.Errorf("response = %d %v %q, want %d, allow %q, %q", response.StatusCode, response.Headers, response.Body, test.status, test.allow, test.body)
+			}
+		})
+	}
+}
+
+func TestHandleRequestDeprecatedAlias(t *testing.T) {
+	serveTestRoutes(t)
+
+	response, err := handleRequest(context.Background(), testRequest("POST", "/submit"))
+	if err != nil {
+		t.Fatalf("handleRequest() error: %v", err)
+	}
+	if response.StatusCode != 200 || response.Headers["deprecation"] != "true" || response.Headers["link"] != `</proposals/{nodeId}/submit>; rel="successor-version"` {
+		t.Errorf("response = %d %v, want the deprecation and successor link headers", response.StatusCode, response.Headers)
+	}
+
+	response, _ = handleRequest(context.Background(), testRequest("POST", "/proposal/submit"))
+	if _, found := response.Headers["deprecation"]; found {
+		t.Errorf("headers = %v, want no deprecation header on a current route", response.Headers)
+	}
+}
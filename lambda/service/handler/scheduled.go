@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/pennsieve/pennsieve-go-core/pkg/queries/pgdb"
+	"github.com/pennsieve/publishing-service/api/config"
+	"github.com/pennsieve/publishing-service/api/maintenance"
+	"github.com/pennsieve/publishing-service/api/outbox"
+	"github.com/pennsieve/publishing-service/api/store"
+	"github.com/pennsieve/publishing-service/api/webhook"
+	log "github.com/sirupsen/logrus"
+	"time"
+)
+
+// NewDraftExpiry creates the draft expiry job, backed by DynamoDB and sending reminders with SES
+func NewDraftExpiry(cfg *config.Config, clients *config.Clients) *maintenance.DraftExpiry {
+	return maintenance.NewDraftExpiry(newPublishingStore(cfg, clients), newEmailNotifier(cfg, clients), cfg.DraftExpiry, cfg.PennsieveDomain)
+}
+
+// DraftExpiryHandler runs the draft expiry job on the schedule's EventBridge events
+func DraftExpiryHandler(job *maintenance.DraftExpiry) func(ctx context.Context, event events.CloudWatchEvent) (*maintenance.DraftExpiryResult, error) {
+	return func(ctx context.Context, event events.CloudWatchEvent) (*maintenance.DraftExpiryResult, error) {
+		log.WithFields(log.Fields{"id": event.ID, "time": event.Time, "resources": event.Resources}).Info("handler.DraftExpiryHandler()")
+
+		return job.Run(ctx, eventTime(event))
+	}
+}
+
+// ReviewRemindersHandler runs the review reminders job on the schedule's EventBridge events. Each run
+// connects to the Pennsieve database, to look up the publishers teams and workspace admins; the EmailNotifier,
+// and its template cache, are shared by the runs.
+func ReviewRemindersHandler(cfg *config.Config, clients *config.Clients) func(ctx context.Context, event events.CloudWatchEvent) (*maintenance.ReviewRemindersResult, error) {
+	emailNotifier := newEmailNotifier(cfg, clients)
+
+	return func(ctx context.Context, event events.CloudWatchEvent) (*maintenance.ReviewRemindersResult, error) {
+		log.WithFields(log.Fields{"id": event.ID, "time": event.Time, "resources": event.Resources}).Info("handler.ReviewRemindersHandler()")
+
+		db, err := pgdb.ConnectRDS()
+		if err != nil {
+			return nil, fmt.Errorf("unable to connect to RDS database: %w", err)
+		}
+		defer db.Close()
+
+		pennsieve := store.NewPennsieveStore(ctx, db, 0, cfg.Timeouts.RDS)
+		job := maintenance.NewReviewReminders(newPublishingStore(cfg, clients), pennsieve, emailNotifier, cfg.ReviewSLA, cfg.PennsieveDomain)
+		return job.Run(ctx, eventTime(event))
+	}
+}
+
+// DigestsHandler runs the publisher digests job on the schedule's EventBridge events. Each run
+// connects to the Pennsieve database, to look up the publishers teams; the EmailNotifier, and its template
+// cache, are shared by the runs.
+func DigestsHandler(cfg *config.Config, clients *config.Clients) func(ctx context.Context, event events.CloudWatchEvent) (*maintenance.DigestsResult, error) {
+	emailNotifier := newEmailNotifier(cfg, clients)
+
+	return func(ctx context.Context, event events.CloudWatchEvent) (*maintenance.DigestsResult, error) {
+		log.WithFields(log.Fields{"id": event.ID, "time": event.Time, "resources": event.Resources}).Info("handler.DigestsHandler()")
+
+		db, err := pgdb.ConnectRDS()
+		if err != nil {
+			return nil, fmt.Errorf("unable to connect to RDS database: %w", err)
+		}
+		defer db.Close()
+
+		pennsieve := store.NewPennsieveStore(ctx, db, 0, cfg.Timeouts.RDS)
+		job := maintenance.NewDigests(newPublishingStore(cfg, clients), pennsieve, emailNotifier, cfg.PennsieveDomain)
+		return job.Run(ctx, eventTime(event))
+	}
+}
+
+// NewOutboxDispatcher creates the dispatcher of the notification outbox, backed by DynamoDB and sending
+// notifications, webhook events and lifecycle events the same way as the API
+func NewOutboxDispatcher(cfg *config.Config, clients *config.Clients) (*outbox.Dispatcher, error) {
+	notifier, err := newNotifier(cfg, clients, newEmailNotifier(cfg, clients))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create email notifier: %w", err)
+	}
+	pubStore := newPublishingStore(cfg, clients)
+	return outbox.NewDispatcher(pubStore, notifier, webhook.NewHTTPNotifier(pubStore, cfg.Webhooks), newEventPublisher(cfg, clients), cfg.Outbox), nil
+}
+
+// OutboxDispatcherHandler delivers the due outbox messages on the schedule's EventBridge events
+func OutboxDispatcherHandler(dispatcher *outbox.Dispatcher) func(ctx context.Context, event events.CloudWatchEvent) (*outbox.DispatchResult, error) {
+	return func(ctx context.Context, event events.CloudWatchEvent) (*outbox.DispatchResult, error) {
+		log.WithFields(log.Fields{"id": event.ID, "time": event.Time, "resources": event.Resources}).Info("handler.OutboxDispatcherHandler()")
+
+		return dispatcher.Run(ctx, eventTime(event))
+	}
+}
+
+// eventTime is the time the schedule fired, or now for an event without one (e.g. a manual invocation)
+func eventTime(event events.CloudWatchEvent) time.Time {
+	if event.Time.IsZero() {
+		return time.Now()
+	}
+	return event.Time
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"github.com/pennsieve/pennsieve-go-core/pkg/authorizer"
+	"github.com/pennsieve/pennsieve-go-core/pkg/queries/pgdb"
+	"github.com/pennsieve/publishing-service/api/aws/eventbridge"
+	"github.com/pennsieve/publishing-service/api/aws/s3"
+	"github.com/pennsieve/publishing-service/api/aws/ses"
+	sesTypes "github.com/pennsieve/publishing-service/api/aws/ses/types"
+	"github.com/pennsieve/publishing-service/api/config"
+	"github.com/pennsieve/publishing-service/api/notification"
+	"github.com/pennsieve/publishing-service/api/service"
+	"github.com/pennsieve/publishing-service/api/store"
+	"github.com/pennsieve/publishing-service/api/webhook"
+	log "github.com/sirupsen/logrus"
+)
+
+// ServiceProvider creates the PublishingService that handles a single request. The claims are nil
+// for routes that do not require authorization. The release function is called once the request
+// has been handled.
+type ServiceProvider func(ctx context.Context, claims *authorizer.Claims) (serviceImpl service.PublishingService, release func(), err error)
+
+var provideService ServiceProvider
+
+// Configure sets the configuration and AWS clients shared by all requests; it is called once, at cold start
+func Configure(cfg *config.Config, clients *config.Clients) {
+	provideService = NewAWSServiceProvider(cfg, clients)
+}
+
+// ConfigureServiceProvider replaces the ServiceProvider, e.g. to serve requests from in-memory stores
+func ConfigureServiceProvider(provider ServiceProvider) {
+	provideService = provider
+}
+
+// NewAWSServiceProvider creates PublishingServices backed by DynamoDB, the Pennsieve database and the email-service queue.
+// The notifiers are created once and shared by all requests, so that the EmailNotifier's template cache lasts as long
+// as the function instance does.
+func NewAWSServiceProvider(cfg *config.Config, clients *config.Clients) ServiceProvider {
+	emailNotifier := newEmailNotifier(cfg, clients)
+	notifier, notifierErr := newNotifier(cfg, clients, emailNotifier)
+
+	return func(ctx context.Context, claims *authorizer.Claims) (service.PublishingService, func(), error) {
+		pubStore := newPublishingStore(cfg, clients)
+		options := []service.Option{
+			service.WithPresigner(s3.MakePresigner(clients.S3)),
+			service.WithLogoBaseURL(cfg.Email.LogoBaseURL),
+			service.WithPennsieveDomain(cfg.PennsieveDomain),
+			service.WithOutbox(cfg.Outbox),
+			service.WithWebhooks(webhook.NewHTTPNotifier(pubStore, cfg.Webhooks), cfg.Webhooks),
+			service.WithEvents(newEventPublisher(cfg, clients)),
+			service.WithNotificationMatrix(cfg.Notifications),
+		}
+
+		if claims == nil {
+			return service.NewPublishingService(pubStore, nil, nil, options...), func() {}, nil
+		}
+
+		if notifierErr != nil {
+			return nil, nil, fmt.Errorf("failed to create email notifier: %w", notifierErr)
+		}
+
+		orgId := claims.OrgClaim.IntId
+		db, err := pgdb.ConnectRDSWithOrg(int(orgId))
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to connect to RDS database: %w", err)
+		}
+		log.WithFields(log.Fields{"orgId": orgId, "resource": "database", "action": "connect"}).Info("connected to RDS database")
+
+		pennsieve := store.NewPennsieveStore(ctx, db, orgId, cfg.Timeouts.RDS)
+
+		release := func() {
+			db.Close()
+		}
+		// previews are rendered by the Notifier that sends the notification
+		options = append(options, service.WithPreviewer(notifier))
+		return service.NewPublishingService(pubStore, pennsieve, notifier, options...), release, nil
+	}
+}
+
+// newPublishingStore creates a PublishingStore backed by DynamoDB
+func newPublishingStore(cfg *config.Config, clients *config.Clients) store.PublishingStore {
+	search := store.NewDynamoDBSearchIndex(clients.DynamoDB, cfg.Tables.ProposalSearch)
+	return store.NewPublishingStore(clients.DynamoDB, search, cfg.Tables, cfg.Timeouts.DynamoDB)
+}
+
+// newEventPublisher creates a Publisher that puts Dataset Proposal lifecycle events on the EventBridge event bus
+func newEventPublisher(cfg *config.Config, clients *config.Clients) *eventbridge.Publisher {
+	return eventbridge.MakePublisher(clients.EventBridge, cfg.EventBusName, cfg.Timeouts.EventBridge)
+}
+
+// newEmailNotifier creates a Notifier that renders the email templates in S3 and sends them with SES
+func newEmailNotifier(cfg *config.Config, clients *config.Clients) *notification.EmailNotifier {
+	delivery := sesTypes.PerRecipient
+	if cfg.Email.Delivery == config.EmailDeliveryBCC {
+		delivery = sesTypes.BCC
+	}
+	emailer := ses.MakeEmailer(clients.SES, ses.WithDelivery(delivery), ses.WithConfigurationSet(cfg.Email.ConfigurationSet))
+
+	return notification.NewEmailNotifier(emailer, s3.MakeFileReader(clients.S3), cfg.PennsieveDomain, cfg.EmailTemplates)
+}
+
+// newNotifier creates the Notifier for proposal notifications. Emails are sent via the Pennsieve email-service
+// (enqueue -> consumer renders + delivers), replacing the previous direct-SES EmailNotifier, which remains
+// for the notifications the email-service has no template for, and those branded for a Repository. The QueueNotifier
+// previews the notifications the EmailNotifier sends.
+func newNotifier(cfg *config.Config, clients *config.Clients, emailNotifier *notification.EmailNotifier) (*notification.QueueNotifier, error) {
+	return notification.NewQueueNotifier(clients.SQS, cfg.EmailServiceQueueURL, cfg.Timeouts.SQS, emailNotifier)
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/pennsieve/publishing-service/api/dtos"
+	"github.com/pennsieve/publishing-service/api/service"
+	"github.com/pennsieve/publishing-service/api/store"
+	log "github.com/sirupsen/logrus"
+)
+
+// The Webhooks of a Repository are managed by its publishing team; the Repository is the workspace of the claims
+
+func handleGetWebhooks(ctx context.Context, request *Request) ([]byte, int) {
+	orgNodeId := request.Claims.OrgClaim.NodeId
+	log.WithFields(log.Fields{"orgNodeId": orgNodeId}).Info("handleGetWebhooks()")
+
+	result, err := request.Service.GetWebhooks(ctx, orgNodeId)
+	if err != nil {
+		log.Error("service.GetWebhooks() failed: ", err)
+		return nil, 500
+	}
+
+	return webhookResponse(result, 200)
+}
+
+func handleCreateWebhook(ctx context.Context, request *Request) ([]byte, int) {
+	orgNodeId := request.Claims.OrgClaim.NodeId
+	log.WithFields(log.Fields{"orgNodeId": orgNodeId, "request.body": request.Body}).Info("handleCreateWebhook()")
+
+	var requestDTO dtos.WebhookRequest
+	var validationErr *service.ValidationError
+	if err := decodeBody(request.Body, &requestDTO); errors.As(err, &validationErr) {
+		return validationErrorResponse(validationErr)
+	}
+
+	result, err := request.Service.CreateWebhook(ctx, orgNodeId, request.Claims.UserClaim.Id, requestDTO)
+	if errors.As(err, &validationErr) {
+		return validationErrorResponse(validationErr)
+	}
+	if errors.Is(err, store.ErrNotFound) {
+		// the workspace is not a Repository
+		return nil, 404
+	}
+	if err != nil {
+		log.Error("service.CreateWebhook() failed: ", err)
+		return nil, 500
+	}
+
+	return webhookResponse(result, 201)
+}
+
+func handleUpdateWebhook(ctx context.Context, request *Request) ([]byte, int) {
+	orgNodeId := request.Claims.OrgClaim.NodeId
+	webhookId := request.PathParameters["webhookId"]
+	log.WithFields(log.Fields{"orgNodeId": orgNodeId, "webhookId": webhookId, "request.body": request.Body}).Info("handleUpdateWebhook()")
+
+	var requestDTO dtos.WebhookRequest
+	var validationErr *service.ValidationError
+	if err := decodeBody(request.Body, &requestDTO); errors.As(err, &validationErr) {
+		return validationErrorResponse(validationErr)
+	}
+
+	result, err := request.Service.UpdateWebhook(ctx, orgNodeId, webhookId, requestDTO)
+	if errors.As(err, &validationErr) {
+		return validationErrorResponse(validationErr)
+	}
+	if errors.Is(err, service.ErrWebhookNotFound) {
+		return nil, 404
+	}
+	if err != nil {
+		log.Error("service.UpdateWebhook() failed: ", err)
+		return nil, 500
+	}
+
+	return webhookResponse(result, 200)
+}
+
+func handleDeleteWebhook(ctx context.Context, request *Request) ([]byte, int) {
+	orgNodeId := request.Claims.OrgClaim.NodeId
+	webhookId := request.PathParameters["webhookId"]
+	log.WithFields(log.Fields{"orgNodeId": orgNodeId, "webhookId": webhookId}).Info("handleDeleteWebhook()")
+
+	err := request.Service.DeleteWebhook(ctx, orgNodeId, webhookId)
+	if errors.Is(err, service.ErrWebhookNotFound) {
+		return nil, 404
+	}
+	if err != nil {
+		log.Error("service.DeleteWebhook() failed: ", err)
+		return nil, 500
+	}
+
+	return nil, 200
+}
+
+func handleGetWebhookDeliveries(ctx context.Context, request *Request) ([]byte, int) {
+	orgNodeId := request.Claims.OrgClaim.NodeId
+	webhookId := request.PathParameters["webhookId"]
+	log.WithFields(log.Fields{"orgNodeId": orgNodeId, "webhookId": webhookId}).Info("handleGetWebhookDeliveries()")
+
+	result, err := request.Service.GetWebhookDeliveries(ctx, orgNodeId, webhookId)
+	if errors.Is(err, service.ErrWebhookNotFound) {
+		return nil, 404
+	}
+	if err != nil {
+		log.Error("service.GetWebhookDeliveries() failed: ", err)
+		return nil, 500
+	}
+
+	return webhookResponse(result, 200)
+}
+
+func webhookResponse(result interface{}, statusCode int) ([]byte, int) {
+	jsonBody, err := json.Marshal(result)
+	if err != nil {
+		log.Error("json.Marshal() failed: ", err)
+		return nil, 500
+	}
+
+	return jsonBody, statusCode
+}
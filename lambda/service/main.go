@@ -1,10 +1,26 @@
 package main
 
 import (
+	"context"
 	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/pennsieve/publishing-service/api/config"
 	"github.com/pennsieve/publishing-service/service/handler"
+	log "github.com/sirupsen/logrus"
 )
 
 func main() {
+	// load and validate configuration once, at cold start, so that a misconfigured
+	// function fails to initialize instead of failing on every request
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("invalid publishing-service configuration: %v", err)
+	}
+
+	clients, err := config.NewClients(context.Background(), cfg)
+	if err != nil {
+		log.Fatalf("unable to create AWS clients: %v", err)
+	}
+
+	handler.Configure(cfg, clients)
 	lambda.Start(handler.PublishingServiceHandler)
 }
@@ -19,4 +19,31 @@ resource "aws_cloudwatch_log_subscription_filter" "publishing_service_lambda_dat
 resource "aws_cloudwatch_log_group" "publishing-service-gateway-log-group" {
   name =  "${var.environment_name}/${var.service_name}/publishing-api-gateway"
   retention_in_days = 30
-}
\ No newline at end of file
+}
+// Create log group for the draft expiry Lambda.
+resource "aws_cloudwatch_log_group" "publishing_service_draft_expiry_lambda_log_group" {
+  name              = "/aws/lambda/${aws_lambda_function.draft_expiry_lambda.function_name}"
+  retention_in_days = 30
+  tags = local.common_tags
+}
+
+// Create log group for the review reminders Lambda.
+resource "aws_cloudwatch_log_group" "publishing_service_review_reminders_lambda_log_group" {
+  name              = "/aws/lambda/${aws_lambda_function.review_reminders_lambda.function_name}"
+  retention_in_days = 30
+  tags = local.common_tags
+}
+
+// Create log group for the publisher digests Lambda.
+resource "aws_cloudwatch_log_group" "publishing_service_publisher_digests_lambda_log_group" {
+  name              = "/aws/lambda/${aws_lambda_function.publisher_digests_lambda.function_name}"
+  retention_in_days = 30
+  tags = local.common_tags
+}
+
+// Create log group for the outbox dispatcher Lambda.
+resource "aws_cloudwatch_log_group" "publishing_service_outbox_dispatcher_lambda_log_group" {
+  name              = "/aws/lambda/${aws_lambda_function.outbox_dispatcher_lambda.function_name}"
+  retention_in_days = 30
+  tags = local.common_tags
+}
@@ -2,6 +2,11 @@ data "aws_caller_identity" "current" {}
 
 data "aws_region" "current_region" {}
 
+# Lifecycle events are published to the account's default event bus
+data "aws_cloudwatch_event_bus" "default" {
+  name = "default"
+}
+
 # Import Account Data
 data "terraform_remote_state" "account" {
   backend = "s3"
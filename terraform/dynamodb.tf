@@ -121,6 +121,18 @@ resource "aws_dynamodb_table" "dataset_proposals_dynamo_table" {
     projection_type    = "ALL"
   }
 
+  global_secondary_index {
+    name               = "ProposalNodeIdIndex"
+    hash_key           = "NodeId"
+    projection_type    = "ALL"
+  }
+
+  # archived drafts are removed by DynamoDB once their ExpiresAt (epoch seconds) has passed
+  ttl {
+    attribute_name = "ExpiresAt"
+    enabled        = true
+  }
+
   point_in_time_recovery {
     enabled = true
   }
@@ -138,4 +150,191 @@ resource "aws_dynamodb_table" "dataset_proposals_dynamo_table" {
     },
   )
 
-}
\ No newline at end of file
+}
+
+resource "aws_dynamodb_table" "proposal_search_dynamo_table" {
+  name           = "${var.environment_name}-proposal-search-${data.terraform_remote_state.region.outputs.aws_region_shortname}"
+  billing_mode   = "PAY_PER_REQUEST"
+  hash_key       = "OrganizationNodeId"
+  range_key      = "NodeId"
+
+  attribute {
+    name = "OrganizationNodeId"
+    type = "S"
+  }
+
+  attribute {
+    name = "NodeId"
+    type = "S"
+  }
+
+  point_in_time_recovery {
+    enabled = true
+  }
+
+  server_side_encryption {
+    enabled = true
+  }
+
+  tags = merge(
+    local.common_tags,
+    {
+      "Name"         = "${var.environment_name}-proposal-search-${data.terraform_remote_state.region.outputs.aws_region_shortname}"
+      "name"         = "${var.environment_name}-proposal-search-${data.terraform_remote_state.region.outputs.aws_region_shortname}"
+      "service_name" = var.service_name
+    },
+  )
+}
+resource "aws_dynamodb_table" "notification_preferences_dynamo_table" {
+  name           = "${var.environment_name}-notification-preferences-${data.terraform_remote_state.region.outputs.aws_region_shortname}"
+  billing_mode   = "PAY_PER_REQUEST"
+  hash_key       = "UserId"
+
+  attribute {
+    name = "UserId"
+    type = "N"
+  }
+
+  point_in_time_recovery {
+    enabled = true
+  }
+
+  server_side_encryption {
+    enabled = true
+  }
+
+  tags = merge(
+    local.common_tags,
+    {
+      "Name"         = "${var.environment_name}-notification-preferences-${data.terraform_remote_state.region.outputs.aws_region_shortname}"
+      "name"         = "${var.environment_name}-notification-preferences-${data.terraform_remote_state.region.outputs.aws_region_shortname}"
+      "service_name" = var.service_name
+    },
+  )
+}
+
+resource "aws_dynamodb_table" "notification_outbox_dynamo_table" {
+  name           = "${var.environment_name}-notification-outbox-${data.terraform_remote_state.region.outputs.aws_region_shortname}"
+  billing_mode   = "PAY_PER_REQUEST"
+  hash_key       = "Id"
+
+  attribute {
+    name = "Id"
+    type = "S"
+  }
+
+  attribute {
+    name = "Status"
+    type = "S"
+  }
+
+  attribute {
+    name = "NextAttemptAt"
+    type = "N"
+  }
+
+  global_secondary_index {
+    name               = "OutboxStatusIndex"
+    hash_key           = "Status"
+    range_key          = "NextAttemptAt"
+    projection_type    = "ALL"
+  }
+
+  # delivered messages are removed by DynamoDB once their ExpiresAt (epoch seconds) has passed;
+  # dead messages have no ExpiresAt, and are kept as the dead-letter record
+  ttl {
+    attribute_name = "ExpiresAt"
+    enabled        = true
+  }
+
+  point_in_time_recovery {
+    enabled = true
+  }
+
+  server_side_encryption {
+    enabled = true
+  }
+
+  tags = merge(
+    local.common_tags,
+    {
+      "Name"         = "${var.environment_name}-notification-outbox-${data.terraform_remote_state.region.outputs.aws_region_shortname}"
+      "name"         = "${var.environment_name}-notification-outbox-${data.terraform_remote_state.region.outputs.aws_region_shortname}"
+      "service_name" = var.service_name
+    },
+  )
+}
+
+resource "aws_dynamodb_table" "webhooks_dynamo_table" {
+  name           = "${var.environment_name}-webhooks-${data.terraform_remote_state.region.outputs.aws_region_shortname}"
+  billing_mode   = "PAY_PER_REQUEST"
+  hash_key       = "OrganizationNodeId"
+  range_key      = "Id"
+
+  attribute {
+    name = "OrganizationNodeId"
+    type = "S"
+  }
+
+  attribute {
+    name = "Id"
+    type = "S"
+  }
+
+  point_in_time_recovery {
+    enabled = true
+  }
+
+  server_side_encryption {
+    enabled = true
+  }
+
+  tags = merge(
+    local.common_tags,
+    {
+      "Name"         = "${var.environment_name}-webhooks-${data.terraform_remote_state.region.outputs.aws_region_shortname}"
+      "name"         = "${var.environment_name}-webhooks-${data.terraform_remote_state.region.outputs.aws_region_shortname}"
+      "service_name" = var.service_name
+    },
+  )
+}
+
+resource "aws_dynamodb_table" "webhook_deliveries_dynamo_table" {
+  name           = "${var.environment_name}-webhook-deliveries-${data.terraform_remote_state.region.outputs.aws_region_shortname}"
+  billing_mode   = "PAY_PER_REQUEST"
+  hash_key       = "WebhookId"
+  range_key      = "Id"
+
+  attribute {
+    name = "WebhookId"
+    type = "S"
+  }
+
+  attribute {
+    name = "Id"
+    type = "S"
+  }
+
+  # delivery attempts are removed by DynamoDB once their ExpiresAt (epoch seconds) has passed
+  ttl {
+    attribute_name = "ExpiresAt"
+    enabled        = true
+  }
+
+  point_in_time_recovery {
+    enabled = true
+  }
+
+  server_side_encryption {
+    enabled = true
+  }
+
+  tags = merge(
+    local.common_tags,
+    {
+      "Name"         = "${var.environment_name}-webhook-deliveries-${data.terraform_remote_state.region.outputs.aws_region_shortname}"
+      "name"         = "${var.environment_name}-webhook-deliveries-${data.terraform_remote_state.region.outputs.aws_region_shortname}"
+      "service_name" = var.service_name
+    },
+  )
+}
@@ -0,0 +1,14 @@
+# Registry of the schemas of the events published by the Publishing Service, so that consumers can
+# discover them and generate bindings. A new schema version is added alongside the previous ones.
+resource "aws_schemas_registry" "publishing_service_events" {
+  name        = "${var.environment_name}-${var.service_name}-events-${data.terraform_remote_state.region.outputs.aws_region_shortname}"
+  description = "Events published by the Publishing Service"
+}
+
+resource "aws_schemas_schema" "proposal_event_v1" {
+  name          = "pennsieve.publishing-service@ProposalEvent-v1"
+  registry_name = aws_schemas_registry.publishing_service_events.name
+  type          = "JSONSchemaDraft4"
+  description   = "Dataset Proposal lifecycle events (ProposalCreated, ProposalSubmitted, ProposalWithdrawn, ProposalAccepted, ProposalRejected), schema version 1"
+  content       = file("${path.module}/../api/events/schema/proposal-event-v1.json")
+}
@@ -66,6 +66,7 @@ data "aws_iam_policy_document" "publishing_service_iam_policy_document" {
       "dynamodb:BatchGetItem",
       "dynamodb:GetItem",
       "dynamodb:PutItem",
+      "dynamodb:UpdateItem",
       "dynamodb:DeleteItem",
       "dynamodb:Query",
       "dynamodb:Scan",
@@ -80,7 +81,17 @@ data "aws_iam_policy_document" "publishing_service_iam_policy_document" {
       aws_dynamodb_table.repository_questions_dynamo_table.arn,
       "${aws_dynamodb_table.repository_questions_dynamo_table.arn}/*",
       aws_dynamodb_table.dataset_proposals_dynamo_table.arn,
-      "${aws_dynamodb_table.dataset_proposals_dynamo_table.arn}/*"
+      "${aws_dynamodb_table.dataset_proposals_dynamo_table.arn}/*",
+      aws_dynamodb_table.proposal_search_dynamo_table.arn,
+      "${aws_dynamodb_table.proposal_search_dynamo_table.arn}/*",
+      aws_dynamodb_table.notification_preferences_dynamo_table.arn,
+      "${aws_dynamodb_table.notification_preferences_dynamo_table.arn}/*",
+      aws_dynamodb_table.notification_outbox_dynamo_table.arn,
+      "${aws_dynamodb_table.notification_outbox_dynamo_table.arn}/*",
+      aws_dynamodb_table.webhooks_dynamo_table.arn,
+      "${aws_dynamodb_table.webhooks_dynamo_table.arn}/*",
+      aws_dynamodb_table.webhook_deliveries_dynamo_table.arn,
+      "${aws_dynamodb_table.webhook_deliveries_dynamo_table.arn}/*"
     ]
 
   }
@@ -129,6 +140,16 @@ data "aws_iam_policy_document" "publishing_service_iam_policy_document" {
     resources = [data.terraform_remote_state.email_service.outputs.email_service_queue_arn]
   }
 
+  # Publish Dataset Proposal lifecycle events.
+  statement {
+    sid    = "PublishingServiceEventBridgePermissions"
+    effect = "Allow"
+    actions = [
+      "events:PutEvents",
+    ]
+    resources = [data.aws_cloudwatch_event_bus.default.arn]
+  }
+
   # The send queue is KMS-encrypted; sending requires use of its key.
   statement {
     sid    = "PublishingServiceEmailServiceKMSPermissions"
@@ -1,3 +1,63 @@
+locals {
+  # configuration shared by the API and scheduled Lambda functions (see api/config)
+  service_environment = {
+    ENV = var.environment_name
+    PENNSIEVE_DOMAIN = data.terraform_remote_state.account.outputs.domain_name
+    REGION = var.aws_region
+    PUBLISHING_INFO_TABLE = aws_dynamodb_table.publishing_info_dynamo_table.name
+    REPOSITORIES_TABLE = aws_dynamodb_table.repositories_dynamo_table.name
+    REPOSITORY_QUESTIONS_TABLE = aws_dynamodb_table.repository_questions_dynamo_table.name
+    DATASET_PROPOSAL_TABLE = aws_dynamodb_table.dataset_proposals_dynamo_table.name
+    PROPOSAL_SEARCH_TABLE = aws_dynamodb_table.proposal_search_dynamo_table.name
+    NOTIFICATION_PREFERENCES_TABLE = aws_dynamodb_table.notification_preferences_dynamo_table.name
+    NOTIFICATION_OUTBOX_TABLE = aws_dynamodb_table.notification_outbox_dynamo_table.name
+    WEBHOOKS_TABLE = aws_dynamodb_table.webhooks_dynamo_table.name
+    WEBHOOK_DELIVERIES_TABLE = aws_dynamodb_table.webhook_deliveries_dynamo_table.name
+    RDS_PROXY_ENDPOINT        = data.terraform_remote_state.pennsieve_postgres.outputs.rds_proxy_endpoint
+    EMAIL_TEMPLATE_BUCKET  = data.terraform_remote_state.platform_infrastructure.outputs.dataset_assets_bucket_id
+    # emails to several recipients are sent to each separately ("per-recipient"), or blind-copied ("bcc");
+    # set SES_CONFIGURATION_SET to send them with an SES configuration set
+    EMAIL_DELIVERY = "per-recipient"
+    # repositories' logos are linked in branded emails by their S3 key under EMAIL_LOGO_BASE_URL, a public URL
+    # (e.g. CloudFront) that does not expire; without it, emails keep the Pennsieve logo
+    EMAIL_LOGO_BASE_URL = var.email_logo_base_url
+    # email templates are cached, and read again from the bucket after EMAIL_TEMPLATE_CACHE_TTL
+    EMAIL_TEMPLATE_CACHE_TTL = "15m"
+    EMAIL_TEMPLATE_SUBMITTED = "PublishingService/EmailTemplates/dataset-proposal-submitted.html"
+    EMAIL_TEMPLATE_WITHDRAWN = "PublishingService/EmailTemplates/dataset-proposal-withdrawn.html"
+    EMAIL_TEMPLATE_ACCEPTED = "PublishingService/EmailTemplates/dataset-proposal-accepted.html"
+    EMAIL_TEMPLATE_REJECTED = "PublishingService/EmailTemplates/dataset-proposal-rejected.html"
+    EMAIL_TEMPLATE_DRAFT_REMINDER = "PublishingService/EmailTemplates/dataset-proposal-draft-reminder.html"
+    EMAIL_TEMPLATE_REVIEW_REMINDER = "PublishingService/EmailTemplates/dataset-proposal-review-reminder.html"
+    EMAIL_TEMPLATE_REVIEW_ESCALATION = "PublishingService/EmailTemplates/dataset-proposal-review-escalation.html"
+    EMAIL_TEMPLATE_DIGEST = "PublishingService/EmailTemplates/dataset-proposal-digest.html"
+    EMAIL_TEMPLATE_SUBMITTED_RECEIPT = "PublishingService/EmailTemplates/dataset-proposal-submitted-receipt.html"
+    EMAIL_TEMPLATE_WITHDRAWN_RECEIPT = "PublishingService/EmailTemplates/dataset-proposal-withdrawn-receipt.html"
+    EMAIL_TEMPLATE_CONTRIBUTOR_ACCEPTED = "PublishingService/EmailTemplates/dataset-proposal-contributor-accepted.html"
+    EMAIL_TEMPLATE_ADMIN_COPY = "PublishingService/EmailTemplates/dataset-proposal-admin-copy.html"
+    # who is notified of each proposal transition; audiences are publishers, author, contributors and admins
+    NOTIFICATION_MATRIX = "submitted=publishers,author;withdrawn=publishers,author;accepted=author,contributors;rejected=author"
+    # per-call timeouts for requests to backing services
+    DYNAMODB_TIMEOUT = "10s"
+    RDS_TIMEOUT = "30s"
+    SQS_TIMEOUT = "10s"
+    EVENTBRIDGE_TIMEOUT = "10s"
+    # email-service send queue — QueueNotifier enqueues here instead of SES.
+    EMAIL_SERVICE_QUEUE_URL = data.terraform_remote_state.email_service.outputs.email_service_queue_url
+    # Dataset Proposal lifecycle events (api/events) are published here, with source pennsieve.publishing-service
+    EVENT_BUS_NAME = data.aws_cloudwatch_event_bus.default.name
+    # drafts untouched for DRAFT_REMINDER_AFTER are sent a reminder, then expired DRAFT_EXPIRY_GRACE later
+    DRAFT_REMINDER_AFTER = "1440h"
+    DRAFT_EXPIRY_GRACE = "336h"
+    DRAFT_EXPIRY_ACTION = "archive"
+    DRAFT_ARCHIVE_RETENTION = "2160h"
+    # publishers are reminded of proposals awaiting review after REVIEW_REMINDER_AFTER, and workspace
+    # admins after REVIEW_ESCALATE_AFTER; a repository's ReviewSLA overrides both
+    REVIEW_REMINDER_AFTER = "336h"
+    REVIEW_ESCALATE_AFTER = "720h"
+  }
+}
+
 resource "aws_lambda_function" "service_lambda" {
   description       = "Lambda Function which handles requests for the serverless Publishing Service"
   function_name     = "${var.environment_name}-${var.service_name}-service-lambda-${data.terraform_remote_state.region.outputs.aws_region_shortname}"
@@ -15,22 +75,174 @@ resource "aws_lambda_function" "service_lambda" {
   }
 
   environment {
-    variables = {
-      ENV = var.environment_name
-      PENNSIEVE_DOMAIN = data.terraform_remote_state.account.outputs.domain_name
-      REGION = var.aws_region
-      PUBLISHING_INFO_TABLE = aws_dynamodb_table.publishing_info_dynamo_table.name
-      REPOSITORIES_TABLE = aws_dynamodb_table.repositories_dynamo_table.name
-      REPOSITORY_QUESTIONS_TABLE = aws_dynamodb_table.repository_questions_dynamo_table.name
-      DATASET_PROPOSAL_TABLE = aws_dynamodb_table.dataset_proposals_dynamo_table.name
-      RDS_PROXY_ENDPOINT        = data.terraform_remote_state.pennsieve_postgres.outputs.rds_proxy_endpoint
-      EMAIL_TEMPLATE_BUCKET  = data.terraform_remote_state.platform_infrastructure.outputs.dataset_assets_bucket_id
-      EMAIL_TEMPLATE_SUBMITTED = "PublishingService/EmailTemplates/dataset-proposal-submitted.html"
-      EMAIL_TEMPLATE_WITHDRAWN = "PublishingService/EmailTemplates/dataset-proposal-withdrawn.html"
-      EMAIL_TEMPLATE_ACCEPTED = "PublishingService/EmailTemplates/dataset-proposal-accepted.html"
-      EMAIL_TEMPLATE_REJECTED = "PublishingService/EmailTemplates/dataset-proposal-rejected.html"
-      # email-service send queue — QueueNotifier enqueues here instead of SES.
-      EMAIL_SERVICE_QUEUE_URL = data.terraform_remote_state.email_service.outputs.email_service_queue_url
-    }
+    variables = local.service_environment
+  }
+}
+
+# Scheduled Lambda Function which reminds authors of abandoned drafts, and archives or deletes them
+resource "aws_lambda_function" "draft_expiry_lambda" {
+  description       = "Lambda Function which expires abandoned draft Dataset Proposals"
+  function_name     = "${var.environment_name}-${var.service_name}-draft-expiry-lambda-${data.terraform_remote_state.region.outputs.aws_region_shortname}"
+  handler           = "draft_expiry"
+  runtime           = "go1.x"
+  role              = aws_iam_role.publishing_service_lambda_role.arn
+  timeout           = 900
+  memory_size       = 128
+  s3_bucket         = var.lambda_bucket
+  s3_key            = "${var.service_name}/${var.service_name}-${var.image_tag}.zip"
+
+  vpc_config {
+    subnet_ids         = tolist(data.terraform_remote_state.vpc.outputs.private_subnet_ids)
+    security_group_ids = [data.terraform_remote_state.platform_infrastructure.outputs.upload_v2_security_group_id]
+  }
+
+  environment {
+    variables = local.service_environment
+  }
+}
+
+resource "aws_cloudwatch_event_rule" "draft_expiry_schedule" {
+  name                = "${var.environment_name}-${var.service_name}-draft-expiry-${data.terraform_remote_state.region.outputs.aws_region_shortname}"
+  description         = "Runs the Publishing Service draft expiry job daily"
+  schedule_expression = "cron(0 6 * * ? *)"
+  tags                = local.common_tags
+}
+
+resource "aws_cloudwatch_event_target" "draft_expiry_target" {
+  rule = aws_cloudwatch_event_rule.draft_expiry_schedule.name
+  arn  = aws_lambda_function.draft_expiry_lambda.arn
+}
+
+resource "aws_lambda_permission" "draft_expiry_schedule_permission" {
+  statement_id  = "AllowExecutionFromEventBridge"
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.draft_expiry_lambda.function_name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.draft_expiry_schedule.arn
+}
+
+# Scheduled Lambda Function which reminds publishers of proposals awaiting review, and escalates to workspace admins
+resource "aws_lambda_function" "review_reminders_lambda" {
+  description       = "Lambda Function which sends reminders of Dataset Proposals awaiting review"
+  function_name     = "${var.environment_name}-${var.service_name}-review-reminders-lambda-${data.terraform_remote_state.region.outputs.aws_region_shortname}"
+  handler           = "review_reminders"
+  runtime           = "go1.x"
+  role              = aws_iam_role.publishing_service_lambda_role.arn
+  timeout           = 900
+  memory_size       = 128
+  s3_bucket         = var.lambda_bucket
+  s3_key            = "${var.service_name}/${var.service_name}-${var.image_tag}.zip"
+
+  vpc_config {
+    subnet_ids         = tolist(data.terraform_remote_state.vpc.outputs.private_subnet_ids)
+    security_group_ids = [data.terraform_remote_state.platform_infrastructure.outputs.upload_v2_security_group_id]
+  }
+
+  environment {
+    variables = local.service_environment
+  }
+}
+
+resource "aws_cloudwatch_event_rule" "review_reminders_schedule" {
+  name                = "${var.environment_name}-${var.service_name}-review-reminders-${data.terraform_remote_state.region.outputs.aws_region_shortname}"
+  description         = "Runs the Publishing Service review reminders job on weekdays"
+  schedule_expression = "cron(0 13 ? * MON-FRI *)"
+  tags                = local.common_tags
+}
+
+resource "aws_cloudwatch_event_target" "review_reminders_target" {
+  rule = aws_cloudwatch_event_rule.review_reminders_schedule.name
+  arn  = aws_lambda_function.review_reminders_lambda.arn
+}
+
+resource "aws_lambda_permission" "review_reminders_schedule_permission" {
+  statement_id  = "AllowExecutionFromEventBridge"
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.review_reminders_lambda.function_name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.review_reminders_schedule.arn
+}
+
+# Scheduled Lambda Function which sends publishers their daily or weekly digest of Dataset Proposals
+resource "aws_lambda_function" "publisher_digests_lambda" {
+  description       = "Lambda Function which sends publishers their Dataset Proposal digests"
+  function_name     = "${var.environment_name}-${var.service_name}-publisher-digests-lambda-${data.terraform_remote_state.region.outputs.aws_region_shortname}"
+  handler           = "publisher_digests"
+  runtime           = "go1.x"
+  role              = aws_iam_role.publishing_service_lambda_role.arn
+  timeout           = 900
+  memory_size       = 128
+  s3_bucket         = var.lambda_bucket
+  s3_key            = "${var.service_name}/${var.service_name}-${var.image_tag}.zip"
+
+  vpc_config {
+    subnet_ids         = tolist(data.terraform_remote_state.vpc.outputs.private_subnet_ids)
+    security_group_ids = [data.terraform_remote_state.platform_infrastructure.outputs.upload_v2_security_group_id]
+  }
+
+  environment {
+    variables = local.service_environment
+  }
+}
+
+resource "aws_cloudwatch_event_rule" "publisher_digests_schedule" {
+  name                = "${var.environment_name}-${var.service_name}-publisher-digests-${data.terraform_remote_state.region.outputs.aws_region_shortname}"
+  description         = "Runs the Publishing Service publisher digests job daily; weekly digests are sent every seventh run"
+  schedule_expression = "cron(0 12 * * ? *)"
+  tags                = local.common_tags
+}
+
+resource "aws_cloudwatch_event_target" "publisher_digests_target" {
+  rule = aws_cloudwatch_event_rule.publisher_digests_schedule.name
+  arn  = aws_lambda_function.publisher_digests_lambda.arn
+}
+
+resource "aws_lambda_permission" "publisher_digests_schedule_permission" {
+  statement_id  = "AllowExecutionFromEventBridge"
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.publisher_digests_lambda.function_name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.publisher_digests_schedule.arn
+}
+
+# Scheduled Lambda Function which retries the notifications in the outbox that have not been delivered
+resource "aws_lambda_function" "outbox_dispatcher_lambda" {
+  description       = "Lambda Function which delivers the Publishing Service notification outbox"
+  function_name     = "${var.environment_name}-${var.service_name}-outbox-dispatcher-lambda-${data.terraform_remote_state.region.outputs.aws_region_shortname}"
+  handler           = "outbox_dispatcher"
+  runtime           = "go1.x"
+  role              = aws_iam_role.publishing_service_lambda_role.arn
+  timeout           = 300
+  memory_size       = 128
+  s3_bucket         = var.lambda_bucket
+  s3_key            = "${var.service_name}/${var.service_name}-${var.image_tag}.zip"
+
+  vpc_config {
+    subnet_ids         = tolist(data.terraform_remote_state.vpc.outputs.private_subnet_ids)
+    security_group_ids = [data.terraform_remote_state.platform_infrastructure.outputs.upload_v2_security_group_id]
+  }
+
+  environment {
+    variables = local.service_environment
   }
 }
+
+resource "aws_cloudwatch_event_rule" "outbox_dispatcher_schedule" {
+  name                = "${var.environment_name}-${var.service_name}-outbox-dispatcher-${data.terraform_remote_state.region.outputs.aws_region_shortname}"
+  description         = "Runs the Publishing Service outbox dispatcher every five minutes"
+  schedule_expression = "rate(5 minutes)"
+  tags                = local.common_tags
+}
+
+resource "aws_cloudwatch_event_target" "outbox_dispatcher_target" {
+  rule = aws_cloudwatch_event_rule.outbox_dispatcher_schedule.name
+  arn  = aws_lambda_function.outbox_dispatcher_lambda.arn
+}
+
+resource "aws_lambda_permission" "outbox_dispatcher_schedule_permission" {
+  statement_id  = "AllowExecutionFromEventBridge"
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.outbox_dispatcher_lambda.function_name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.outbox_dispatcher_schedule.arn
+}
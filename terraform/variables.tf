@@ -25,3 +25,7 @@ locals {
     environment_name = var.environment_name
   }
 }
+
+variable "email_logo_base_url" {
+  default = ""
+}